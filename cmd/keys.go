@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+)
+
+// generateSigningKey writes a new private key named after the current time to the signing keys
+// directory, so it sorts after the existing keys and signs new tokens once the server restarts
+func generateSigningKey(token config.TokenConfig, args []string) error {
+	algorithm := "eddsa"
+	if len(args) > 0 {
+		algorithm = args[0]
+	}
+
+	dir := token.SigningKeysDir
+	if dir == "" {
+		return fmt.Errorf("token.signing_keys_dir (TOKEN_SIGNING_KEYS_DIR) has to be set")
+	}
+
+	key, err := services.GenerateSigningKey(algorithm)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	file := filepath.Join(dir, time.Now().UTC().Format("20060102150405")+".pem")
+	if err := os.WriteFile(file, key, 0o600); err != nil {
+		return err
+	}
+
+	fmt.Println("Created", file)
+	return nil
+}
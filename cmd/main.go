@@ -5,17 +5,19 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/mail"
+	"github.com/stivo-m/vise-resume/internal/config"
 	"github.com/stivo-m/vise-resume/internal/core/services"
 	"github.com/stivo-m/vise-resume/internal/core/utils"
 )
 
-func setupPostmanCollections(app *fiber.App, port int) {
+func setupPostmanCollections(app *fiber.App, server config.ServerConfig) {
 	// Generate the Postman collection
-	collection := utils.GeneratePostmanCollection(app, port)
+	collection := utils.GeneratePostmanCollection(app, server.URL, server.Port)
 
 	// Write the collection to a file
 	file, err := os.Create("postman_collection.json")
@@ -35,17 +37,62 @@ func setupPostmanCollections(app *fiber.App, port int) {
 }
 
 func main() {
-	db, err := database.NewDatabase()
+	// Create migrations, which does not need a database connection
+	if len(os.Args) > 1 && os.Args[1] == "migrations:create" {
+		if err := createMigration(os.Args[2:]); err != nil {
+			log.Fatalf("unable to create migration: %v", err)
+		}
+		return
+	}
+
+	cfg, err := config.Load(config.DefaultOptions())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Generate token signing keys, which does not need a database connection either
+	if len(os.Args) > 1 && os.Args[1] == "keys:generate" {
+		if err := generateSigningKey(cfg.Token, os.Args[2:]); err != nil {
+			log.Fatalf("unable to generate signing key: %v", err)
+		}
+		return
+	}
+
+	db, err := database.NewDatabase(cfg.Database)
 	if err != nil {
 		log.Panicf("unable to connect to the database: %v", err)
 	}
 
-	port, err := strconv.Atoi(os.Getenv("SERVER_PORT"))
+	// Run, revert or inspect migrations
+	if len(os.Args) > 1 && strings.HasPrefix(os.Args[1], "migrations:") {
+		if err := runMigrations(db, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatalf("migrations failed: %v", err)
+		}
+		return
+	}
+
+	// Grant a role to a user, for instance to create the first administrator
+	if len(os.Args) > 1 && os.Args[1] == "users:grant-role" {
+		if err := grantRole(db, os.Args[2:]); err != nil {
+			log.Fatalf("unable to grant role: %v", err)
+		}
+		return
+	}
+
+	// Erase the accounts whose deletion grace period has passed
+	if len(os.Args) > 1 && os.Args[1] == "users:purge-deleted" {
+		if err := purgeDeletedUsers(db, cfg); err != nil {
+			log.Fatalf("unable to purge deleted users: %v", err)
+		}
+		return
+	}
+
+	mailer, err := mail.NewMailerFromConfig(cfg.Mail)
 	if err != nil {
-		log.Panicf("unable to parse SERVER_PORT: %v", err)
+		log.Panicf("unable to setup the mailer: %v", err)
 	}
 
-	server := services.NewServer(db)
+	server := services.NewServer(cfg, db, mailer)
 	app, err := server.PrepareServer()
 
 	if err != nil {
@@ -60,15 +107,9 @@ func main() {
 
 	// Generate postman collections
 	if len(os.Args) > 1 && os.Args[1] == "generate:postman" {
-		setupPostmanCollections(app, port)
-		return
-	}
-
-	// Run migrations
-	if len(os.Args) > 1 && os.Args[1] == "migrations:run" {
-		db.AutoMigrate()
+		setupPostmanCollections(app, cfg.Server)
 		return
 	}
 
-	log.Fatal(app.Listen(fmt.Sprintf(":%d", port)))
+	log.Fatal(app.Listen(fmt.Sprintf(":%d", cfg.Server.Port)))
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/database/migrations"
+)
+
+func createMigration(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: migrations:create <name>")
+	}
+
+	files, err := migrations.Create(migrations.SourceDir, args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		fmt.Println("Created", file)
+	}
+
+	return nil
+}
+
+func runMigrations(db *database.DB, command string, args []string) error {
+	ctx := context.Background()
+	migrator, err := migrations.NewMigrator(db.Db)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "migrations:status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, status := range statuses {
+			applied := "pending"
+			if status.AppliedAt != nil {
+				applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", status.Version, status.Name, applied)
+		}
+
+	case "migrations:up":
+		ran, err := migrator.Up(ctx)
+		for _, migration := range ran {
+			fmt.Printf("Applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(ran) == 0 {
+			fmt.Println("Nothing to migrate")
+		}
+
+	case "migrations:down":
+		steps := 1
+		if len(args) > 0 {
+			steps, err = strconv.Atoi(args[0])
+			if err != nil {
+				return fmt.Errorf("usage: migrations:down [N]")
+			}
+		}
+
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("Reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+	default:
+		return fmt.Errorf("unknown command %s", command)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/database/repository"
+	"github.com/stivo-m/vise-resume/internal/adapters/mail"
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+)
+
+// grantRole gives a role to the user with the email address, which is how the first
+// administrator is created before anyone can use the admin API
+func grantRole(db *database.DB, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: users:grant-role <email> <role>")
+	}
+
+	ctx := context.Background()
+	user, err := repository.NewUserRepository(db).FindUser(ctx, dto.FindUserDto{Email: args[0]})
+	if err != nil {
+		return fmt.Errorf("user %s not found", args[0])
+	}
+
+	roleRepo := repository.NewRoleRepository(db)
+	role, err := roleRepo.FindRole(ctx, args[1])
+	if err != nil {
+		return fmt.Errorf("role %s not found", args[1])
+	}
+
+	if err := roleRepo.AssignRole(ctx, user.ID, role.ID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Granted %s to %s\n", role.Name, user.Email)
+	return nil
+}
+
+// purgeDeletedUsers erases the accounts whose deletion grace period has passed. It is meant
+// to be run on a schedule, for instance once a day from cron.
+func purgeDeletedUsers(db *database.DB, cfg *config.Config) error {
+	mailer, err := mail.NewMailerFromConfig(cfg.Mail)
+	if err != nil {
+		return err
+	}
+
+	verificationRepo := repository.NewVerificationRepository(db)
+	accountService := services.NewAccountService(
+		db,
+		repository.NewUserRepository(db),
+		repository.NewResumeRepository(db),
+		verificationRepo,
+		services.NewVerificationService(verificationRepo, services.NewVerificationPoliciesFromConfig(cfg.Verification)),
+		services.NewPasswordService(),
+		repository.NewAuditRepository(db),
+		mailer,
+	)
+
+	purged, err := accountService.PurgeDeletedAccounts(context.Background())
+	fmt.Printf("Purged %d deleted accounts\n", purged)
+	return err
+}
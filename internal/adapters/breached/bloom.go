@@ -0,0 +1,61 @@
+package breached
+
+import (
+	"hash/fnv"
+	"math"
+)
+
+// BloomFilter is a compact set that can tell for certain that a value was never added,
+// and that a value was added with a small chance of false positives. It keeps large
+// password lists in memory for a fraction of their size.
+type BloomFilter struct {
+	bits   []uint64
+	size   uint64
+	hashes uint64
+}
+
+// NewBloomFilter sizes a filter for the expected number of values and false positive rate
+func NewBloomFilter(expected int, falsePositiveRate float64) *BloomFilter {
+	n := math.Max(float64(expected), 1)
+	size := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	hashes := math.Max(math.Round(size/n*math.Ln2), 1)
+
+	return &BloomFilter{
+		bits:   make([]uint64, (uint64(size)+63)/64),
+		size:   uint64(size),
+		hashes: uint64(hashes),
+	}
+}
+
+func (f *BloomFilter) Add(value string) {
+	first, second := f.hash(value)
+	for i := uint64(0); i < f.hashes; i++ {
+		position := (first + i*second) % f.size
+		f.bits[position/64] |= 1 << (position % 64)
+	}
+}
+
+// Test reports whether the value may have been added
+func (f *BloomFilter) Test(value string) bool {
+	first, second := f.hash(value)
+	for i := uint64(0); i < f.hashes; i++ {
+		position := (first + i*second) % f.size
+		if f.bits[position/64]&(1<<(position%64)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hash derives the two hashes the positions of a value are computed from (Kirsch-Mitzenmacher)
+func (f *BloomFilter) hash(value string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	first := h.Sum64()
+
+	h.Write([]byte{0})
+	second := h.Sum64() | 1
+
+	return first, second
+}
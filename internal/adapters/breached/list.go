@@ -0,0 +1,66 @@
+package breached
+
+import (
+	"bufio"
+	_ "embed"
+	"io"
+	"os"
+	"strings"
+)
+
+//go:embed passwords.txt
+var bundledPasswords string
+
+// falsePositiveRate is the share of passwords wrongly reported as breached
+const falsePositiveRate = 0.001
+
+// List is a set of breached passwords, compared case-insensitively
+type List struct {
+	filter *BloomFilter
+}
+
+// NewBundledList loads the list of common breached passwords shipped with the application
+func NewBundledList() *List {
+	list, _ := NewList(strings.NewReader(bundledPasswords))
+	return list
+}
+
+// NewListFromFile loads a list of breached passwords, one per line, from a file
+func NewListFromFile(file string) (*List, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return NewList(f)
+}
+
+// NewList loads a list of breached passwords, one per line. Empty lines and lines
+// starting with # are skipped.
+func NewList(reader io.Reader) (*List, error) {
+	var passwords []string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords = append(passwords, strings.ToLower(line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	filter := NewBloomFilter(len(passwords), falsePositiveRate)
+	for _, password := range passwords {
+		filter.Add(password)
+	}
+
+	return &List{filter: filter}, nil
+}
+
+// Contains reports whether the password is in the list
+func (l *List) Contains(password string) bool {
+	return l.filter.Test(strings.ToLower(password))
+}
@@ -0,0 +1,40 @@
+package breached
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundledListContainsCommonPasswords(t *testing.T) {
+	list := NewBundledList()
+
+	assert.True(t, list.Contains("password"))
+	assert.True(t, list.Contains("Password123"), "Expected the list to ignore case")
+	assert.True(t, list.Contains("qwerty"))
+	assert.False(t, list.Contains("plum-Quartz-41-river"))
+	assert.False(t, list.Contains("# Common passwords seen in public breach corpora, one per line. The list is"))
+}
+
+func TestBloomFilterFalsePositiveRate(t *testing.T) {
+	var lines []string
+	for i := 0; i < 10000; i++ {
+		lines = append(lines, fmt.Sprintf("breached-%d", i))
+	}
+	list, err := NewList(strings.NewReader(strings.Join(lines, "\n")))
+	assert.NoError(t, err)
+
+	for _, line := range lines {
+		assert.True(t, list.Contains(line), "Expected no false negatives")
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if list.Contains(fmt.Sprintf("unknown-%d", i)) {
+			falsePositives++
+		}
+	}
+	assert.Less(t, falsePositives, 50, "Expected about 0.1%% of unknown passwords to be reported")
+}
@@ -2,10 +2,8 @@ package database
 
 import (
 	"fmt"
-	"os"
-	"strconv"
 
-	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/config"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -14,13 +12,9 @@ type DB struct {
 	Db *gorm.DB
 }
 
-func NewDatabase() (*DB, error) {
-	port, err := strconv.Atoi(os.Getenv("DB_PORT"))
-	if err != nil {
-		return nil, err
-	}
+func NewDatabase(config config.DatabaseConfig) (*DB, error) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=disable",
-		os.Getenv("DB_HOST"), os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_NAME"), port,
+		config.Host, config.User, config.Password, config.Name, config.Port,
 	)
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
@@ -29,13 +23,3 @@ func NewDatabase() (*DB, error) {
 
 	return &DB{Db: db}, nil
 }
-
-func (db *DB) AutoMigrate() {
-	db.Db.AutoMigrate(&domain.User{})
-	db.Db.AutoMigrate(&domain.Password{})
-	db.Db.AutoMigrate(&domain.Verifications{})
-	db.Db.AutoMigrate(&domain.Token{})
-	db.Db.AutoMigrate(&domain.Resume{})
-	db.Db.AutoMigrate(&domain.Education{})
-	db.Db.AutoMigrate(&domain.WorkExperience{})
-}
@@ -0,0 +1,49 @@
+package migrations
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var nonWordPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Create adds empty up and down files for a new migration to every dialect
+// directory under dir, numbered after the highest existing version, and
+// returns the paths of the files it wrote
+func Create(dir string, name string) ([]string, error) {
+	name = strings.Trim(nonWordPattern.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, fmt.Errorf("a migration name is required")
+	}
+
+	version := 0
+	for _, dialect := range Dialects {
+		migrations, err := Load(os.DirFS(dir), dialect)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, migration := range migrations {
+			version = max(version, migration.Version)
+		}
+	}
+	version++
+
+	var created []string
+	for _, dialect := range Dialects {
+		for _, direction := range []string{"up", "down"} {
+			file := filepath.Join(dir, dialect, fmt.Sprintf("%04d_%s.%s.sql", version, name, direction))
+			content := fmt.Sprintf("-- %04d_%s (%s) %s migration\n", version, name, dialect, direction)
+
+			if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+				return created, err
+			}
+			created = append(created, file)
+		}
+	}
+
+	return created, nil
+}
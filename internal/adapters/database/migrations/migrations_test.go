@@ -0,0 +1,116 @@
+package migrations
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func setupEmptyDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err, "Failed to open test database")
+	return db
+}
+
+func TestEveryDialectHasTheSameMigrations(t *testing.T) {
+	postgres, err := Load(files, "postgres")
+	assert.NoError(t, err)
+	sqlite, err := Load(files, "sqlite")
+	assert.NoError(t, err)
+
+	assert.NotEmpty(t, postgres)
+	assert.Equal(t, len(postgres), len(sqlite))
+	for i := range postgres {
+		assert.Equal(t, postgres[i].Version, sqlite[i].Version)
+		assert.Equal(t, postgres[i].Name, sqlite[i].Name)
+	}
+}
+
+func TestUpAppliesPendingMigrations(t *testing.T) {
+	db := setupEmptyDB(t)
+	migrator, err := NewMigrator(db)
+	assert.NoError(t, err)
+
+	ran, err := migrator.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, ran, len(migrator.migrations))
+	assert.True(t, db.Migrator().HasTable("users"))
+	assert.True(t, db.Migrator().HasTable("resumes"))
+
+	statuses, err := migrator.Status(context.Background())
+	assert.NoError(t, err)
+	for _, status := range statuses {
+		assert.NotNil(t, status.AppliedAt, "Expected migration %d to be applied", status.Version)
+	}
+
+	ran, err = migrator.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, ran, "Expected nothing to run the second time")
+}
+
+func TestDownRevertsTheLatestMigrations(t *testing.T) {
+	db := setupEmptyDB(t)
+	migrator, err := NewMigrator(db)
+	assert.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	assert.NoError(t, err)
+
+	latest := migrator.migrations[len(migrator.migrations)-1]
+	reverted, err := migrator.Down(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Len(t, reverted, 1)
+	assert.Equal(t, latest.Version, reverted[0].Version)
+
+	statuses, err := migrator.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, statuses[len(statuses)-1].AppliedAt)
+
+	_, err = migrator.Down(context.Background(), len(migrator.migrations))
+	assert.NoError(t, err)
+	assert.False(t, db.Migrator().HasTable("users"))
+}
+
+func TestFailedMigrationIsRolledBack(t *testing.T) {
+	db := setupEmptyDB(t)
+	migrator := NewMigratorWithMigrations(db, []Migration{
+		{
+			Version: 1,
+			Name:    "broken",
+			Up:      "CREATE TABLE examples (id integer);\nINSERT INTO missing VALUES (1);",
+			Down:    "DROP TABLE examples;",
+		},
+	})
+
+	ran, err := migrator.Up(context.Background())
+	assert.Error(t, err)
+	assert.Empty(t, ran)
+	assert.False(t, db.Migrator().HasTable("examples"), "Expected the partial migration to be rolled back")
+
+	statuses, err := migrator.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, statuses[0].AppliedAt)
+}
+
+func TestCreateAddsNumberedFilesForEveryDialect(t *testing.T) {
+	dir := t.TempDir()
+	for _, dialect := range Dialects {
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, dialect), 0o755))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, dialect, "0003_existing.up.sql"), []byte("SELECT 1;"), 0o644))
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, dialect, "0003_existing.down.sql"), []byte("SELECT 1;"), 0o644))
+	}
+
+	created, err := Create(dir, "Add Resume Title")
+	assert.NoError(t, err)
+	assert.Len(t, created, 4)
+	assert.FileExists(t, filepath.Join(dir, "postgres", "0004_add_resume_title.up.sql"))
+	assert.FileExists(t, filepath.Join(dir, "sqlite", "0004_add_resume_title.down.sql"))
+
+	loaded, err := Load(os.DirFS(dir), "sqlite")
+	assert.NoError(t, err)
+	assert.Len(t, loaded, 2)
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS tokens;
+DROP TABLE IF EXISTS verifications;
+DROP TABLE IF EXISTS passwords;
+DROP TABLE IF EXISTS users;
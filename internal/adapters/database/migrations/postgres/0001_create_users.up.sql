@@ -0,0 +1,46 @@
+-- Tables are created only when missing so databases previously set up
+-- through AutoMigrate can adopt versioned migrations without changes.
+CREATE TABLE IF NOT EXISTS users (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    full_name varchar(100),
+    email varchar(150) NOT NULL,
+    email_verified_at timestamptz DEFAULT NULL,
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+
+CREATE TABLE IF NOT EXISTS passwords (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    value varchar(150) NOT NULL,
+    user_id uuid NOT NULL,
+    CONSTRAINT fk_users_password FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_passwords_user_id ON passwords (user_id);
+
+CREATE TABLE IF NOT EXISTS verifications (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    type varchar(20) NOT NULL,
+    user_id uuid NOT NULL,
+    code varchar(20) NOT NULL,
+    CONSTRAINT fk_users_verifications FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_verifications_user_id ON verifications (user_id);
+
+CREATE TABLE IF NOT EXISTS tokens (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    access_token varchar(500) NOT NULL,
+    user_id uuid NOT NULL,
+    CONSTRAINT fk_users_tokens FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_tokens_user_id ON tokens (user_id);
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS resumes (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id uuid NOT NULL,
+    score bigint DEFAULT 0,
+    summary varchar(200) DEFAULT NULL,
+    skills text[],
+    template_id varchar(50) DEFAULT NULL,
+    CONSTRAINT fk_resumes_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_resumes_user_id ON resumes (user_id);
+
+CREATE TABLE IF NOT EXISTS educations (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    resume_id uuid NOT NULL,
+    school_name varchar(255) NOT NULL,
+    course varchar(255) NOT NULL,
+    start_date timestamptz,
+    end_date timestamptz,
+    CONSTRAINT fk_resumes_education FOREIGN KEY (resume_id) REFERENCES resumes (id)
+);
+CREATE INDEX IF NOT EXISTS idx_educations_resume_id ON educations (resume_id);
+
+CREATE TABLE IF NOT EXISTS work_experiences (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    resume_id uuid NOT NULL,
+    company_name varchar(255) NOT NULL,
+    role varchar(255) NOT NULL,
+    start_date timestamptz,
+    end_date timestamptz,
+    CONSTRAINT fk_resumes_experiences FOREIGN KEY (resume_id) REFERENCES resumes (id)
+);
+CREATE INDEX IF NOT EXISTS idx_work_experiences_resume_id ON work_experiences (resume_id);
@@ -0,0 +1,5 @@
+ALTER TABLE tokens ADD COLUMN type varchar(20) NOT NULL DEFAULT 'access';
+ALTER TABLE tokens ADD COLUMN family_id uuid DEFAULT NULL;
+ALTER TABLE tokens ADD COLUMN expires_at timestamptz DEFAULT NULL;
+ALTER TABLE tokens ADD COLUMN used_at timestamptz DEFAULT NULL;
+CREATE INDEX idx_tokens_family_id ON tokens (family_id);
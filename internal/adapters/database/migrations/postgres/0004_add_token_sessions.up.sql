@@ -0,0 +1,5 @@
+ALTER TABLE tokens ADD COLUMN ip_address varchar(45) DEFAULT NULL;
+ALTER TABLE tokens ADD COLUMN user_agent varchar(255) DEFAULT NULL;
+ALTER TABLE tokens ADD COLUMN last_used_at timestamptz DEFAULT NULL;
+-- Tokens issued before token families existed become a family of their own
+UPDATE tokens SET family_id = id WHERE family_id IS NULL;
@@ -0,0 +1,25 @@
+CREATE TABLE two_factors (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id uuid NOT NULL,
+    secret varchar(64) NOT NULL,
+    enabled_at timestamptz DEFAULT NULL,
+    last_used_step bigint NOT NULL DEFAULT 0,
+    failed_attempts integer NOT NULL DEFAULT 0,
+    CONSTRAINT fk_two_factors_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX idx_two_factors_user_id ON two_factors (user_id);
+
+CREATE TABLE recovery_codes (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id uuid NOT NULL,
+    code_hash varchar(150) NOT NULL,
+    used_at timestamptz DEFAULT NULL,
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes (user_id);
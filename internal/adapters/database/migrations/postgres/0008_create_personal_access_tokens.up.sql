@@ -0,0 +1,15 @@
+CREATE TABLE personal_access_tokens (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id uuid NOT NULL,
+    name varchar(100) NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    scopes varchar(255) NOT NULL,
+    expires_at timestamptz NOT NULL,
+    last_used_at timestamptz DEFAULT NULL,
+    CONSTRAINT fk_personal_access_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_personal_access_tokens_user_id ON personal_access_tokens (user_id);
+CREATE UNIQUE INDEX idx_personal_access_tokens_token_hash ON personal_access_tokens (token_hash);
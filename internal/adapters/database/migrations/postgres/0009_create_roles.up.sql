@@ -0,0 +1,41 @@
+ALTER TABLE users ADD COLUMN disabled_at timestamptz DEFAULT NULL;
+ALTER TABLE users ADD COLUMN password_reset_required boolean NOT NULL DEFAULT false;
+
+CREATE TABLE roles (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name varchar(50) NOT NULL,
+    description varchar(255)
+);
+CREATE UNIQUE INDEX idx_roles_name ON roles (name);
+
+CREATE TABLE role_permissions (
+    role_id uuid NOT NULL,
+    permission varchar(50) NOT NULL,
+    PRIMARY KEY (role_id, permission),
+    CONSTRAINT fk_roles_permissions FOREIGN KEY (role_id) REFERENCES roles (id)
+);
+
+CREATE TABLE user_roles (
+    user_id uuid NOT NULL,
+    role_id uuid NOT NULL,
+    created_at timestamptz,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles (id)
+);
+
+INSERT INTO roles (id, created_at, updated_at, name, description) VALUES
+    ('6f1c2a52-8d2e-4c1b-9a55-0b6f6f3d0a01', now(), now(), 'admin', 'Full access to the admin API, including granting roles'),
+    ('6f1c2a52-8d2e-4c1b-9a55-0b6f6f3d0a02', now(), now(), 'support', 'Look users and resumes up and help with account problems');
+
+INSERT INTO role_permissions (role_id, permission) VALUES
+    ('6f1c2a52-8d2e-4c1b-9a55-0b6f6f3d0a01', 'users:read'),
+    ('6f1c2a52-8d2e-4c1b-9a55-0b6f6f3d0a01', 'users:manage'),
+    ('6f1c2a52-8d2e-4c1b-9a55-0b6f6f3d0a01', 'resumes:read'),
+    ('6f1c2a52-8d2e-4c1b-9a55-0b6f6f3d0a01', 'roles:manage'),
+    ('6f1c2a52-8d2e-4c1b-9a55-0b6f6f3d0a02', 'users:read'),
+    ('6f1c2a52-8d2e-4c1b-9a55-0b6f6f3d0a02', 'users:manage'),
+    ('6f1c2a52-8d2e-4c1b-9a55-0b6f6f3d0a02', 'resumes:read');
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN deletion_requested_at timestamptz DEFAULT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS login_states;
+DROP TABLE IF EXISTS external_identities;
@@ -0,0 +1,27 @@
+CREATE TABLE external_identities (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id uuid NOT NULL,
+    provider varchar(50) NOT NULL,
+    subject varchar(255) NOT NULL,
+    email varchar(255) NOT NULL,
+    last_login_at timestamptz DEFAULT NULL,
+    CONSTRAINT fk_external_identities_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_external_identities_user_id ON external_identities (user_id);
+CREATE UNIQUE INDEX idx_external_identities_provider_subject ON external_identities (provider, subject);
+
+CREATE TABLE login_states (
+    id uuid PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    state_hash varchar(64) NOT NULL,
+    provider varchar(50) NOT NULL,
+    code_verifier varchar(128) NOT NULL,
+    nonce varchar(64) NOT NULL,
+    expires_at timestamptz NOT NULL
+);
+CREATE UNIQUE INDEX idx_login_states_state_hash ON login_states (state_hash);
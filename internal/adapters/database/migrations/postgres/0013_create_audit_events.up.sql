@@ -0,0 +1,23 @@
+CREATE TABLE audit_events (
+    id bigint PRIMARY KEY,
+    created_at timestamptz NOT NULL,
+    actor_id uuid DEFAULT NULL,
+    action varchar(50) NOT NULL,
+    outcome varchar(20) NOT NULL,
+    ip_address varchar(45) DEFAULT NULL,
+    user_agent varchar(255) DEFAULT NULL,
+    details varchar(255) DEFAULT NULL,
+    previous_hash varchar(64) NOT NULL,
+    hash varchar(64) NOT NULL
+);
+CREATE INDEX idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX idx_audit_events_action ON audit_events (action);
+CREATE UNIQUE INDEX idx_audit_events_hash ON audit_events (hash);
+
+-- Events are never changed or removed, not even by the application
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$ BEGIN RAISE EXCEPTION 'audit_events is append-only'; END; $$ LANGUAGE plpgsql;
+CREATE TRIGGER audit_events_append_only BEFORE UPDATE OR DELETE ON audit_events FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+CREATE TRIGGER audit_events_no_truncate BEFORE TRUNCATE ON audit_events FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+INSERT INTO role_permissions (role_id, permission) VALUES
+    ('6f1c2a52-8d2e-4c1b-9a55-0b6f6f3d0a01', 'audit:read');
@@ -0,0 +1 @@
+ALTER TABLE educations DROP COLUMN study_type;
@@ -0,0 +1 @@
+ALTER TABLE educations ADD COLUMN study_type varchar(100) DEFAULT NULL;
@@ -0,0 +1 @@
+ALTER TABLE tokens ALTER COLUMN access_token TYPE varchar(500);
@@ -0,0 +1,2 @@
+-- RS256 access tokens are longer than the 500 characters the column held
+ALTER TABLE tokens ALTER COLUMN access_token TYPE text;
@@ -0,0 +1 @@
+ALTER TABLE login_states DROP COLUMN binding_hash;
@@ -0,0 +1,2 @@
+-- The secret given to the browser that started the login, which has to come back with it
+ALTER TABLE login_states ADD COLUMN binding_hash varchar(64) NOT NULL DEFAULT '';
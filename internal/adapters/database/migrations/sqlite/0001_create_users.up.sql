@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS users (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    full_name varchar(100),
+    email varchar(150) NOT NULL,
+    email_verified_at datetime DEFAULT NULL,
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
+
+CREATE TABLE IF NOT EXISTS passwords (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    value varchar(150) NOT NULL,
+    user_id text NOT NULL,
+    CONSTRAINT fk_users_password FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_passwords_user_id ON passwords (user_id);
+
+CREATE TABLE IF NOT EXISTS verifications (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    type varchar(20) NOT NULL,
+    user_id text NOT NULL,
+    code varchar(20) NOT NULL,
+    CONSTRAINT fk_users_verifications FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_verifications_user_id ON verifications (user_id);
+
+CREATE TABLE IF NOT EXISTS tokens (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    access_token varchar(500) NOT NULL,
+    user_id text NOT NULL,
+    CONSTRAINT fk_users_tokens FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_tokens_user_id ON tokens (user_id);
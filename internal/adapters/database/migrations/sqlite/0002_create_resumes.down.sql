@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS work_experiences;
+DROP TABLE IF EXISTS educations;
+DROP TABLE IF EXISTS resumes;
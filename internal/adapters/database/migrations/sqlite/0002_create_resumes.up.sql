@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS resumes (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    user_id text NOT NULL,
+    score integer DEFAULT 0,
+    summary varchar(200) DEFAULT NULL,
+    skills text,
+    template_id varchar(50) DEFAULT NULL,
+    CONSTRAINT fk_resumes_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX IF NOT EXISTS idx_resumes_user_id ON resumes (user_id);
+
+CREATE TABLE IF NOT EXISTS educations (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    resume_id text NOT NULL,
+    school_name varchar(255) NOT NULL,
+    course varchar(255) NOT NULL,
+    start_date datetime,
+    end_date datetime,
+    CONSTRAINT fk_resumes_education FOREIGN KEY (resume_id) REFERENCES resumes (id)
+);
+CREATE INDEX IF NOT EXISTS idx_educations_resume_id ON educations (resume_id);
+
+CREATE TABLE IF NOT EXISTS work_experiences (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    resume_id text NOT NULL,
+    company_name varchar(255) NOT NULL,
+    role varchar(255) NOT NULL,
+    start_date datetime,
+    end_date datetime,
+    CONSTRAINT fk_resumes_experiences FOREIGN KEY (resume_id) REFERENCES resumes (id)
+);
+CREATE INDEX IF NOT EXISTS idx_work_experiences_resume_id ON work_experiences (resume_id);
@@ -0,0 +1,2 @@
+ALTER TABLE verifications DROP COLUMN attempts;
+ALTER TABLE verifications DROP COLUMN expires_at;
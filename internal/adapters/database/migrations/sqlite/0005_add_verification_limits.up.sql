@@ -0,0 +1,2 @@
+ALTER TABLE verifications ADD COLUMN expires_at datetime DEFAULT NULL;
+ALTER TABLE verifications ADD COLUMN attempts integer NOT NULL DEFAULT 0;
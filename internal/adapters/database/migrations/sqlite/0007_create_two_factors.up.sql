@@ -0,0 +1,25 @@
+CREATE TABLE two_factors (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    user_id text NOT NULL,
+    secret varchar(64) NOT NULL,
+    enabled_at datetime DEFAULT NULL,
+    last_used_step integer NOT NULL DEFAULT 0,
+    failed_attempts integer NOT NULL DEFAULT 0,
+    CONSTRAINT fk_two_factors_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX idx_two_factors_user_id ON two_factors (user_id);
+
+CREATE TABLE recovery_codes (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    user_id text NOT NULL,
+    code_hash varchar(150) NOT NULL,
+    used_at datetime DEFAULT NULL,
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes (user_id);
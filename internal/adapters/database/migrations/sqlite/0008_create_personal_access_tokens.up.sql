@@ -0,0 +1,15 @@
+CREATE TABLE personal_access_tokens (
+    id text PRIMARY KEY,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    user_id text NOT NULL,
+    name varchar(100) NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    scopes varchar(255) NOT NULL,
+    expires_at datetime NOT NULL,
+    last_used_at datetime DEFAULT NULL,
+    CONSTRAINT fk_personal_access_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_personal_access_tokens_user_id ON personal_access_tokens (user_id);
+CREATE UNIQUE INDEX idx_personal_access_tokens_token_hash ON personal_access_tokens (token_hash);
@@ -0,0 +1,22 @@
+CREATE TABLE audit_events (
+    id bigint PRIMARY KEY,
+    created_at datetime NOT NULL,
+    actor_id text DEFAULT NULL,
+    action varchar(50) NOT NULL,
+    outcome varchar(20) NOT NULL,
+    ip_address varchar(45) DEFAULT NULL,
+    user_agent varchar(255) DEFAULT NULL,
+    details varchar(255) DEFAULT NULL,
+    previous_hash varchar(64) NOT NULL,
+    hash varchar(64) NOT NULL
+);
+CREATE INDEX idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX idx_audit_events_action ON audit_events (action);
+CREATE UNIQUE INDEX idx_audit_events_hash ON audit_events (hash);
+
+-- Events are never changed or removed, not even by the application
+CREATE TRIGGER audit_events_no_update BEFORE UPDATE ON audit_events BEGIN SELECT RAISE(ABORT, 'audit_events is append-only'); END;
+CREATE TRIGGER audit_events_no_delete BEFORE DELETE ON audit_events BEGIN SELECT RAISE(ABORT, 'audit_events is append-only'); END;
+
+INSERT INTO role_permissions (role_id, permission) VALUES
+    ('6f1c2a52-8d2e-4c1b-9a55-0b6f6f3d0a01', 'audit:read');
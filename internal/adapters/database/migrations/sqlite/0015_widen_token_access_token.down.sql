@@ -0,0 +1,4 @@
+ALTER TABLE tokens ADD COLUMN access_token_varchar varchar(500) NOT NULL DEFAULT '';
+UPDATE tokens SET access_token_varchar = access_token;
+ALTER TABLE tokens DROP COLUMN access_token;
+ALTER TABLE tokens RENAME COLUMN access_token_varchar TO access_token;
@@ -0,0 +1,6 @@
+-- RS256 access tokens are longer than the 500 characters the column held. SQLite cannot change
+-- the type of a column, so it is replaced by a new one.
+ALTER TABLE tokens ADD COLUMN access_token_text text NOT NULL DEFAULT '';
+UPDATE tokens SET access_token_text = access_token;
+ALTER TABLE tokens DROP COLUMN access_token;
+ALTER TABLE tokens RENAME COLUMN access_token_text TO access_token;
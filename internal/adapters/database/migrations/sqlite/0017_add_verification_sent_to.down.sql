@@ -0,0 +1 @@
+ALTER TABLE verifications DROP COLUMN sent_to;
@@ -0,0 +1,2 @@
+-- The address a code was emailed to, so a code only confirms the address it went to
+ALTER TABLE verifications ADD COLUMN sent_to varchar(150) NOT NULL DEFAULT '';
@@ -1,6 +1,9 @@
 package database
 
 import (
+	"context"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database/migrations"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -11,8 +14,14 @@ func SetupMockDB() (*DB, error) {
 		return nil, err
 	}
 
-	mockedDb := DB{Db: db}
-	mockedDb.AutoMigrate()
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := migrator.Up(context.Background()); err != nil {
+		return nil, err
+	}
 
-	return &mockedDb, nil
+	return &DB{Db: db}, nil
 }
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+// appendAttempts is how often an event is chained again after another one took its place
+const appendAttempts = 3
+
+type AuditRepository struct {
+	db *database.DB
+}
+
+func NewAuditRepository(db *database.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// AppendEvent numbers an event after the last one in the log and chains it to its hash. Two
+// events appended at once can not both get the same number, so the one that loses is chained
+// again to the other.
+func (r AuditRepository) AppendEvent(ctx context.Context, event domain.AuditEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	// Both databases keep timestamps to the microsecond, which the hash has to match
+	event.CreatedAt = event.CreatedAt.UTC().Truncate(time.Microsecond)
+
+	var err error
+	for attempt := 0; attempt < appendAttempts; attempt++ {
+		err = r.db.WithinTransaction(ctx, func(ctx context.Context) error {
+			var last domain.AuditEvent
+			result := r.db.Conn(ctx).Order("id desc").Limit(1).Find(&last)
+			if result.Error != nil {
+				return result.Error
+			}
+
+			event.ID = last.ID + 1
+			event.PreviousHash = last.Hash
+			event.Hash = event.ComputeHash()
+			return r.db.Conn(ctx).Create(&event).Error
+		})
+		if err == nil || !r.eventExists(ctx, event.ID) {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (r AuditRepository) eventExists(ctx context.Context, id int64) bool {
+	var count int64
+	r.db.Conn(ctx).Model(&domain.AuditEvent{}).Where("id = ?", id).Count(&count)
+	return count > 0
+}
+
+// FindEvents lists the events matching the filters, newest first, along with the number of
+// events matching them
+func (r AuditRepository) FindEvents(ctx context.Context, payload dto.AuditQueryDto) ([]domain.AuditEvent, int64, error) {
+	query := r.db.Conn(ctx).Model(&domain.AuditEvent{})
+	if payload.ActorId != "" {
+		query = query.Where("actor_id = ?", payload.ActorId)
+	}
+	if payload.Action != "" {
+		query = query.Where("action = ?", payload.Action)
+	}
+	if payload.Outcome != "" {
+		query = query.Where("outcome = ?", payload.Outcome)
+	}
+	if payload.Since != "" {
+		since, err := time.Parse(time.RFC3339, payload.Since)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("created_at >= ?", since.UTC())
+	}
+	if payload.Until != "" {
+		until, err := time.Parse(time.RFC3339, payload.Until)
+		if err != nil {
+			return nil, 0, err
+		}
+		query = query.Where("created_at < ?", until.UTC())
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var events []domain.AuditEvent
+	result := query.Order("id desc").
+		Offset((payload.Page - 1) * payload.Limit).
+		Limit(payload.Limit).
+		Find(&events)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return events, total, nil
+}
+
+func (r AuditRepository) FindEventsAfter(ctx context.Context, id int64, limit int) ([]domain.AuditEvent, error) {
+	var events []domain.AuditEvent
+	result := r.db.Conn(ctx).Where("id > ?", id).Order("id").Limit(limit).Find(&events)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return events, nil
+}
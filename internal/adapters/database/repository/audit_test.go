@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditEventsAreChained(t *testing.T) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err, "Failed to setup test database")
+
+	ctx := context.Background()
+	repo := NewAuditRepository(db)
+	actorId := uuid.NewString()
+	assert.NoError(t, repo.AppendEvent(ctx, domain.AuditEvent{ActorId: actorId, Action: domain.AuditLogin, Outcome: domain.AuditFailure, IpAddress: "10.0.0.1"}))
+	assert.NoError(t, repo.AppendEvent(ctx, domain.AuditEvent{ActorId: actorId, Action: domain.AuditLogin, Outcome: domain.AuditSuccess, IpAddress: "10.0.0.1"}))
+	assert.NoError(t, repo.AppendEvent(ctx, domain.AuditEvent{Action: domain.AuditTokenRejected, Outcome: domain.AuditFailure}))
+
+	events, err := repo.FindEventsAfter(ctx, 0, 10)
+	assert.NoError(t, err)
+	assert.Len(t, events, 3)
+	previous := ""
+	for i, event := range events {
+		assert.Equal(t, int64(i+1), event.ID)
+		assert.Equal(t, previous, event.PreviousHash)
+		assert.Equal(t, event.Hash, event.ComputeHash(), "Expected the stored event to match its hash")
+		previous = event.Hash
+	}
+
+	found, total, err := repo.FindEvents(ctx, dto.AuditQueryDto{ActorId: actorId, Page: 1, Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, found, 1)
+	assert.Equal(t, domain.AuditSuccess, found[0].Outcome, "Expected the newest event first")
+
+	// The database refuses to change the log
+	assert.Error(t, db.Db.Model(&domain.AuditEvent{}).Where("id = ?", 1).Update("outcome", domain.AuditSuccess).Error)
+	assert.Error(t, db.Db.Where("id = ?", 1).Delete(&domain.AuditEvent{}).Error)
+}
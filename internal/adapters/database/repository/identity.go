@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+type IdentityRepository struct {
+	db *database.DB
+}
+
+func NewIdentityRepository(db *database.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// CreateLoginState stores a new login state, clearing the ones that expired without being used
+func (r IdentityRepository) CreateLoginState(ctx context.Context, state domain.LoginState) error {
+	result := r.db.Conn(ctx).Unscoped().Where("expires_at < ?", time.Now()).Delete(&domain.LoginState{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return r.db.Conn(ctx).Create(&state).Error
+}
+
+func (r IdentityRepository) TakeLoginState(ctx context.Context, stateHash string) (*domain.LoginState, error) {
+	var state domain.LoginState
+	err := r.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		result := r.db.Conn(ctx).Where("state_hash = ?", stateHash).First(&state)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		// Two requests racing with the same state can both find it, but only one deletes it
+		result = r.db.Conn(ctx).Unscoped().Delete(&domain.LoginState{}, "id = ?", state.ID)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+func (r IdentityRepository) FindExternalIdentity(ctx context.Context, provider string, subject string) (*domain.ExternalIdentity, error) {
+	var identity domain.ExternalIdentity
+	result := r.db.Conn(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &identity, nil
+}
+
+func (r IdentityRepository) CreateExternalIdentity(ctx context.Context, identity domain.ExternalIdentity) error {
+	return r.db.Conn(ctx).Create(&identity).Error
+}
+
+func (r IdentityRepository) UpdateExternalIdentity(ctx context.Context, id string, updates map[string]interface{}) error {
+	return r.db.Conn(ctx).Model(&domain.ExternalIdentity{}).Where("id = ?", id).Updates(updates).Error
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RateLimitRepository keeps rate limit state in the database so it is shared by every server instance
+type RateLimitRepository struct {
+	db *database.DB
+}
+
+func NewRateLimitRepository(db *database.DB) *RateLimitRepository {
+	return &RateLimitRepository{db: db}
+}
+
+func (r RateLimitRepository) Find(ctx context.Context, key string) (*domain.RateLimit, error) {
+	var state domain.RateLimit
+	result := r.db.Conn(ctx).Where("key = ?", key).First(&state)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &state, nil
+}
+
+func (r RateLimitRepository) Save(ctx context.Context, state domain.RateLimit) error {
+	result := r.db.Conn(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		UpdateAll: true,
+	}).Create(&state)
+
+	return result.Error
+}
+
+func (r RateLimitRepository) Delete(ctx context.Context, key string) error {
+	result := r.db.Conn(ctx).Where("key = ?", key).Delete(&domain.RateLimit{})
+	return result.Error
+}
@@ -19,11 +19,12 @@ func NewResumeRepository(db *database.DB) *ResumeRepository {
 
 func (repo ResumeRepository) CreateResume(ctx context.Context, resume dto.ResumeDto) (*domain.Resume, error) {
 	payload := domain.Resume{
-		UserId:  resume.UserId,
-		Summary: resume.Summary,
-		Skills:  resume.Skills,
+		UserId:     resume.UserId,
+		Summary:    resume.Summary,
+		Skills:     resume.Skills,
+		TemplateId: resume.TemplateId,
 	}
-	result := repo.db.Db.Create(&payload)
+	result := repo.db.Conn(ctx).Create(&payload)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -32,7 +33,7 @@ func (repo ResumeRepository) CreateResume(ctx context.Context, resume dto.Resume
 
 func (repo ResumeRepository) FindResumeById(ctx context.Context, id string) (*domain.Resume, error) {
 	var resume domain.Resume
-	result := repo.db.Db.Where("id = ?", id).First(&resume)
+	result := repo.db.Conn(ctx).Preload("User").Preload("Experiences").Preload("Education").Where("id = ?", id).First(&resume)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -42,7 +43,7 @@ func (repo ResumeRepository) FindResumeById(ctx context.Context, id string) (*do
 
 func (repo ResumeRepository) FindResumeList(ctx context.Context, filter dto.ResumeFilterDto) ([]domain.Resume, error) {
 	var resumes []domain.Resume
-	result := repo.db.Db.Where("user_id = ?", filter.UserId).Limit(10).Find(&resumes)
+	result := repo.db.Conn(ctx).Where("user_id = ?", filter.UserId).Limit(10).Find(&resumes)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -50,8 +51,43 @@ func (repo ResumeRepository) FindResumeList(ctx context.Context, filter dto.Resu
 	return resumes, nil
 
 }
+
+// FindUserResumes lists every resume of a user along with its experiences and education
+func (repo ResumeRepository) FindUserResumes(ctx context.Context, userId string) ([]domain.Resume, error) {
+	var resumes []domain.Resume
+	result := repo.db.Conn(ctx).Preload("Experiences").Preload("Education").
+		Where("user_id = ?", userId).
+		Order("created_at").
+		Find(&resumes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return resumes, nil
+}
+
+// PurgeUserResumes permanently deletes the resumes of a user, including the ones that were
+// soft deleted, along with their experiences and education
+func (repo ResumeRepository) PurgeUserResumes(ctx context.Context, userId string) error {
+	return repo.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		resumes := repo.db.Conn(ctx).Unscoped().Model(&domain.Resume{}).Select("id").Where("user_id = ?", userId)
+
+		result := repo.db.Conn(ctx).Unscoped().Where("resume_id IN (?)", resumes).Delete(&domain.WorkExperience{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = repo.db.Conn(ctx).Unscoped().Where("resume_id IN (?)", resumes).Delete(&domain.Education{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return repo.db.Conn(ctx).Unscoped().Where("user_id = ?", userId).Delete(&domain.Resume{}).Error
+	})
+}
+
 func (repo ResumeRepository) UpdateResume(ctx context.Context, id string, updates map[string]interface{}) error {
-	result := repo.db.Db.Model(&domain.User{}).Where("id = ?", id).Updates(updates)
+	result := repo.db.Conn(ctx).Model(&domain.Resume{}).Where("id = ?", id).Updates(updates)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -64,7 +100,7 @@ func (repo ResumeRepository) UpdateResume(ctx context.Context, id string, update
 
 }
 func (repo ResumeRepository) DeleteResume(ctx context.Context, id string) error {
-	result := repo.db.Db.Delete(&domain.Resume{Base: domain.Base{ID: id}})
+	result := repo.db.Conn(ctx).Delete(&domain.Resume{Base: domain.Base{ID: id}})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -89,7 +125,11 @@ func (repo ResumeRepository) AddWorkExperiences(ctx context.Context, id string,
 		records = append(records, experience)
 	}
 
-	result := repo.db.Db.Create(&records)
+	if len(records) == 0 {
+		return nil
+	}
+
+	result := repo.db.Conn(ctx).Create(&records)
 
 	if result.Error != nil {
 		return result.Error
@@ -99,7 +139,7 @@ func (repo ResumeRepository) AddWorkExperiences(ctx context.Context, id string,
 
 }
 func (repo ResumeRepository) UpdateWorkExperiences(ctx context.Context, id string, updates map[string]interface{}) error {
-	result := repo.db.Db.Model(&domain.WorkExperience{}).Where("id = ?", id).Updates(updates)
+	result := repo.db.Conn(ctx).Model(&domain.WorkExperience{}).Where("id = ?", id).Updates(updates)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -119,13 +159,18 @@ func (repo ResumeRepository) AddEducation(ctx context.Context, id string, educat
 			ResumeId:   id,
 			SchoolName: record.SchoolName,
 			Course:     record.Course,
+			StudyType:  record.StudyType,
 			StartDate:  record.StartDate,
 			EndDate:    record.EndDate,
 		}
 		records = append(records, experience)
 	}
 
-	result := repo.db.Db.Create(&records)
+	if len(records) == 0 {
+		return nil
+	}
+
+	result := repo.db.Conn(ctx).Create(&records)
 
 	if result.Error != nil {
 		return result.Error
@@ -135,7 +180,7 @@ func (repo ResumeRepository) AddEducation(ctx context.Context, id string, educat
 
 }
 func (repo ResumeRepository) UpdateEducation(ctx context.Context, id string, updates map[string]interface{}) error {
-	result := repo.db.Db.Model(&domain.Education{}).Where("id = ?", id).Updates(updates)
+	result := repo.db.Conn(ctx).Model(&domain.Education{}).Where("id = ?", id).Updates(updates)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -148,7 +193,7 @@ func (repo ResumeRepository) UpdateEducation(ctx context.Context, id string, upd
 }
 
 func (repo ResumeRepository) DeleteWorkExperience(ctx context.Context, experienceId string) error {
-	result := repo.db.Db.Delete(&domain.WorkExperience{Base: domain.Base{ID: experienceId}})
+	result := repo.db.Conn(ctx).Delete(&domain.WorkExperience{Base: domain.Base{ID: experienceId}})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -161,7 +206,7 @@ func (repo ResumeRepository) DeleteWorkExperience(ctx context.Context, experienc
 }
 
 func (repo ResumeRepository) DeleteEducation(ctx context.Context, educationId string) error {
-	result := repo.db.Db.Delete(&domain.Education{Base: domain.Base{ID: educationId}})
+	result := repo.db.Conn(ctx).Delete(&domain.Education{Base: domain.Base{ID: educationId}})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupResumeOwner(t *testing.T) (*database.DB, string) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err, "Failed to setup test database")
+
+	user, err := NewUserRepository(db).CreateUser(context.Background(), GenerateFakeUser())
+	assert.NoError(t, err, "Expected no error on user creation")
+
+	return db, user.ID
+}
+
+func TestCreateResumeWithinTransaction(t *testing.T) {
+	db, userId := setupResumeOwner(t)
+	repo := NewResumeRepository(db)
+	ctx := context.Background()
+
+	err := db.WithinTransaction(ctx, func(ctx context.Context) error {
+		resume, err := repo.CreateResume(ctx, dto.ResumeDto{
+			UserId:  userId,
+			Summary: gofakeit.Sentence(10),
+			Skills:  []string{"Go"},
+		})
+		if err != nil {
+			return err
+		}
+
+		return repo.AddEducation(ctx, resume.ID, []dto.EducationDto{
+			{
+				SchoolName: gofakeit.Company(),
+				Course:     gofakeit.JobTitle(),
+				StartDate:  gofakeit.Date(),
+			},
+		})
+	})
+	assert.NoError(t, err, "Expected the transaction to commit")
+
+	resumes, err := repo.FindResumeList(ctx, dto.ResumeFilterDto{UserId: userId})
+	assert.NoError(t, err)
+	assert.Len(t, resumes, 1, "Expected the committed resume to be stored")
+
+	resume, err := repo.FindResumeById(ctx, resumes[0].ID)
+	assert.NoError(t, err)
+	assert.Len(t, resume.Education, 1, "Expected the committed education to be stored")
+}
+
+func TestCreateResumeRollsBackOnError(t *testing.T) {
+	db, userId := setupResumeOwner(t)
+	repo := NewResumeRepository(db)
+	ctx := context.Background()
+	failure := errors.New("adding experiences failed")
+
+	err := db.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := repo.CreateResume(ctx, dto.ResumeDto{
+			UserId:  userId,
+			Summary: gofakeit.Sentence(10),
+			Skills:  []string{"Go"},
+		})
+		if err != nil {
+			return err
+		}
+
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	resumes, err := repo.FindResumeList(ctx, dto.ResumeFilterDto{UserId: userId})
+	assert.NoError(t, err)
+	assert.Empty(t, resumes, "Expected no orphaned resume after a rollback")
+}
+
+func TestNestedTransactionJoinsOuterTransaction(t *testing.T) {
+	db, userId := setupResumeOwner(t)
+	repo := NewResumeRepository(db)
+	ctx := context.Background()
+	failure := errors.New("outer step failed")
+
+	err := db.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := db.WithinTransaction(ctx, func(ctx context.Context) error {
+			_, err := repo.CreateResume(ctx, dto.ResumeDto{
+				UserId:  userId,
+				Summary: gofakeit.Sentence(10),
+				Skills:  []string{"Go"},
+			})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	resumes, err := repo.FindResumeList(ctx, dto.ResumeFilterDto{UserId: userId})
+	assert.NoError(t, err)
+	assert.Empty(t, resumes, "Expected the inner write to be rolled back with the outer transaction")
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleRepository struct {
+	db *database.DB
+}
+
+func NewRoleRepository(db *database.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+func (r RoleRepository) FindRoles(ctx context.Context) ([]domain.Role, error) {
+	var roles []domain.Role
+	result := r.db.Conn(ctx).Preload("Permissions").Order("name").Find(&roles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return roles, nil
+}
+
+func (r RoleRepository) FindRole(ctx context.Context, name string) (*domain.Role, error) {
+	var role domain.Role
+	result := r.db.Conn(ctx).Preload("Permissions").Where("name = ?", name).First(&role)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &role, nil
+}
+
+func (r RoleRepository) FindUserRoles(ctx context.Context, userId string) ([]domain.Role, error) {
+	var roles []domain.Role
+	result := r.db.Conn(ctx).Preload("Permissions").
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userId).
+		Order("roles.name").
+		Find(&roles)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return roles, nil
+}
+
+// FindUserPermissions lists every permission granted to a user through their roles
+func (r RoleRepository) FindUserPermissions(ctx context.Context, userId string) ([]string, error) {
+	var permissions []string
+	result := r.db.Conn(ctx).Model(&domain.RolePermission{}).
+		Distinct("role_permissions.permission").
+		Joins("JOIN user_roles ON user_roles.role_id = role_permissions.role_id").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id AND roles.deleted_at IS NULL").
+		Where("user_roles.user_id = ?", userId).
+		Pluck("role_permissions.permission", &permissions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return permissions, nil
+}
+
+// AssignRole grants a role to a user, doing nothing when they already have it
+func (r RoleRepository) AssignRole(ctx context.Context, userId string, roleId string) error {
+	result := r.db.Conn(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.UserRole{
+		UserId: userId,
+		RoleId: roleId,
+	})
+
+	return result.Error
+}
+
+func (r RoleRepository) RemoveRole(ctx context.Context, userId string, roleId string) error {
+	result := r.db.Conn(ctx).Where("user_id = ? AND role_id = ?", userId, roleId).Delete(&domain.UserRole{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindUserPermissions(t *testing.T) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err, "Failed to setup test database")
+
+	ctx := context.Background()
+	user, err := NewUserRepository(db).CreateUser(ctx, GenerateFakeUser())
+	assert.NoError(t, err, "Expected no error on user creation")
+
+	repo := NewRoleRepository(db)
+	permissions, err := repo.FindUserPermissions(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, permissions, "Expected users to have no permission by default")
+
+	support, err := repo.FindRole(ctx, "support")
+	assert.NoError(t, err, "Expected the support role to be seeded")
+	admin, err := repo.FindRole(ctx, "admin")
+	assert.NoError(t, err, "Expected the admin role to be seeded")
+
+	assert.NoError(t, repo.AssignRole(ctx, user.ID, support.ID))
+	assert.NoError(t, repo.AssignRole(ctx, user.ID, support.ID), "Expected assigning a role twice to be a no-op")
+	assert.NoError(t, repo.AssignRole(ctx, user.ID, admin.ID))
+
+	permissions, err = repo.FindUserPermissions(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		domain.PermissionUsersRead,
+		domain.PermissionUsersManage,
+		domain.PermissionResumesRead,
+		domain.PermissionRolesManage,
+		domain.PermissionAuditRead,
+	}, permissions, "Expected permissions shared by roles to be listed once")
+
+	assert.NoError(t, repo.RemoveRole(ctx, user.ID, admin.ID))
+	permissions, err = repo.FindUserPermissions(ctx, user.ID)
+	assert.NoError(t, err)
+	assert.NotContains(t, permissions, domain.PermissionRolesManage)
+}
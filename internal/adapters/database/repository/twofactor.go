@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"gorm.io/gorm"
+)
+
+type TwoFactorRepository struct {
+	db *database.DB
+}
+
+func NewTwoFactorRepository(db *database.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+func (r TwoFactorRepository) FindTwoFactor(ctx context.Context, userId string) (*domain.TwoFactor, error) {
+	var twoFactor domain.TwoFactor
+	result := r.db.Conn(ctx).Where("user_id = ?", userId).First(&twoFactor)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &twoFactor, nil
+}
+
+// SaveTwoFactor stores the two-factor settings of a user, replacing the ones they already have
+func (r TwoFactorRepository) SaveTwoFactor(ctx context.Context, twoFactor domain.TwoFactor) error {
+	return r.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		result := r.db.Conn(ctx).Unscoped().Where("user_id = ?", twoFactor.UserId).Delete(&domain.TwoFactor{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		return r.db.Conn(ctx).Create(&twoFactor).Error
+	})
+}
+
+func (r TwoFactorRepository) UpdateTwoFactor(ctx context.Context, userId string, updates map[string]interface{}) error {
+	result := r.db.Conn(ctx).Model(&domain.TwoFactor{}).Where("user_id = ?", userId).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes deletes every recovery code of a user and stores the given hashes instead
+func (r TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userId string, hashes []string) error {
+	return r.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		result := r.db.Conn(ctx).Unscoped().Where("user_id = ?", userId).Delete(&domain.RecoveryCode{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		codes := make([]domain.RecoveryCode, 0, len(hashes))
+		for _, hash := range hashes {
+			codes = append(codes, domain.RecoveryCode{UserId: userId, CodeHash: hash})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+
+		return r.db.Conn(ctx).Create(&codes).Error
+	})
+}
+
+// UseTOTPStep records the time step of a TOTP code a user logged in with and clears their failed
+// attempts. It fails with [gorm.ErrRecordNotFound] when the step is not newer than the last one
+// used, so a code can only be used once even by concurrent requests.
+func (r TwoFactorRepository) UseTOTPStep(ctx context.Context, userId string, step int64) error {
+	result := r.db.Conn(ctx).Model(&domain.TwoFactor{}).
+		Where("user_id = ?", userId).
+		Where("last_used_step < ?", step).
+		Updates(map[string]interface{}{"last_used_step": step, "failed_attempts": 0})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// ConsumeAttempt increments the failed attempts of a user unless they already reached
+// maxAttempts. The check and the increment are a single statement, so concurrent guesses can
+// not go past the limit.
+func (r TwoFactorRepository) ConsumeAttempt(ctx context.Context, userId string, maxAttempts int) (bool, error) {
+	result := r.db.Conn(ctx).Model(&domain.TwoFactor{}).
+		Where("user_id = ? AND failed_attempts < ?", userId, maxAttempts).
+		Update("failed_attempts", gorm.Expr("failed_attempts + 1"))
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+// UseRecoveryCode flags the unused recovery code of a user with the given hash as used. It fails
+// with [gorm.ErrRecordNotFound] when there is no such code, or it was already used.
+func (r TwoFactorRepository) UseRecoveryCode(ctx context.Context, userId string, codeHash string) error {
+	result := r.db.Conn(ctx).Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ?", userId, codeHash).
+		Where("used_at IS NULL").
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
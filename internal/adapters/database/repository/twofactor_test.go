@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseTOTPStepOnlyOnce(t *testing.T) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err, "Failed to setup test database")
+	repo := NewTwoFactorRepository(db)
+	ctx := context.Background()
+
+	createdUser, err := NewUserRepository(db).CreateUser(ctx, GenerateFakeUser())
+	assert.NoError(t, err)
+	err = repo.SaveTwoFactor(ctx, domain.TwoFactor{UserId: createdUser.ID, Secret: "secret", LastUsedStep: 10, FailedAttempts: 2})
+	assert.NoError(t, err)
+
+	assert.Error(t, repo.UseTOTPStep(ctx, createdUser.ID, 10), "Expected the last used step to be rejected")
+	assert.NoError(t, repo.UseTOTPStep(ctx, createdUser.ID, 11))
+	assert.Error(t, repo.UseTOTPStep(ctx, createdUser.ID, 11), "Expected a step to be used only once")
+
+	twoFactor, err := repo.FindTwoFactor(ctx, createdUser.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(11), twoFactor.LastUsedStep)
+	assert.Equal(t, 0, twoFactor.FailedAttempts)
+}
+
+func TestConsumeTwoFactorAttemptStopsAtTheLimit(t *testing.T) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err, "Failed to setup test database")
+	repo := NewTwoFactorRepository(db)
+	ctx := context.Background()
+
+	createdUser, err := NewUserRepository(db).CreateUser(ctx, GenerateFakeUser())
+	assert.NoError(t, err)
+	assert.NoError(t, repo.SaveTwoFactor(ctx, domain.TwoFactor{UserId: createdUser.ID, Secret: "secret"}))
+
+	for i := 0; i < 3; i++ {
+		ok, err := repo.ConsumeAttempt(ctx, createdUser.ID, 3)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, err := repo.ConsumeAttempt(ctx, createdUser.ID, 3)
+	assert.NoError(t, err)
+	assert.False(t, ok, "Expected no attempt to be left")
+
+	twoFactor, err := repo.FindTwoFactor(ctx, createdUser.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, twoFactor.FailedAttempts)
+}
+
+func TestUseRecoveryCodeOnlyOnce(t *testing.T) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err, "Failed to setup test database")
+	repo := NewTwoFactorRepository(db)
+	ctx := context.Background()
+
+	userRepo := NewUserRepository(db)
+	createdUser, err := userRepo.CreateUser(ctx, GenerateFakeUser())
+	assert.NoError(t, err)
+	otherUser, err := userRepo.CreateUser(ctx, GenerateFakeUser())
+	assert.NoError(t, err)
+	assert.NoError(t, repo.ReplaceRecoveryCodes(ctx, createdUser.ID, []string{"first", "second"}))
+
+	assert.Error(t, repo.UseRecoveryCode(ctx, otherUser.ID, "first"), "Expected codes of other users to be rejected")
+	assert.Error(t, repo.UseRecoveryCode(ctx, createdUser.ID, "unknown"))
+	assert.NoError(t, repo.UseRecoveryCode(ctx, createdUser.ID, "first"))
+	assert.Error(t, repo.UseRecoveryCode(ctx, createdUser.ID, "first"), "Expected a code to be used only once")
+	assert.NoError(t, repo.UseRecoveryCode(ctx, createdUser.ID, "second"))
+}
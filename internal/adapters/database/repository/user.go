@@ -2,7 +2,10 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stivo-m/vise-resume/internal/adapters/database"
 	"github.com/stivo-m/vise-resume/internal/core/domain"
 	"github.com/stivo-m/vise-resume/internal/core/dto"
@@ -18,7 +21,7 @@ func NewUserRepository(db *database.DB) *UserRepository {
 }
 
 func (repo UserRepository) CreateUser(ctx context.Context, user domain.User) (*domain.User, error) {
-	result := repo.db.Db.Create(&user)
+	result := repo.db.Conn(ctx).Create(&user)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -32,9 +35,9 @@ func (repo UserRepository) FindUser(ctx context.Context, payload dto.FindUserDto
 	var result *gorm.DB
 
 	if payload.Email != "" {
-		result = repo.db.Db.Preload("Password").Where("email = ?", payload.Email).First(&user)
+		result = repo.db.Conn(ctx).Preload("Password").Where("email = ?", payload.Email).First(&user)
 	} else if payload.ID != "" {
-		result = repo.db.Db.Preload("Password").Where("id = ?", payload.ID).First(&user)
+		result = repo.db.Conn(ctx).Preload("Password").Where("id = ?", payload.ID).First(&user)
 	}
 
 	if result.Error != nil {
@@ -44,8 +47,34 @@ func (repo UserRepository) FindUser(ctx context.Context, payload dto.FindUserDto
 	return &user, nil
 }
 
+// SearchUsers lists users whose name or email contains the search term, newest first,
+// along with the number of users matching it
+func (repo UserRepository) SearchUsers(ctx context.Context, payload dto.SearchUsersDto) ([]domain.User, int64, error) {
+	query := repo.db.Conn(ctx).Model(&domain.User{})
+	if payload.Search != "" {
+		term := "%" + strings.ToLower(payload.Search) + "%"
+		query = query.Where("LOWER(email) LIKE ? OR LOWER(full_name) LIKE ?", term, term)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	result := query.Order("created_at desc").
+		Offset((payload.Page - 1) * payload.Limit).
+		Limit(payload.Limit).
+		Find(&users)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+
+	return users, total, nil
+}
+
 func (repo UserRepository) UpdateUser(ctx context.Context, id string, updates map[string]interface{}) error {
-	result := repo.db.Db.Model(&domain.User{}).Where("id = ?", id).Updates(updates)
+	result := repo.db.Conn(ctx).Model(&domain.User{}).Where("id = ?", id).Updates(updates)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -58,26 +87,33 @@ func (repo UserRepository) UpdateUser(ctx context.Context, id string, updates ma
 }
 
 func (repo UserRepository) UpdateUserPassword(ctx context.Context, id string, password domain.Password) error {
-	user, err := repo.FindUser(ctx, dto.FindUserDto{
-		ID: id,
+	return repo.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := repo.FindUser(ctx, dto.FindUserDto{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if user.Password.ID != "" {
+			result := repo.db.Conn(ctx).Delete(&domain.Password{Base: domain.Base{ID: user.Password.ID}})
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		user.Password = password
+		if err := repo.db.Conn(ctx).Save(&user).Error; err != nil {
+			return err
+		}
+
+		return nil
 	})
-
-	if err != nil {
-		return err
-	}
-
-	_ = repo.db.Db.Delete(&domain.Password{Base: domain.Base{ID: user.Password.ID}})
-	user.Password = password
-	if err := repo.db.Db.Save(&user).Error; err != nil {
-		return err
-	}
-
-	return nil
-
 }
 
 func (repo UserRepository) DeleteUser(ctx context.Context, id string) error {
-	result := repo.db.Db.Delete(&domain.User{Base: domain.Base{ID: id}})
+	result := repo.db.Conn(ctx).Delete(&domain.User{Base: domain.Base{ID: id}})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -89,10 +125,63 @@ func (repo UserRepository) DeleteUser(ctx context.Context, id string) error {
 	return nil
 }
 
+// FindUsersPendingDeletion lists the users who asked for their account to be deleted before a date
+func (repo UserRepository) FindUsersPendingDeletion(ctx context.Context, requestedBefore time.Time) ([]domain.User, error) {
+	var users []domain.User
+	result := repo.db.Conn(ctx).Where("deletion_requested_at <= ?", requestedBefore).Find(&users)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return users, nil
+}
+
+// PurgeUser permanently deletes a user, including rows that were soft deleted, along with
+// everything that belongs to them apart from their resumes
+func (repo UserRepository) PurgeUser(ctx context.Context, id string) error {
+	return repo.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		owned := []interface{}{
+			&domain.Password{},
+			&domain.Token{},
+			&domain.Verifications{},
+			&domain.RecoveryCode{},
+			&domain.TwoFactor{},
+			&domain.PersonalAccessToken{},
+			&domain.UserRole{},
+			&domain.ExternalIdentity{},
+		}
+		for _, model := range owned {
+			result := repo.db.Conn(ctx).Unscoped().Where("user_id = ?", id).Delete(model)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		result := repo.db.Conn(ctx).Unscoped().Delete(&domain.User{Base: domain.Base{ID: id}})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return nil
+	})
+}
+
+// FindToken looks a token up by its value, optionally narrowed down to a user and a token type
 func (repo UserRepository) FindToken(ctx context.Context, payload dto.ManageTokenDto) (*domain.Token, error) {
 	var token *domain.Token
-	result := repo.db.Db.Where("user_id = ?", payload.ID).Where("access_token = ?", payload.AccessToken).First(&token)
+	query := repo.db.Conn(ctx).Where("access_token = ?", payload.AccessToken)
+	if payload.ID != "" {
+		query = query.Where("user_id = ?", payload.ID)
+	}
+	if payload.Type != "" {
+		query = query.Where("type = ?", payload.Type)
+	}
 
+	result := query.First(&token)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -105,8 +194,17 @@ func (repo UserRepository) CreateToken(ctx context.Context, payload dto.ManageTo
 	tokenData := domain.Token{
 		UserId:      payload.ID,
 		AccessToken: payload.AccessToken,
+		Type:        payload.Type,
+		FamilyId:    payload.FamilyId,
+		ExpiresAt:   payload.ExpiresAt,
+	}
+	if tokenData.Type == "" {
+		tokenData.Type = "access"
+	}
+	if tokenData.FamilyId == "" {
+		tokenData.FamilyId = uuid.NewString()
 	}
-	result := repo.db.Db.Create(&tokenData)
+	result := repo.db.Conn(ctx).Create(&tokenData)
 
 	if result.Error != nil {
 		return result.Error
@@ -120,11 +218,167 @@ func (repo UserRepository) DeleteToken(ctx context.Context, payload dto.ManageTo
 	tokenData := domain.Token{
 		AccessToken: payload.AccessToken,
 	}
-	result := repo.db.Db.Where("access_token = ?", payload.AccessToken).Delete(&tokenData)
+	result := repo.db.Conn(ctx).Where("access_token = ?", payload.AccessToken).Delete(&tokenData)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+// FindTokens lists the tokens of a user, newest first, optionally narrowed down to a token type
+func (repo UserRepository) FindTokens(ctx context.Context, payload dto.ManageTokenDto) ([]domain.Token, error) {
+	var tokens []domain.Token
+	query := repo.db.Conn(ctx).Where("user_id = ?", payload.ID)
+	if payload.Type != "" {
+		query = query.Where("type = ?", payload.Type)
+	}
+	if payload.WithRevoked {
+		query = query.Unscoped()
+	}
+
+	result := query.Order("created_at desc").Find(&tokens)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return tokens, nil
+}
+
+func (repo UserRepository) UpdateToken(ctx context.Context, id string, updates map[string]interface{}) error {
+	result := repo.db.Conn(ctx).Model(&domain.Token{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// MarkTokenUsed flags a token as used. It fails with [gorm.ErrRecordNotFound] when the
+// token was already used, which lets callers detect concurrent use of the same token.
+func (repo UserRepository) MarkTokenUsed(ctx context.Context, id string) error {
+	result := repo.db.Conn(ctx).Model(&domain.Token{}).
+		Where("id = ?", id).
+		Where("used_at IS NULL").
+		Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
 
+	return nil
+}
+
+// ConsumeToken deletes a single use token. It fails with [gorm.ErrRecordNotFound] when the token
+// was already deleted, so only one of concurrent requests can use it.
+func (repo UserRepository) ConsumeToken(ctx context.Context, id string) error {
+	result := repo.db.Conn(ctx).Where("id = ?", id).Delete(&domain.Token{})
 	if result.Error != nil {
 		return result.Error
 	}
 
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// DeleteTokenFamily deletes every token issued by the same login, or only
+// the ones of the given type when tokenType is not empty
+func (repo UserRepository) DeleteTokenFamily(ctx context.Context, familyId string, tokenType string) error {
+	query := repo.db.Conn(ctx).Where("family_id = ?", familyId)
+	if tokenType != "" {
+		query = query.Where("type = ?", tokenType)
+	}
+
+	result := query.Delete(&domain.Token{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+// DeleteUserTokens deletes every token of a user except the ones in the family exceptFamilyId,
+// which can be empty to delete all of them
+func (repo UserRepository) DeleteUserTokens(ctx context.Context, userId string, exceptFamilyId string) error {
+	query := repo.db.Conn(ctx).Where("user_id = ?", userId)
+	if exceptFamilyId != "" {
+		query = query.Where("family_id IS NULL OR family_id <> ?", exceptFamilyId)
+	}
+
+	result := query.Delete(&domain.Token{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	return nil
+}
+
+func (repo UserRepository) CreatePersonalAccessToken(ctx context.Context, token domain.PersonalAccessToken) (*domain.PersonalAccessToken, error) {
+	result := repo.db.Conn(ctx).Create(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &token, nil
+}
+
+// FindPersonalAccessToken looks a personal access token up by the hash of its value
+func (repo UserRepository) FindPersonalAccessToken(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	var token domain.PersonalAccessToken
+	result := repo.db.Conn(ctx).Where("token_hash = ?", tokenHash).First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &token, nil
+}
+
+// FindPersonalAccessTokens lists the personal access tokens of a user, newest first
+func (repo UserRepository) FindPersonalAccessTokens(ctx context.Context, userId string) ([]domain.PersonalAccessToken, error) {
+	var tokens []domain.PersonalAccessToken
+	result := repo.db.Conn(ctx).Where("user_id = ?", userId).Order("created_at desc").Find(&tokens)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return tokens, nil
+}
+
+func (repo UserRepository) UpdatePersonalAccessToken(ctx context.Context, id string, updates map[string]interface{}) error {
+	result := repo.db.Conn(ctx).Model(&domain.PersonalAccessToken{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// DeletePersonalAccessToken deletes one of the personal access tokens of a user
+func (repo UserRepository) DeletePersonalAccessToken(ctx context.Context, userId string, id string) error {
+	result := repo.db.Conn(ctx).Where("user_id = ?", userId).Where("id = ?", id).Delete(&domain.PersonalAccessToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
 	return nil
 }
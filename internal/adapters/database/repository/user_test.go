@@ -275,3 +275,84 @@ func TestDeleteToken(t *testing.T) {
 	err = repo.DeleteToken(ctx, dto.ManageTokenDto{AccessToken: tokenString})
 	assert.Nil(t, err)
 }
+
+func TestMarkTokenUsedOnlyOnce(t *testing.T) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err, "Failed to setup test database")
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	createdUser, _ := repo.CreateUser(ctx, GenerateFakeUser())
+	tokenString := gofakeit.UUID()
+	err = repo.CreateToken(ctx, dto.ManageTokenDto{
+		ID:          createdUser.ID,
+		AccessToken: tokenString,
+		Type:        "refresh",
+		FamilyId:    gofakeit.UUID(),
+	})
+	assert.Nil(t, err)
+
+	token, err := repo.FindToken(ctx, dto.ManageTokenDto{AccessToken: tokenString, Type: "refresh"})
+	assert.Nil(t, err)
+	assert.Nil(t, token.UsedAt)
+
+	assert.Nil(t, repo.MarkTokenUsed(ctx, token.ID))
+	assert.Error(t, repo.MarkTokenUsed(ctx, token.ID), "Expected a used token to be rejected")
+
+	_, err = repo.FindToken(ctx, dto.ManageTokenDto{AccessToken: tokenString, Type: "access"})
+	assert.Error(t, err, "Expected the token type to be part of the lookup")
+}
+
+func TestConsumeTokenOnlyOnce(t *testing.T) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err, "Failed to setup test database")
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	createdUser, _ := repo.CreateUser(ctx, GenerateFakeUser())
+	tokenString := gofakeit.UUID()
+	err = repo.CreateToken(ctx, dto.ManageTokenDto{
+		ID:          createdUser.ID,
+		AccessToken: tokenString,
+		Type:        "2fa-challenge",
+		FamilyId:    gofakeit.UUID(),
+	})
+	assert.Nil(t, err)
+
+	token, err := repo.FindToken(ctx, dto.ManageTokenDto{AccessToken: tokenString, Type: "2fa-challenge"})
+	assert.Nil(t, err)
+
+	assert.Nil(t, repo.ConsumeToken(ctx, token.ID))
+	assert.Error(t, repo.ConsumeToken(ctx, token.ID), "Expected a consumed token to be rejected")
+	_, err = repo.FindToken(ctx, dto.ManageTokenDto{AccessToken: tokenString, Type: "2fa-challenge"})
+	assert.Error(t, err)
+}
+
+func TestDeleteTokenFamily(t *testing.T) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err, "Failed to setup test database")
+	repo := NewUserRepository(db)
+	ctx := context.Background()
+
+	createdUser, _ := repo.CreateUser(ctx, GenerateFakeUser())
+	familyId := gofakeit.UUID()
+	for _, tokenType := range []string{"access", "refresh"} {
+		err = repo.CreateToken(ctx, dto.ManageTokenDto{
+			ID:          createdUser.ID,
+			AccessToken: tokenType + familyId,
+			Type:        tokenType,
+			FamilyId:    familyId,
+		})
+		assert.Nil(t, err)
+	}
+
+	assert.Nil(t, repo.DeleteTokenFamily(ctx, familyId, "access"))
+	_, err = repo.FindToken(ctx, dto.ManageTokenDto{AccessToken: "access" + familyId})
+	assert.Error(t, err)
+	_, err = repo.FindToken(ctx, dto.ManageTokenDto{AccessToken: "refresh" + familyId})
+	assert.Nil(t, err)
+
+	assert.Nil(t, repo.DeleteTokenFamily(ctx, familyId, ""))
+	_, err = repo.FindToken(ctx, dto.ManageTokenDto{AccessToken: "refresh" + familyId})
+	assert.Error(t, err)
+}
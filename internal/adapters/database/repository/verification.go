@@ -2,12 +2,10 @@ package repository
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/stivo-m/vise-resume/internal/adapters/database"
 	"github.com/stivo-m/vise-resume/internal/core/domain"
 	"github.com/stivo-m/vise-resume/internal/core/dto"
-	"github.com/stivo-m/vise-resume/internal/core/utils"
 	"gorm.io/gorm"
 )
 
@@ -20,25 +18,31 @@ func NewVerificationRepository(db *database.DB) *VerificationRepository {
 }
 
 func (r VerificationRepository) CreateCode(ctx context.Context, payload dto.VerificationDto) error {
-	current, _ := r.FindCode(ctx, payload)
-
-	if current != nil && current.ID != "" {
-		_ = r.DeleteCode(ctx, current.ID)
-	}
-
-	data := domain.Verifications{
-		UserId: payload.UserID,
-		Code:   payload.Code,
-		Type:   payload.Type,
-	}
-
-	utils.JsonLogger.Debug(fmt.Sprintf("verification code for user id %s is %s for type %s", data.UserId, data.Code, data.Type))
-
-	result := r.db.Db.Create(&data)
-	if result.Error != nil {
-		return result.Error
-	}
-	return nil
+	return r.db.WithinTransaction(ctx, func(ctx context.Context) error {
+		current, _ := r.FindCode(ctx, payload)
+
+		if current != nil && current.ID != "" {
+			if err := r.DeleteCode(ctx, current.ID); err != nil {
+				return err
+			}
+		}
+
+		data := domain.Verifications{
+			UserId: payload.UserID,
+			Code:   payload.Code,
+			Type:   payload.Type,
+			SentTo: payload.SentTo,
+		}
+		if !payload.ExpiresAt.IsZero() {
+			data.ExpiresAt = &payload.ExpiresAt
+		}
+
+		result := r.db.Conn(ctx).Create(&data)
+		if result.Error != nil {
+			return result.Error
+		}
+		return nil
+	})
 }
 
 func (r VerificationRepository) FindCode(ctx context.Context, payload dto.VerificationDto) (*domain.Verifications, error) {
@@ -46,9 +50,9 @@ func (r VerificationRepository) FindCode(ctx context.Context, payload dto.Verifi
 	var result *gorm.DB
 
 	if payload.UserID != "" {
-		result = r.db.Db.Where("user_id = ?", payload.UserID).Where("type = ?", payload.Type).First(&verification)
+		result = r.db.Conn(ctx).Where("user_id = ?", payload.UserID).Where("type = ?", payload.Type).First(&verification)
 	} else {
-		result = r.db.Db.Where("code = ?", payload.Code).Where("type = ?", payload.Type).First(&verification)
+		result = r.db.Conn(ctx).Where("code = ?", payload.Code).Where("type = ?", payload.Type).First(&verification)
 	}
 
 	if result.Error != nil {
@@ -58,8 +62,19 @@ func (r VerificationRepository) FindCode(ctx context.Context, payload dto.Verifi
 	return &verification, nil
 }
 
+// FindUserCodes lists every code ever sent to a user, including the ones that were used up
+func (r VerificationRepository) FindUserCodes(ctx context.Context, userId string) ([]domain.Verifications, error) {
+	var verifications []domain.Verifications
+	result := r.db.Conn(ctx).Unscoped().Where("user_id = ?", userId).Order("created_at").Find(&verifications)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return verifications, nil
+}
+
 func (r VerificationRepository) DeleteCode(ctx context.Context, id string) error {
-	result := r.db.Db.Delete(&domain.Verifications{Base: domain.Base{ID: id}})
+	result := r.db.Conn(ctx).Delete(&domain.Verifications{Base: domain.Base{ID: id}})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -70,3 +85,17 @@ func (r VerificationRepository) DeleteCode(ctx context.Context, id string) error
 
 	return nil
 }
+
+// ConsumeAttempt uses up one of the attempts of a code, in a single conditional update so
+// concurrent guesses cannot get past the limit. It reports false once maxAttempts were used.
+func (r VerificationRepository) ConsumeAttempt(ctx context.Context, id string, maxAttempts int) (bool, error) {
+	result := r.db.Conn(ctx).Model(&domain.Verifications{}).
+		Where("id = ? AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/adapters/middleware"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+type AccountHandler struct {
+	accountService ports.AccountService
+	userPort       ports.UserPort
+	tokenPort      ports.TokenService
+	auditPort      ports.AuditPort
+}
+
+func NewAccountHandler(
+	accountService ports.AccountService,
+	userPort ports.UserPort,
+	tokenPort ports.TokenService,
+	auditPort ports.AuditPort,
+) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+		userPort:       userPort,
+		tokenPort:      tokenPort,
+		auditPort:      auditPort,
+	}
+}
+
+func (h AccountHandler) RegisterAccountRoutes(router fiber.Router) {
+	authRouter := router.Group("/auth")
+	authRouter.Get(
+		"/profile/export",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.HandleExportAccount,
+	)
+
+	authRouter.Post(
+		"/profile/deletion-code",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.HandleRequestDeletionCode,
+	)
+
+	authRouter.Delete(
+		"/profile",
+		middleware.ValidationMiddleware(&dto.DeleteAccountDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.HandleDeleteAccount,
+	)
+}
+
+// Handles the process of downloading everything stored about a user as a ZIP archive
+func (h *AccountHandler) HandleExportAccount(c *fiber.Ctx) error {
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	res, err := h.accountService.ExportAccount(context.Background(), userId)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Account export failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	c.Set(fiber.HeaderContentType, res.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, res.FileName))
+	return c.Status(fiber.StatusOK).SendStream(bytes.NewReader(res.Content), len(res.Content))
+}
+
+// Handles the process of emailing a code that confirms the deletion of a user's account
+func (h *AccountHandler) HandleRequestDeletionCode(c *fiber.Ctx) error {
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	err := h.accountService.RequestDeletionCode(requestContext(c), userId)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to send a deletion code",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(failureStatus(c, err)).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"A code confirming the deletion of your account was sent to your email address",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of deleting a user's account, which is erased after a grace period
+func (h *AccountHandler) HandleDeleteAccount(c *fiber.Ctx) error {
+	var body dto.DeleteAccountDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	res, err := h.accountService.DeleteAccount(requestContext(c), userId, body)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to delete account",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Your account will be deleted. Log in again before then to keep it.",
+		res,
+	)
+	return c.Status(fiber.StatusAccepted).JSON(data)
+}
@@ -0,0 +1,128 @@
+package handlers_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/mocks"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportAccount(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest("GET", "/api/v1/auth/profile/export", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	assert.Nil(t, err)
+
+	files := map[string][]byte{}
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		assert.Nil(t, err)
+		files[file.Name], _ = io.ReadAll(reader)
+		reader.Close()
+	}
+	assert.Len(t, files, 4)
+
+	var profile dto.AccountProfileExportDto
+	assert.Nil(t, json.Unmarshal(files["profile.json"], &profile))
+	assert.Equal(t, user.Email, profile.Email)
+
+	var resumes []dto.ResumeDetailsDto
+	assert.Nil(t, json.Unmarshal(files["resumes.json"], &resumes))
+	assert.Len(t, resumes, 1)
+	assert.Equal(t, resume.ID, resumes[0].ID)
+	assert.Len(t, resumes[0].Experiences, 1)
+	assert.Len(t, resumes[0].Education, 1)
+
+	var sessions []dto.SessionDto
+	assert.Nil(t, json.Unmarshal(files["sessions.json"], &sessions))
+	assert.Len(t, sessions, 1)
+	assert.NotContains(t, string(files["sessions.json"]), token.AccessToken)
+}
+
+func TestDeleteAccount(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, requestStatus(t, app, "DELETE", "/api/v1/auth/profile", token.AccessToken, `{}`))
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "DELETE", "/api/v1/auth/profile", token.AccessToken, `{"password":"wrong"}`))
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, token.AccessToken))
+
+	assert.Equal(t, http.StatusAccepted, requestStatus(t, app, "DELETE", "/api/v1/auth/profile", token.AccessToken, `{"password":"password"}`))
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, token.AccessToken))
+
+	deleted := auditEvents(t, db, domain.AuditAccountDeleted)
+	if assert.Len(t, deleted, 2) {
+		assert.Equal(t, domain.AuditFailure, deleted[0].Outcome, "Expected the wrong password to be recorded")
+		assert.Equal(t, user.ID, deleted[1].ActorId)
+		assert.Equal(t, domain.AuditSuccess, deleted[1].Outcome)
+	}
+
+	// Logging in during the grace period keeps the account
+	login := loginTestUser(t, app, user.Email, "password")
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, login.AccessToken))
+
+	var stored domain.User
+	assert.Nil(t, db.Db.Where("id = ?", user.ID).First(&stored).Error)
+	assert.Nil(t, stored.DeletionRequestedAt)
+}
+
+func TestDeleteAccountWithAnEmailedCode(t *testing.T) {
+	// Accounts created by logging in with an identity provider have a password nobody knows
+	app, db, outbox, provider := setupIdentityTestServer(t)
+	status, login := completeExternalLogin(t, app, startExternalLogin(t, app, provider))
+	assert.Equal(t, http.StatusOK, status)
+	accessToken := login.Token.AccessToken
+
+	assert.Equal(t, http.StatusUnprocessableEntity, requestStatus(t, app, "DELETE", "/api/v1/auth/profile", accessToken, `{"code":"123"}`))
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "DELETE", "/api/v1/auth/profile", accessToken, `{"code":"000000"}`), "Expected a code to be requested first")
+
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "POST", "/api/v1/auth/profile/deletion-code", accessToken, ""))
+	assert.Equal(t, http.StatusTooManyRequests, requestStatus(t, app, "POST", "/api/v1/auth/profile/deletion-code", accessToken, ""))
+	message, ok := outbox.LastTo(provider.Account.Email)
+	assert.True(t, ok, "Expected a deletion code to be sent")
+	assert.Equal(t, "Confirm the deletion of your account", message.Subject)
+	code := emailCodePattern.FindString(message.Text)
+	assert.NotEmpty(t, code)
+
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "DELETE", "/api/v1/auth/profile", accessToken, fmt.Sprintf(`{"code":"%v"}`, wrong)))
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, accessToken))
+
+	assert.Equal(t, http.StatusAccepted, requestStatus(t, app, "DELETE", "/api/v1/auth/profile", accessToken, fmt.Sprintf(`{"code":"%v"}`, code)))
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, accessToken))
+
+	var stored domain.User
+	assert.Nil(t, db.Db.Where("id = ?", login.User.ID).First(&stored).Error)
+	assert.NotNil(t, stored.DeletionRequestedAt)
+}
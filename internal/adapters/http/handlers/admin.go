@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/adapters/middleware"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+type AdminHandler struct {
+	adminService  ports.AdminService
+	resumeService ports.ResumeService
+	auditService  ports.AuditService
+	userPort      ports.UserPort
+	tokenPort     ports.TokenService
+	rolePort      ports.RolePort
+	auditPort     ports.AuditPort
+}
+
+func NewAdminHandler(
+	adminService ports.AdminService,
+	resumeService ports.ResumeService,
+	auditService ports.AuditService,
+	userPort ports.UserPort,
+	tokenPort ports.TokenService,
+	rolePort ports.RolePort,
+	auditPort ports.AuditPort,
+) *AdminHandler {
+	return &AdminHandler{
+		adminService:  adminService,
+		resumeService: resumeService,
+		auditService:  auditService,
+		userPort:      userPort,
+		tokenPort:     tokenPort,
+		rolePort:      rolePort,
+		auditPort:     auditPort,
+	}
+}
+
+func (h AdminHandler) RegisterAdminRoutes(router fiber.Router) {
+	// Personal access tokens have no admin scope, so only logged in users reach these routes
+	adminRouter := router.Group("/admin", middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort))
+
+	canReadUsers := middleware.RequirePermission(h.rolePort, domain.PermissionUsersRead)
+	canManageUsers := middleware.RequirePermission(h.rolePort, domain.PermissionUsersManage)
+	canReadResumes := middleware.RequirePermission(h.rolePort, domain.PermissionResumesRead)
+	canManageRoles := middleware.RequirePermission(h.rolePort, domain.PermissionRolesManage)
+	canReadAudit := middleware.RequirePermission(h.rolePort, domain.PermissionAuditRead)
+
+	adminRouter.Get("/users", canReadUsers, h.HandleListUsers)
+	adminRouter.Get("/users/:id", canReadUsers, h.HandleFindUser)
+	adminRouter.Post("/users/:id/verify", canManageUsers, h.HandleVerifyUser)
+	adminRouter.Post("/users/:id/disable", canManageUsers, h.HandleDisableUser)
+	adminRouter.Post("/users/:id/enable", canManageUsers, h.HandleEnableUser)
+	adminRouter.Post("/users/:id/force-password-reset", canManageUsers, h.HandleForcePasswordReset)
+	adminRouter.Get("/users/:id/resumes", canReadResumes, h.HandleFindUserResumes)
+	adminRouter.Get("/resumes/:id", canReadResumes, h.HandleFindResume)
+
+	adminRouter.Get("/roles", canManageRoles, h.HandleListRoles)
+	adminRouter.Post(
+		"/users/:id/roles",
+		canManageRoles,
+		middleware.ValidationMiddleware(&dto.AssignRoleDto{}),
+		h.HandleAssignRole,
+	)
+	adminRouter.Delete("/users/:id/roles/:role", canManageRoles, h.HandleRemoveRole)
+
+	adminRouter.Get("/audit", canReadAudit, h.HandleSearchAuditEvents)
+	adminRouter.Get("/audit/verify", canReadAudit, h.HandleVerifyAuditLog)
+}
+
+// Handles the process of listing and searching users
+func (h *AdminHandler) HandleListUsers(c *fiber.Ctx) error {
+	var query dto.SearchUsersDto
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	res, err := h.adminService.ListUsers(context.Background(), query)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to list users",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Users obtained successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of showing a single user
+func (h *AdminHandler) HandleFindUser(c *fiber.Ctx) error {
+	res, err := h.adminService.FindUser(context.Background(), c.Params("id"))
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to find user",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"User obtained successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of verifying the email address of a user on their behalf
+func (h *AdminHandler) HandleVerifyUser(c *fiber.Ctx) error {
+	adminId := c.Locals(utils.USER_ID_KEY).(string)
+	if err := h.adminService.VerifyUser(requestContext(c), adminId, c.Params("id")); err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to verify user",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"User was verified successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of disabling a user
+func (h *AdminHandler) HandleDisableUser(c *fiber.Ctx) error {
+	adminId := c.Locals(utils.USER_ID_KEY).(string)
+	if err := h.adminService.DisableUser(requestContext(c), adminId, c.Params("id")); err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to disable user",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"User was disabled successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of enabling a disabled user
+func (h *AdminHandler) HandleEnableUser(c *fiber.Ctx) error {
+	adminId := c.Locals(utils.USER_ID_KEY).(string)
+	if err := h.adminService.EnableUser(requestContext(c), adminId, c.Params("id")); err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to enable user",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"User was enabled successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of making a user choose a new password before they can log in again
+func (h *AdminHandler) HandleForcePasswordReset(c *fiber.Ctx) error {
+	adminId := c.Locals(utils.USER_ID_KEY).(string)
+	if err := h.adminService.ForcePasswordReset(requestContext(c), adminId, c.Params("id")); err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to force a password reset",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"A password reset email was sent to the user",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of listing the resumes of any user
+func (h *AdminHandler) HandleFindUserResumes(c *fiber.Ctx) error {
+	userId := c.Params("id")
+	res, err := h.resumeService.FindResumes(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		dto.ResumeFilterDto{
+			UserId: userId,
+		},
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to find resumes",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Resumes obtained successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of showing any resume, whoever owns it
+func (h *AdminHandler) HandleFindResume(c *fiber.Ctx) error {
+	res, err := h.resumeService.FindAnyResume(context.Background(), c.Params("id"))
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to find resume",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Resume obtained successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of listing the roles users can be given
+func (h *AdminHandler) HandleListRoles(c *fiber.Ctx) error {
+	res, err := h.adminService.ListRoles(context.Background())
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to list roles",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Roles obtained successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of giving a role to a user
+func (h *AdminHandler) HandleAssignRole(c *fiber.Ctx) error {
+	var body dto.AssignRoleDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	adminId := c.Locals(utils.USER_ID_KEY).(string)
+	if err := h.adminService.AssignRole(requestContext(c), adminId, c.Params("id"), body); err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to assign role",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Role was assigned successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of taking a role away from a user
+func (h *AdminHandler) HandleRemoveRole(c *fiber.Ctx) error {
+	adminId := c.Locals(utils.USER_ID_KEY).(string)
+	if err := h.adminService.RemoveRole(requestContext(c), adminId, c.Params("id"), c.Params("role")); err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to remove role",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Role was removed successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of searching the audit log
+func (h *AdminHandler) HandleSearchAuditEvents(c *fiber.Ctx) error {
+	var query dto.AuditQueryDto
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if status, res := middleware.Validate(&query); res != nil {
+		return c.Status(status).JSON(res)
+	}
+
+	res, err := h.auditService.SearchEvents(context.Background(), query)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to search the audit log",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Audit events obtained successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of checking that no event of the audit log was tampered with
+func (h *AdminHandler) HandleVerifyAuditLog(c *fiber.Ctx) error {
+	res, err := h.auditService.VerifyLog(context.Background())
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to verify the audit log",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	message := "The audit log is intact"
+	if !res.Valid {
+		message = "The audit log was tampered with"
+	}
+
+	data := utils.FormatApiResponse(message, res)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
@@ -0,0 +1,234 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/database/repository"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/mocks"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestUserWithRole(t *testing.T, db *database.DB, role string) (*domain.User, *domain.Token) {
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	roleRepo := repository.NewRoleRepository(db)
+	found, err := roleRepo.FindRole(context.Background(), role)
+	assert.Nil(t, err)
+	assert.Nil(t, roleRepo.AssignRole(context.Background(), user.ID, found.ID))
+
+	return user, token
+}
+
+func adminRequest(t *testing.T, app *fiber.App, method string, path string, accessToken string, data interface{}) int {
+	req := httptest.NewRequest(method, path, nil)
+	if accessToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	}
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+
+	if data != nil {
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(data))
+	}
+	return resp.StatusCode
+}
+
+// auditEvents lists the events recorded for an action, oldest first
+func auditEvents(t *testing.T, db *database.DB, action string) []domain.AuditEvent {
+	var events []domain.AuditEvent
+	assert.Nil(t, db.Db.Where("action = ?", action).Order("id").Find(&events).Error)
+	return events
+}
+
+func TestAdminRoutesRequirePermission(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	_, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	assert.Equal(t, http.StatusForbidden, adminRequest(t, app, "GET", "/api/v1/admin/users", token.AccessToken, nil))
+	assert.Equal(t, http.StatusForbidden, adminRequest(t, app, "GET", "/api/v1/admin/roles", token.AccessToken, nil))
+	assert.Equal(t, http.StatusUnauthorized, adminRequest(t, app, "GET", "/api/v1/admin/users", "", nil))
+}
+
+func TestAdminSearchUsers(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	_, adminToken := createTestUserWithRole(t, db, "admin")
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	var list struct {
+		Data dto.UserListDto `json:"data"`
+	}
+	path := fmt.Sprintf("/api/v1/admin/users?search=%v&limit=5", strings.ToUpper(user.Email))
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "GET", path, adminToken.AccessToken, &list))
+	assert.Equal(t, int64(1), list.Data.Total)
+	assert.Equal(t, 5, list.Data.Limit)
+	assert.Equal(t, user.ID, list.Data.Users[0].ID)
+
+	var found struct {
+		Data dto.AdminUserDto `json:"data"`
+	}
+	path = fmt.Sprintf("/api/v1/admin/users/%v", user.ID)
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "GET", path, adminToken.AccessToken, &found))
+	assert.Equal(t, user.Email, found.Data.Email)
+	assert.Empty(t, found.Data.Roles)
+}
+
+func TestAdminDisableAndEnableUser(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	admin, adminToken := createTestUserWithRole(t, db, "admin")
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	path := fmt.Sprintf("/api/v1/admin/users/%v", user.ID)
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "POST", path+"/disable", adminToken.AccessToken, nil))
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, token.AccessToken))
+
+	payload := fmt.Sprintf(`{"email":"%v", "password": "password"}`, user.Email)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/login", "", payload))
+
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "POST", path+"/enable", adminToken.AccessToken, nil))
+	assert.NotEmpty(t, loginTestUser(t, app, user.Email, "password").AccessToken)
+
+	// Administrators can not lock themselves out
+	path = fmt.Sprintf("/api/v1/admin/users/%v/disable", admin.ID)
+	assert.Equal(t, http.StatusForbidden, adminRequest(t, app, "POST", path, adminToken.AccessToken, nil))
+
+	disabled := auditEvents(t, db, domain.AuditUserDisabled)
+	if assert.Len(t, disabled, 2) {
+		assert.Equal(t, admin.ID, disabled[0].ActorId, "Expected the administrator to be the actor")
+		assert.Equal(t, "user "+user.ID, disabled[0].Details)
+		assert.Equal(t, domain.AuditSuccess, disabled[0].Outcome)
+		assert.Equal(t, domain.AuditFailure, disabled[1].Outcome)
+	}
+	enabled := auditEvents(t, db, domain.AuditUserEnabled)
+	if assert.Len(t, enabled, 1) {
+		assert.Equal(t, admin.ID, enabled[0].ActorId)
+		assert.Equal(t, "user "+user.ID, enabled[0].Details)
+	}
+}
+
+func TestAdminVerifyUser(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	admin, adminToken := createTestUserWithRole(t, db, "admin")
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Db.Model(&domain.User{}).Where("id = ?", user.ID).Update("email_verified_at", nil).Error)
+
+	path := fmt.Sprintf("/api/v1/admin/users/%v/verify", user.ID)
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "POST", path, adminToken.AccessToken, nil))
+	assert.Equal(t, http.StatusForbidden, adminRequest(t, app, "POST", path, adminToken.AccessToken, nil))
+	assert.NotEmpty(t, loginTestUser(t, app, user.Email, "password").AccessToken)
+
+	verified := auditEvents(t, db, domain.AuditUserVerified)
+	if assert.Len(t, verified, 2) {
+		assert.Equal(t, admin.ID, verified[0].ActorId)
+		assert.Equal(t, "user "+user.ID, verified[0].Details)
+		assert.Equal(t, domain.AuditSuccess, verified[0].Outcome)
+		assert.Equal(t, domain.AuditFailure, verified[1].Outcome)
+	}
+}
+
+func TestAdminForcePasswordReset(t *testing.T) {
+	app, db, outbox, err := mocks.SetupTestServerWithOutbox()
+	assert.Nil(t, err)
+
+	admin, adminToken := createTestUserWithRole(t, db, "admin")
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	path := fmt.Sprintf("/api/v1/admin/users/%v/force-password-reset", user.ID)
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "POST", path, adminToken.AccessToken, nil))
+	forced := auditEvents(t, db, domain.AuditPasswordResetForced)
+	if assert.Len(t, forced, 1) {
+		assert.Equal(t, admin.ID, forced[0].ActorId)
+		assert.Equal(t, "user "+user.ID, forced[0].Details)
+	}
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, token.AccessToken))
+
+	payload := fmt.Sprintf(`{"email":"%v", "password": "password"}`, user.Email)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/login", "", payload))
+
+	message, ok := outbox.LastTo(user.Email)
+	assert.True(t, ok, "Expected a password reset email to be sent")
+	code := emailCodePattern.FindString(message.Text)
+
+	payload = fmt.Sprintf(`{"email": "%v", "code": "%v", "password": "new-password"}`, user.Email, code)
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "POST", "/api/v1/auth/reset-password", "", payload))
+	assert.NotEmpty(t, loginTestUser(t, app, user.Email, "new-password").AccessToken)
+}
+
+func TestSupportCanReadResumesButNotManageRoles(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	_, supportToken := createTestUserWithRole(t, db, "support")
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	var resumes struct {
+		Data []dto.ResumeDto `json:"data"`
+	}
+	path := fmt.Sprintf("/api/v1/admin/users/%v/resumes", user.ID)
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "GET", path, supportToken.AccessToken, &resumes))
+	assert.Len(t, resumes.Data, 1)
+
+	path = fmt.Sprintf("/api/v1/admin/resumes/%v", resume.ID)
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "GET", path, supportToken.AccessToken, nil))
+
+	assert.Equal(t, http.StatusForbidden, adminRequest(t, app, "GET", "/api/v1/admin/roles", supportToken.AccessToken, nil))
+	path = fmt.Sprintf("/api/v1/admin/users/%v/roles", user.ID)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", path, supportToken.AccessToken, `{"role":"admin"}`))
+}
+
+func TestAdminAssignAndRemoveRole(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	admin, adminToken := createTestUserWithRole(t, db, "admin")
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	path := fmt.Sprintf("/api/v1/admin/users/%v/roles", user.ID)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", path, adminToken.AccessToken, `{"role":"unknown"}`))
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "POST", path, adminToken.AccessToken, `{"role":"support"}`))
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "GET", "/api/v1/admin/users", token.AccessToken, nil))
+
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "DELETE", path+"/support", adminToken.AccessToken, nil))
+	assert.Equal(t, http.StatusForbidden, adminRequest(t, app, "GET", "/api/v1/admin/users", token.AccessToken, nil))
+
+	assigned := auditEvents(t, db, domain.AuditRoleAssigned)
+	if assert.Len(t, assigned, 2) {
+		assert.Equal(t, domain.AuditFailure, assigned[0].Outcome)
+		assert.Equal(t, admin.ID, assigned[1].ActorId)
+		assert.Equal(t, "user "+user.ID+", role support", assigned[1].Details)
+		assert.Equal(t, domain.AuditSuccess, assigned[1].Outcome)
+	}
+	removed := auditEvents(t, db, domain.AuditRoleRemoved)
+	if assert.Len(t, removed, 1) {
+		assert.Equal(t, admin.ID, removed[0].ActorId)
+		assert.Equal(t, "user "+user.ID+", role support", removed[0].Details)
+	}
+}
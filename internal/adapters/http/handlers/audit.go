@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/adapters/middleware"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// requestContext hands the client making the request to the usecases recording it in the audit log
+func requestContext(c *fiber.Ctx) context.Context {
+	return context.WithValue(context.Background(), utils.CLIENT_KEY, middleware.Client(c))
+}
+
+// Handles the process of listing the logins and account changes of the logged in user
+func (h *AuthHandler) handleListActivity(c *fiber.Ctx) error {
+	var query dto.AuditQueryDto
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if status, res := middleware.Validate(&query); res != nil {
+		return c.Status(status).JSON(res)
+	}
+
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	res, err := h.auditService.ListActivity(context.Background(), userId, query)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to list activity",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Activity obtained successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
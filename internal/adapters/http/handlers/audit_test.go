@@ -0,0 +1,103 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/mocks"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func listTestAuditEvents(t *testing.T, app *fiber.App, path string, accessToken string) dto.AuditEventListDto {
+	var list struct {
+		Data dto.AuditEventListDto `json:"data"`
+	}
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "GET", path, accessToken, &list))
+	return list.Data
+}
+
+func TestActivityListsLoginsAndLogouts(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	_, otherToken, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	payload := fmt.Sprintf(`{"email":"%v", "password": "wrong password"}`, user.Email)
+	req := httptest.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "audit-test")
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	first := loginTestUser(t, app, user.Email, "password")
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "POST", "/api/v1/auth/logout", first.AccessToken, ""))
+	token := loginTestUser(t, app, user.Email, "password")
+
+	activity := listTestAuditEvents(t, app, "/api/v1/auth/activity", token.AccessToken)
+	assert.Equal(t, int64(4), activity.Total)
+	actions := []string{}
+	for _, event := range activity.Events {
+		assert.Equal(t, user.ID, event.ActorId)
+		assert.NotEmpty(t, event.IpAddress)
+		actions = append(actions, event.Action+" "+event.Outcome)
+	}
+	assert.Equal(t, []string{
+		"auth.login success",
+		"auth.logout success",
+		"auth.login success",
+		"auth.login failure",
+	}, actions, "Expected the newest events first")
+	assert.Equal(t, "audit-test", activity.Events[3].UserAgent)
+
+	failures := listTestAuditEvents(t, app, "/api/v1/auth/activity?outcome=failure", token.AccessToken)
+	assert.Equal(t, int64(1), failures.Total)
+
+	// Users only see their own activity
+	assert.Empty(t, listTestAuditEvents(t, app, "/api/v1/auth/activity", otherToken.AccessToken).Events)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, requestStatus(t, app, "GET", "/api/v1/auth/activity?outcome=unknown", token.AccessToken, ""))
+	assert.Equal(t, http.StatusUnauthorized, adminRequest(t, app, "GET", "/api/v1/auth/activity", "", nil))
+}
+
+func TestAdminAuditLog(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+	_, supportToken := createTestUserWithRole(t, db, "support")
+	_, adminToken := createTestUserWithRole(t, db, "admin")
+
+	assert.Equal(t, http.StatusForbidden, adminRequest(t, app, "GET", "/api/v1/admin/audit", supportToken.AccessToken, nil))
+
+	// Refused tokens are recorded by the authentication middleware
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, "forged"))
+	rejected := listTestAuditEvents(t, app, "/api/v1/admin/audit?action=auth.token_rejected", adminToken.AccessToken)
+	assert.Equal(t, int64(1), rejected.Total)
+	assert.Equal(t, domain.AuditFailure, rejected.Events[0].Outcome)
+	assert.Empty(t, rejected.Events[0].ActorId)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, adminRequest(t, app, "GET", "/api/v1/admin/audit?since=yesterday", adminToken.AccessToken, nil))
+
+	var verification struct {
+		Data dto.AuditVerificationDto `json:"data"`
+	}
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "GET", "/api/v1/admin/audit/verify", adminToken.AccessToken, &verification))
+	assert.True(t, verification.Data.Valid)
+	assert.Equal(t, int64(1), verification.Data.Events)
+
+	// Changing an event behind the application's back breaks the chain
+	assert.Nil(t, db.Db.Exec("DROP TRIGGER audit_events_no_update").Error)
+	assert.Nil(t, db.Db.Model(&domain.AuditEvent{}).Where("id = ?", 1).Update("outcome", domain.AuditSuccess).Error)
+
+	assert.Equal(t, http.StatusOK, adminRequest(t, app, "GET", "/api/v1/admin/audit/verify", adminToken.AccessToken, &verification))
+	assert.False(t, verification.Data.Valid)
+	assert.Equal(t, int64(1), *verification.Data.BrokenAt)
+}
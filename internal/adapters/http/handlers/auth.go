@@ -1,8 +1,6 @@
 package handlers
 
 import (
-	"context"
-
 	"github.com/gofiber/fiber/v2"
 	"github.com/stivo-m/vise-resume/internal/adapters/middleware"
 	"github.com/stivo-m/vise-resume/internal/core/dto"
@@ -11,20 +9,29 @@ import (
 )
 
 type AuthHandler struct {
-	userService ports.UserService
-	userPort    ports.UserPort
-	tokenPort   ports.TokenService
+	userService  ports.UserService
+	auditService ports.AuditService
+	userPort     ports.UserPort
+	tokenPort    ports.TokenService
+	auditPort    ports.AuditPort
+	rateLimiter  ports.RateLimiter
 }
 
 func NewAuthHandler(
 	userService ports.UserService,
+	auditService ports.AuditService,
 	userPort ports.UserPort,
 	tokenPort ports.TokenService,
+	auditPort ports.AuditPort,
+	rateLimiter ports.RateLimiter,
 ) *AuthHandler {
 	return &AuthHandler{
-		userService: userService,
-		userPort:    userPort,
-		tokenPort:   tokenPort,
+		userService:  userService,
+		auditService: auditService,
+		userPort:     userPort,
+		tokenPort:    tokenPort,
+		auditPort:    auditPort,
+		rateLimiter:  rateLimiter,
 	}
 }
 
@@ -38,46 +45,159 @@ func (h AuthHandler) RegisterAuthRoutes(router fiber.Router) {
 
 	authRouter.Post(
 		"/login",
+		middleware.RateLimitMiddleware(h.rateLimiter, "login"),
 		middleware.ValidationMiddleware(&dto.LoginDto{}),
 		h.handleLogin,
 	)
 
+	authRouter.Post(
+		"/magic-link",
+		middleware.RateLimitRequestsMiddleware(h.rateLimiter, "magic-link"),
+		middleware.ValidationMiddleware(&dto.EmailDto{}),
+		h.handleRequestMagicLink,
+	)
+	authRouter.Get("/magic-link/callback", h.handleMagicLinkCallback)
+
+	authRouter.Get("/oauth/:provider", h.handleExternalLogin)
+	authRouter.Get("/oauth/:provider/callback", h.handleExternalLoginCallback)
+
+	authRouter.Post(
+		"/2fa/setup",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleTwoFactorSetup,
+	)
+
+	authRouter.Post(
+		"/2fa/enable",
+		middleware.ValidationMiddleware(&dto.TwoFactorCodeDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleTwoFactorEnable,
+	)
+
+	authRouter.Post(
+		"/2fa/verify",
+		middleware.RateLimitMiddleware(h.rateLimiter, "2fa-verify"),
+		middleware.ValidationMiddleware(&dto.TwoFactorVerifyDto{}),
+		h.handleTwoFactorVerify,
+	)
+
+	authRouter.Post(
+		"/refresh",
+		middleware.ValidationMiddleware(&dto.RefreshTokenDto{}),
+		h.handleRefreshToken,
+	)
+
 	authRouter.Post(
 		"/verify-email",
+		middleware.RateLimitMiddleware(h.rateLimiter, "verify-email"),
 		middleware.ValidationMiddleware(&dto.VerificationDto{}),
 		h.handleEmailVerification,
 	)
 
+	authRouter.Post(
+		"/resend-verification",
+		middleware.RateLimitRequestsMiddleware(h.rateLimiter, "resend-verification"),
+		middleware.ValidationMiddleware(&dto.EmailDto{}),
+		h.handleResendVerification,
+	)
+
 	authRouter.Post(
 		"/forgot-password",
+		middleware.RateLimitRequestsMiddleware(h.rateLimiter, "forgot-password"),
 		middleware.ValidationMiddleware(&dto.EmailDto{}),
 		h.handleForgotPassword,
 	)
 
 	authRouter.Post(
 		"/reset-password",
+		middleware.RateLimitMiddleware(h.rateLimiter, "reset-password"),
 		middleware.ValidationMiddleware(&dto.ResetPasswordDto{}),
 		h.handleResetPassword,
 	)
 
+	authRouter.Post(
+		"/password",
+		middleware.ValidationMiddleware(&dto.ChangePasswordDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleChangePassword,
+	)
+
 	authRouter.Post(
 		"/logout",
-		middleware.AuthMiddleware(h.tokenPort, h.userPort),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
 		h.handleLogout,
 	)
 
+	authRouter.Post(
+		"/logout-all",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleLogoutAll,
+	)
+
+	authRouter.Get(
+		"/activity",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleListActivity,
+	)
+
+	authRouter.Get(
+		"/sessions",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleListSessions,
+	)
+
+	authRouter.Delete(
+		"/sessions/:id",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleRevokeSession,
+	)
+
+	authRouter.Post(
+		"/tokens",
+		middleware.ValidationMiddleware(&dto.CreatePersonalAccessTokenDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleCreatePersonalAccessToken,
+	)
+
+	authRouter.Get(
+		"/tokens",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleListPersonalAccessTokens,
+	)
+
+	authRouter.Delete(
+		"/tokens/:id",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleRevokePersonalAccessToken,
+	)
+
 	authRouter.Get(
 		"/profile",
-		middleware.AuthMiddleware(h.tokenPort, h.userPort),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
 		h.handleShowProfile,
 	)
 
 	authRouter.Patch(
 		"/profile",
 		middleware.ValidationMiddleware(&dto.UpdateUserDto{}),
-		middleware.AuthMiddleware(h.tokenPort, h.userPort),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
 		h.handleUpdateUserInfo,
 	)
+
+	authRouter.Post(
+		"/profile/email",
+		middleware.ValidationMiddleware(&dto.ChangeEmailDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleRequestEmailChange,
+	)
+
+	authRouter.Post(
+		"/profile/email/confirm",
+		middleware.RateLimitMiddleware(h.rateLimiter, "confirm-email-change"),
+		middleware.ValidationMiddleware(&dto.ConfirmEmailChangeDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort),
+		h.handleConfirmEmailChange,
+	)
 }
 
 // Handles the process of registering a user
@@ -87,13 +207,13 @@ func (h *AuthHandler) handleRegistration(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	res, err := h.userService.RegisterUser(context.Background(), body)
+	res, err := h.userService.RegisterUser(requestContext(c), body)
 	if err != nil {
 		data := utils.FormatApiResponse(
 			"Registration failed",
 			fiber.Map{"error": err.Error()},
 		)
-		return c.Status(fiber.StatusForbidden).JSON(data)
+		return c.Status(failureStatus(c, err)).JSON(data)
 	}
 
 	data := utils.FormatApiResponse(
@@ -110,7 +230,7 @@ func (h *AuthHandler) handleLogin(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	res, err := h.userService.LoginUser(context.Background(), body)
+	res, err := h.userService.LoginUser(requestContext(c), body)
 	if err != nil {
 		data := utils.FormatApiResponse(
 			"Login failed",
@@ -119,8 +239,33 @@ func (h *AuthHandler) handleLogin(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(data)
 	}
 
+	message := "User was logged in successfully"
+	if res.TwoFactor != nil {
+		message = "Two-factor authentication is required to complete the login"
+	}
+
+	data := utils.FormatApiResponse(message, res)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of exchanging a refresh token for a new token pair
+func (h *AuthHandler) handleRefreshToken(c *fiber.Ctx) error {
+	var body dto.RefreshTokenDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	res, err := h.userService.RefreshToken(requestContext(c), body)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Token refresh failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
 	data := utils.FormatApiResponse(
-		"User was logged in successfully",
+		"Token was refreshed successfully",
 		res,
 	)
 	return c.Status(fiber.StatusOK).JSON(data)
@@ -133,7 +278,7 @@ func (h *AuthHandler) handleEmailVerification(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	err := h.userService.VerifyEmailAddress(context.Background(), body)
+	err := h.userService.VerifyEmailAddress(requestContext(c), body)
 	if err != nil {
 		data := utils.FormatApiResponse(
 			"Verification failed",
@@ -146,6 +291,29 @@ func (h *AuthHandler) handleEmailVerification(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(data)
 }
 
+// Handles the process of sending a new email verification code
+func (h *AuthHandler) handleResendVerification(c *fiber.Ctx) error {
+	var body dto.EmailDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	err := h.userService.ResendVerification(requestContext(c), body)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Verification resend failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(failureStatus(c, err)).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"If an account uses this email address and has not verified it, a new verification code was sent to it",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
 // Handles the process of forgetting a user's password
 func (h *AuthHandler) handleForgotPassword(c *fiber.Ctx) error {
 	var body dto.EmailDto
@@ -153,17 +321,17 @@ func (h *AuthHandler) handleForgotPassword(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	err := h.userService.ForgetPassword(context.Background(), body)
+	err := h.userService.ForgetPassword(requestContext(c), body)
 	if err != nil {
 		data := utils.FormatApiResponse(
 			"Password reset failed",
 			fiber.Map{"error": err.Error()},
 		)
-		return c.Status(fiber.StatusForbidden).JSON(data)
+		return c.Status(failureStatus(c, err)).JSON(data)
 	}
 
 	data := utils.FormatApiResponse(
-		"Instructions have been sent to the registered email address on how to reset your password",
+		"If an account uses this email address, instructions on how to reset the password were sent to it",
 		nil,
 	)
 	return c.Status(fiber.StatusOK).JSON(data)
@@ -176,13 +344,13 @@ func (h *AuthHandler) handleResetPassword(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	err := h.userService.ResetPassword(context.Background(), body)
+	err := h.userService.ResetPassword(requestContext(c), body)
 	if err != nil {
 		data := utils.FormatApiResponse(
 			"Password reset failed",
 			fiber.Map{"error": err.Error()},
 		)
-		return c.Status(fiber.StatusForbidden).JSON(data)
+		return c.Status(failureStatus(c, err)).JSON(data)
 	}
 
 	data := utils.FormatApiResponse(
@@ -192,10 +360,35 @@ func (h *AuthHandler) handleResetPassword(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(data)
 }
 
+// Handles the process of changing the password of a logged in user
+func (h *AuthHandler) handleChangePassword(c *fiber.Ctx) error {
+	var body dto.ChangePasswordDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	token := c.Locals(utils.ACCESS_TOKEN_KEY).(string)
+	err := h.userService.ChangePassword(requestContext(c), userId, token, body)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Password change failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(failureStatus(c, err)).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Password was changed successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
 // Handles the process of resetting a user's account
 func (h *AuthHandler) handleLogout(c *fiber.Ctx) error {
 	accessToken := c.Locals(utils.ACCESS_TOKEN_KEY).(string)
-	err := h.userService.LogoutUser(context.Background(), accessToken)
+	err := h.userService.LogoutUser(requestContext(c), accessToken)
 	if err != nil {
 		data := utils.FormatApiResponse(
 			"Logout failed",
@@ -211,10 +404,76 @@ func (h *AuthHandler) handleLogout(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(data)
 }
 
+// Handles the process of signing a user out of every session, optionally keeping the current one
+func (h *AuthHandler) handleLogoutAll(c *fiber.Ctx) error {
+	var body dto.LogoutAllDto
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+		}
+	}
+
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	accessToken := c.Locals(utils.ACCESS_TOKEN_KEY).(string)
+	err := h.userService.LogoutAll(requestContext(c), userId, accessToken, body.KeepCurrent)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Logout failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"All sessions were logged out successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of listing a user's active sessions
+func (h *AuthHandler) handleListSessions(c *fiber.Ctx) error {
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	accessToken := c.Locals(utils.ACCESS_TOKEN_KEY).(string)
+	sessions, err := h.userService.ListSessions(requestContext(c), userId, accessToken)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to list sessions",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Active sessions",
+		sessions,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of revoking one of a user's sessions
+func (h *AuthHandler) handleRevokeSession(c *fiber.Ctx) error {
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	err := h.userService.RevokeSession(requestContext(c), userId, c.Params("id"))
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to revoke session",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Session was revoked successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
 // Handles the process of showing a user's profile
 func (h *AuthHandler) handleShowProfile(c *fiber.Ctx) error {
 	userId := c.Locals(utils.USER_ID_KEY).(string)
-	user, err := h.userService.ShowProfile(context.Background(), userId)
+	user, err := h.userService.ShowProfile(requestContext(c), userId)
 	if err != nil {
 		data := utils.FormatApiResponse(
 			"Unable to show profile",
@@ -242,7 +501,7 @@ func (h *AuthHandler) handleUpdateUserInfo(c *fiber.Ctx) error {
 	}
 	userId := c.Locals(utils.USER_ID_KEY).(string)
 
-	err := h.userService.UpdateUser(context.Background(), userId, updates)
+	err := h.userService.UpdateUser(requestContext(c), userId, updates)
 	if err != nil {
 		data := utils.FormatApiResponse(
 			"User update failed",
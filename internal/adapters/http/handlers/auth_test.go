@@ -1,18 +1,29 @@
 package handlers_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"regexp"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
 	"github.com/stivo-m/vise-resume/internal/core/mocks"
 	"github.com/stivo-m/vise-resume/internal/core/test"
 	"github.com/stretchr/testify/assert"
 )
 
+// strongTestPassword meets the password policy, unlike the "password" of the test users
+const strongTestPassword = "plum-Quartz-41-river"
+
 func TestUserRegistrationWithoutBody(t *testing.T) {
 	app, _, err := mocks.SetupTestServer()
 	assert.Nil(t, err)
@@ -71,7 +82,7 @@ func TestUserRegistrationSuccessful(t *testing.T) {
 	assert.Nil(t, err)
 
 	email := gofakeit.Email()
-	payload := fmt.Sprintf(`{"full_name":"John Doe","email":"%v", "password": "password"}`, email)
+	payload := fmt.Sprintf(`{"full_name":"John Doe","email":"%v", "password": "%v"}`, email, strongTestPassword)
 
 	req := httptest.NewRequest("POST", "/api/v1/auth/register", strings.NewReader(payload))
 	req.Header.Set("Content-Type", "application/json")
@@ -92,7 +103,7 @@ func TestUserRegistrationFailsForSameEmail(t *testing.T) {
 	assert.Nil(t, err)
 
 	email := gofakeit.Email()
-	payload := fmt.Sprintf(`{"full_name":"John Doe","email":"%v", "password": "password"}`, email)
+	payload := fmt.Sprintf(`{"full_name":"John Doe","email":"%v", "password": "%v"}`, email, strongTestPassword)
 
 	req := httptest.NewRequest("POST", "/api/v1/auth/register", strings.NewReader(payload))
 	req.Header.Set("Content-Type", "application/json")
@@ -142,7 +153,7 @@ func TestLoginWithUnverifiedEmail(t *testing.T) {
 	assert.Nil(t, err)
 
 	email := gofakeit.Email()
-	payload := fmt.Sprintf(`{"full_name":"John Doe","email":"%v", "password": "password"}`, email)
+	payload := fmt.Sprintf(`{"full_name":"John Doe","email":"%v", "password": "%v"}`, email, strongTestPassword)
 
 	req := httptest.NewRequest("POST", "/api/v1/auth/register", strings.NewReader(payload))
 	req.Header.Set("Content-Type", "application/json")
@@ -152,7 +163,7 @@ func TestLoginWithUnverifiedEmail(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, resp)
 
-	payload = fmt.Sprintf(`{"email":"%v", "password": "password"}`, email)
+	payload = fmt.Sprintf(`{"email":"%v", "password": "%v"}`, email, strongTestPassword)
 	req = httptest.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(payload))
 	req.Header.Set("Content-Type", "application/json")
 	resp, err = app.Test(req)
@@ -187,6 +198,26 @@ func TestLoginWithVerifiedEmail(t *testing.T) {
 	assert.NotContains(t, string(body), `"email address is not verified"`)
 }
 
+func TestLoginRehashesLegacyPasswords(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	// The test users are created with a bcrypt hash, like the accounts made before Argon2id
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(user.Password.Value, "$2a$"))
+
+	loginTestUser(t, app, user.Email, "password")
+
+	var password domain.Password
+	assert.Nil(t, db.Db.Where("user_id = ?", user.ID).First(&password).Error)
+	assert.True(t, strings.HasPrefix(password.Value, "$argon2id$"), "Expected the password to be rehashed")
+
+	assert.NotEmpty(t, loginTestUser(t, app, user.Email, "password").AccessToken)
+	payload := fmt.Sprintf(`{"email":"%v", "password": "wrong"}`, user.Email)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/login", "", payload))
+}
+
 func TestShowProfile(t *testing.T) {
 	app, db, err := mocks.SetupTestServer()
 	assert.Nil(t, err)
@@ -229,3 +260,563 @@ func TestUpdateUserName(t *testing.T) {
 	assert.Contains(t, string(body), `"User updated successfully"`)
 	assert.NotContains(t, string(body), `"User update failed"`)
 }
+
+// Email Tests
+
+var emailCodePattern = regexp.MustCompile(`\b\d{6}\b`)
+
+func TestRegistrationEmailsAVerificationCode(t *testing.T) {
+	app, _, outbox, err := mocks.SetupTestServerWithOutbox()
+	assert.Nil(t, err)
+
+	email := gofakeit.Email()
+	payload := fmt.Sprintf(`{"full_name":"John Doe","email":"%v", "password": "%v"}`, email, strongTestPassword)
+	req := httptest.NewRequest("POST", "/api/v1/auth/register", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	message, ok := outbox.LastTo(email)
+	assert.True(t, ok, "Expected a verification email to be sent")
+	assert.Equal(t, "Verify your email address", message.Subject)
+	assert.Contains(t, message.Text, "John Doe")
+	code := emailCodePattern.FindString(message.Text)
+	assert.NotEmpty(t, code)
+	assert.Contains(t, message.HTML, code)
+
+	payload = fmt.Sprintf(`{"email":"%v", "code": "%v", "type": "email-verification"}`, email, code)
+	req = httptest.NewRequest("POST", "/api/v1/auth/verify-email", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	payload = fmt.Sprintf(`{"email":"%v", "password": "%v"}`, email, strongTestPassword)
+	req = httptest.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestForgotPasswordEmailsAResetCode(t *testing.T) {
+	app, db, outbox, err := mocks.SetupTestServerWithOutbox()
+	assert.Nil(t, err)
+
+	user, _, _ := test.GetAuthenticatedTestUser(db)
+
+	payload := fmt.Sprintf(`{"email":"%v"}`, user.Email)
+	req := httptest.NewRequest("POST", "/api/v1/auth/forgot-password", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	message, ok := outbox.LastTo(user.Email)
+	assert.True(t, ok, "Expected a password reset email to be sent")
+	assert.Equal(t, "Reset your password", message.Subject)
+	code := emailCodePattern.FindString(message.Text)
+	assert.NotEmpty(t, code)
+
+	payload = fmt.Sprintf(`{"email": "%v", "code": "%v", "password": "new-password"}`, user.Email, code)
+	req = httptest.NewRequest("POST", "/api/v1/auth/reset-password", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	payload = fmt.Sprintf(`{"email":"%v", "password": "new-password"}`, user.Email)
+	req = httptest.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// Refresh Token Tests
+
+func loginTestUser(t *testing.T, app *fiber.App, email string, password string) dto.TokenResponse {
+	payload := fmt.Sprintf(`{"email":"%v", "password": "%v"}`, email, password)
+	req := httptest.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var login struct {
+		Data dto.LoginResponse `json:"data"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&login))
+	if login.Data.Token == nil {
+		return dto.TokenResponse{}
+	}
+	return *login.Data.Token
+}
+
+func refreshTestToken(t *testing.T, app *fiber.App, refreshToken string) (int, dto.TokenResponse) {
+	payload := fmt.Sprintf(`{"refresh_token":"%v"}`, refreshToken)
+	req := httptest.NewRequest("POST", "/api/v1/auth/refresh", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+
+	var refreshed struct {
+		Data dto.LoginResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&refreshed)
+	if refreshed.Data.Token == nil {
+		return resp.StatusCode, dto.TokenResponse{}
+	}
+	return resp.StatusCode, *refreshed.Data.Token
+}
+
+func profileStatus(t *testing.T, app *fiber.App, accessToken string) int {
+	req := httptest.NewRequest("GET", "/api/v1/auth/profile", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	return resp.StatusCode
+}
+
+func TestLoginIssuesRefreshToken(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, _, _ := test.GetAuthenticatedTestUser(db)
+	tokens := loginTestUser(t, app, user.Email, "password")
+
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.Equal(t, 900, tokens.ExpiresIn)
+
+	// The refresh token can not be used as an access token
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, tokens.RefreshToken))
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, _, _ := test.GetAuthenticatedTestUser(db)
+	tokens := loginTestUser(t, app, user.Email, "password")
+
+	status, rotated := refreshTestToken(t, app, tokens.RefreshToken)
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotEqual(t, tokens.RefreshToken, rotated.RefreshToken)
+	assert.NotEqual(t, tokens.AccessToken, rotated.AccessToken)
+
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, rotated.AccessToken))
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, tokens.AccessToken), "Expected the replaced access token to be revoked")
+
+	status, _ = refreshTestToken(t, app, rotated.RefreshToken)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, _, _ := test.GetAuthenticatedTestUser(db)
+	tokens := loginTestUser(t, app, user.Email, "password")
+	other := loginTestUser(t, app, user.Email, "password")
+
+	status, rotated := refreshTestToken(t, app, tokens.RefreshToken)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = refreshTestToken(t, app, tokens.RefreshToken)
+	assert.Equal(t, http.StatusForbidden, status, "Expected a reused refresh token to be rejected")
+
+	status, _ = refreshTestToken(t, app, rotated.RefreshToken)
+	assert.Equal(t, http.StatusForbidden, status, "Expected the family to be revoked after reuse")
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, rotated.AccessToken))
+
+	// Other logins of the same user are not affected
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, other.AccessToken))
+	status, _ = refreshTestToken(t, app, other.RefreshToken)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestLogoutRevokesRefreshToken(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, _, _ := test.GetAuthenticatedTestUser(db)
+	tokens := loginTestUser(t, app, user.Email, "password")
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/logout", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokens.AccessToken))
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	status, _ := refreshTestToken(t, app, tokens.RefreshToken)
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+func TestRefreshWithUnknownToken(t *testing.T) {
+	app, _, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	status, _ := refreshTestToken(t, app, "unknown")
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+// Session Tests
+
+func listTestSessions(t *testing.T, app *fiber.App, accessToken string) []dto.SessionDto {
+	req := httptest.NewRequest("GET", "/api/v1/auth/sessions", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	req.Header.Set("User-Agent", "session-test")
+	resp, err := app.Test(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var sessions struct {
+		Data []dto.SessionDto `json:"data"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&sessions))
+	return sessions.Data
+}
+
+func TestListSessions(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, _, _ := test.GetAuthenticatedTestUser(db)
+	first := loginTestUser(t, app, user.Email, "password")
+	second := loginTestUser(t, app, user.Email, "password")
+
+	sessions := listTestSessions(t, app, second.AccessToken)
+	assert.Len(t, sessions, 3)
+
+	var current *dto.SessionDto
+	for i := range sessions {
+		if sessions[i].Current {
+			current = &sessions[i]
+		}
+	}
+	assert.NotNil(t, current, "Expected the requesting session to be marked as current")
+	assert.Equal(t, "session-test", current.UserAgent)
+	assert.NotEmpty(t, current.IpAddress)
+	assert.NotNil(t, current.LastUsedAt)
+
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, first.AccessToken))
+}
+
+func TestRevokeSession(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, _ := test.GetAuthenticatedTestUser(db)
+	other := loginTestUser(t, app, user.Email, "password")
+
+	var otherSession dto.SessionDto
+	for _, session := range listTestSessions(t, app, token.AccessToken) {
+		if !session.Current {
+			otherSession = session
+		}
+	}
+	assert.NotEmpty(t, otherSession.ID)
+
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/auth/sessions/%s", otherSession.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, other.AccessToken))
+	status, _ := refreshTestToken(t, app, other.RefreshToken)
+	assert.Equal(t, http.StatusForbidden, status)
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, token.AccessToken))
+}
+
+func TestRevokeSessionOfAnotherUser(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	_, token, _ := test.GetAuthenticatedTestUser(db)
+	_, otherToken, _ := test.GetAuthenticatedTestUser(db)
+
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/auth/sessions/%s", otherToken.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, otherToken.AccessToken))
+}
+
+func TestLogoutAllKeepingCurrentSession(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, _ := test.GetAuthenticatedTestUser(db)
+	other := loginTestUser(t, app, user.Email, "password")
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/logout-all", strings.NewReader(`{"keep_current": true}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, other.AccessToken))
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, token.AccessToken))
+	assert.Len(t, listTestSessions(t, app, token.AccessToken), 1)
+}
+
+func TestLogoutAll(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, _ := test.GetAuthenticatedTestUser(db)
+	other := loginTestUser(t, app, user.Email, "password")
+
+	req := httptest.NewRequest("POST", "/api/v1/auth/logout-all", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, other.AccessToken))
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, token.AccessToken))
+}
+
+// Verification Limit Tests
+
+func TestResendVerificationCooldown(t *testing.T) {
+	app, _, outbox, err := mocks.SetupTestServerWithOutbox()
+	assert.Nil(t, err)
+
+	email := gofakeit.Email()
+	payload := fmt.Sprintf(`{"full_name":"John Doe","email":"%v", "password": "%v"}`, email, strongTestPassword)
+	req := httptest.NewRequest("POST", "/api/v1/auth/register", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	payload = fmt.Sprintf(`{"email":"%v"}`, email)
+	req = httptest.NewRequest("POST", "/api/v1/auth/resend-verification", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(req)
+
+	// The cooldown is not revealed, it would tell the address has an account
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, outbox.Messages(), 1, "Expected no new email during the cooldown")
+}
+
+func TestResendVerificationForVerifiedUser(t *testing.T) {
+	app, db, outbox, err := mocks.SetupTestServerWithOutbox()
+	assert.Nil(t, err)
+
+	user, _, _ := test.GetAuthenticatedTestUser(db)
+
+	payload := fmt.Sprintf(`{"email":"%v"}`, user.Email)
+	req := httptest.NewRequest("POST", "/api/v1/auth/resend-verification", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, outbox.Messages(), "Expected no code for an address that is already verified")
+}
+
+func TestEmailSendingRoutesDoNotTellWhichAddressesHaveAccounts(t *testing.T) {
+	for _, path := range []string{"/api/v1/auth/resend-verification", "/api/v1/auth/forgot-password"} {
+		app, db, err := mocks.SetupTestServer()
+		assert.Nil(t, err)
+
+		verified, _, err := test.GetAuthenticatedTestUser(db)
+		assert.Nil(t, err)
+		unverified, _, err := test.GetAuthenticatedTestUser(db)
+		assert.Nil(t, err)
+		assert.Nil(t, db.Db.Model(&domain.User{}).Where("id = ?", unverified.ID).Update("email_verified_at", nil).Error)
+
+		var answers []string
+		for _, email := range []string{unverified.Email, verified.Email, gofakeit.Email()} {
+			req := httptest.NewRequest("POST", path, strings.NewReader(fmt.Sprintf(`{"email":"%v"}`, email)))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req)
+			assert.Nil(t, err)
+
+			body, _ := io.ReadAll(resp.Body)
+			answers = append(answers, fmt.Sprintf("%d %s", resp.StatusCode, body))
+		}
+		assert.Equal(t, answers[0], answers[1], path)
+		assert.Equal(t, answers[0], answers[2], path)
+		assert.True(t, strings.HasPrefix(answers[0], "200 "), path)
+	}
+}
+
+func TestVerificationCodeIsInvalidatedAfterFailedAttempts(t *testing.T) {
+	app, _, outbox, err := mocks.SetupTestServerWithOutbox()
+	assert.Nil(t, err)
+
+	email := gofakeit.Email()
+	payload := fmt.Sprintf(`{"full_name":"John Doe","email":"%v", "password": "%v"}`, email, strongTestPassword)
+	req := httptest.NewRequest("POST", "/api/v1/auth/register", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	message, _ := outbox.LastTo(email)
+	code := emailCodePattern.FindString(message.Text)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	verify := func(code string) *http.Response {
+		payload := fmt.Sprintf(`{"email":"%v", "code": "%v", "type": "email-verification"}`, email, code)
+		req := httptest.NewRequest("POST", "/api/v1/auth/verify-email", strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusForbidden, verify(wrong).StatusCode)
+	}
+
+	// The failed attempts also throttle the client for a moment
+	resp = verify(code)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	assert.Nil(t, err)
+	time.Sleep(time.Duration(retryAfter) * time.Second)
+
+	assert.Equal(t, http.StatusForbidden, verify(code).StatusCode, "Expected the code to stop working after too many attempts")
+}
+
+func TestLoginIsThrottledAfterRepeatedFailures(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, _, _ := test.GetAuthenticatedTestUser(db)
+
+	login := func(password string) *http.Response {
+		payload := fmt.Sprintf(`{"email":"%v", "password": "%v"}`, user.Email, password)
+		req := httptest.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		return resp
+	}
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, http.StatusForbidden, login("wrong-password").StatusCode)
+	}
+
+	// The fifth failure starts the backoff
+	assert.Equal(t, http.StatusForbidden, login("wrong-password").StatusCode)
+
+	resp := login("password")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode, "Expected even the right password to wait out the backoff")
+	assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+
+	retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	assert.Nil(t, err)
+	time.Sleep(time.Duration(retryAfter) * time.Second)
+
+	assert.Equal(t, http.StatusOK, login("password").StatusCode)
+}
+
+func TestEmailSendingRoutesAreThrottled(t *testing.T) {
+	for _, path := range []string{"/api/v1/auth/magic-link", "/api/v1/auth/resend-verification", "/api/v1/auth/forgot-password"} {
+		app, _, err := mocks.SetupTestServer()
+		assert.Nil(t, err)
+
+		// Every request counts, even the ones for addresses nobody uses
+		for i := 0; i < 5; i++ {
+			status := requestStatus(t, app, "POST", path, "", fmt.Sprintf(`{"email":"%v"}`, gofakeit.Email()))
+			assert.NotEqual(t, http.StatusTooManyRequests, status, path)
+		}
+		status := requestStatus(t, app, "POST", path, "", fmt.Sprintf(`{"email":"%v"}`, gofakeit.Email()))
+		assert.Equal(t, http.StatusTooManyRequests, status, path)
+	}
+}
+
+func TestEmailChangeConfirmationIsThrottled(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	_, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/profile/email/confirm", token.AccessToken, `{"code":"000000"}`))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, requestStatus(t, app, "POST", "/api/v1/auth/profile/email/confirm", token.AccessToken, `{"code":"000000"}`))
+}
+
+func TestUserRegistrationWithWeakPassword(t *testing.T) {
+	app, _, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	for _, password := range []string{"password", "aaaaaaaaaa", "abcdefghij", "john-doe-2024!"} {
+		payload := fmt.Sprintf(`{"full_name":"John Doe","email":"%v", "password": "%v"}`, gofakeit.Email(), password)
+		req := httptest.NewRequest("POST", "/api/v1/auth/register", strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode, "Expected %q to be refused", password)
+	}
+}
+
+// Password Change Tests
+
+func TestChangePassword(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	payload := fmt.Sprintf(`{"current_password":"wrong","password":"%v"}`, strongTestPassword)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/password", token.AccessToken, payload))
+
+	payload = `{"current_password":"password","password":"qwerty123"}`
+	assert.Equal(t, http.StatusUnprocessableEntity, requestStatus(t, app, "POST", "/api/v1/auth/password", token.AccessToken, payload))
+
+	payload = fmt.Sprintf(`{"current_password":"password","password":"%v"}`, strongTestPassword)
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "POST", "/api/v1/auth/password", token.AccessToken, payload))
+
+	// Other sessions are kept unless asked otherwise
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, token.AccessToken))
+	assert.NotEmpty(t, loginTestUser(t, app, user.Email, strongTestPassword).AccessToken)
+
+	payload = fmt.Sprintf(`{"email":"%v", "password": "password"}`, user.Email)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/login", "", payload))
+}
+
+func TestChangePasswordLogsOtherSessionsOut(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	current := loginTestUser(t, app, user.Email, "password")
+	other := loginTestUser(t, app, user.Email, "password")
+
+	payload := fmt.Sprintf(`{"current_password":"password","password":"%v","logout_other_sessions":true}`, strongTestPassword)
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "POST", "/api/v1/auth/password", current.AccessToken, payload))
+
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, current.AccessToken))
+	assert.Equal(t, http.StatusUnauthorized, profileStatus(t, app, other.AccessToken))
+	status, _ := refreshTestToken(t, app, other.RefreshToken)
+	assert.Equal(t, http.StatusForbidden, status)
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// Handles the process of requesting a change of email address
+func (h *AuthHandler) handleRequestEmailChange(c *fiber.Ctx) error {
+	var body dto.ChangeEmailDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	if err := h.userService.RequestEmailChange(requestContext(c), userId, body); err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to change email address",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(failureStatus(c, err)).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"A confirmation code was sent to the new email address",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of confirming a change of email address with the code sent to it
+func (h *AuthHandler) handleConfirmEmailChange(c *fiber.Ctx) error {
+	var body dto.ConfirmEmailChangeDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	if err := h.userService.ConfirmEmailChange(requestContext(c), userId, body); err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to confirm email address",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(failureStatus(c, err)).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Email address was changed successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
@@ -0,0 +1,101 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/mocks"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeEmailAddress(t *testing.T) {
+	app, db, outbox, err := mocks.SetupTestServerWithOutbox()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	newEmail := gofakeit.Email()
+
+	payload := fmt.Sprintf(`{"email":"%v","password":"wrong"}`, newEmail)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/profile/email", token.AccessToken, payload))
+
+	payload = fmt.Sprintf(`{"email":"%v","password":"password"}`, newEmail)
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "POST", "/api/v1/auth/profile/email", token.AccessToken, payload))
+
+	message, ok := outbox.LastTo(newEmail)
+	assert.True(t, ok, "Expected a confirmation code to be sent to the new address")
+	assert.Equal(t, "Confirm your new email address", message.Subject)
+	code := emailCodePattern.FindString(message.Text)
+	assert.NotEmpty(t, code)
+
+	// The account keeps its address until the change is confirmed
+	loginTestUser(t, app, user.Email, "password")
+
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/profile/email/confirm", token.AccessToken, `{"code":"000000"}`))
+	payload = fmt.Sprintf(`{"code":"%v"}`, code)
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "POST", "/api/v1/auth/profile/email/confirm", token.AccessToken, payload))
+
+	notice, ok := outbox.LastTo(user.Email)
+	assert.True(t, ok, "Expected the previous address to be notified")
+	assert.Equal(t, "Your email address was changed", notice.Subject)
+	assert.Contains(t, notice.Text, newEmail)
+
+	assert.NotEmpty(t, loginTestUser(t, app, newEmail, "password").AccessToken)
+	payload = fmt.Sprintf(`{"email":"%v", "password": "password"}`, user.Email)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/login", "", payload))
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/profile/email/confirm", token.AccessToken, fmt.Sprintf(`{"code":"%v"}`, code)))
+}
+
+func TestChangeEmailAddressToOneInUse(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	other, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	payload := fmt.Sprintf(`{"email":"%v","password":"password"}`, other.Email)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/profile/email", token.AccessToken, payload))
+
+	payload = fmt.Sprintf(`{"email":"%v","password":"password"}`, user.Email)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/profile/email", token.AccessToken, payload))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, requestStatus(t, app, "POST", "/api/v1/auth/profile/email", token.AccessToken, `{"email":"invalid","password":"password"}`))
+}
+
+func TestEmailChangeCodeOnlyConfirmsItsAddress(t *testing.T) {
+	app, db, outbox, err := mocks.SetupTestServerWithOutbox()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	first, second := gofakeit.Email(), gofakeit.Email()
+
+	payload := fmt.Sprintf(`{"email":"%v","password":"password"}`, first)
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "POST", "/api/v1/auth/profile/email", token.AccessToken, payload))
+	message, ok := outbox.LastTo(first)
+	assert.True(t, ok)
+	code := emailCodePattern.FindString(message.Text)
+
+	// A second address asked for during the resend cooldown is not sent a code, nor stored
+	payload = fmt.Sprintf(`{"email":"%v","password":"password"}`, second)
+	assert.Equal(t, http.StatusTooManyRequests, requestStatus(t, app, "POST", "/api/v1/auth/profile/email", token.AccessToken, payload))
+	_, ok = outbox.LastTo(second)
+	assert.False(t, ok)
+
+	var stored domain.User
+	assert.Nil(t, db.Db.Where("id = ?", user.ID).First(&stored).Error)
+	assert.Equal(t, first, stored.PendingEmail)
+
+	// Even if the pending address changes, the code only confirms the address it was sent to
+	assert.Nil(t, db.Db.Model(&domain.User{}).Where("id = ?", user.ID).Update("pending_email", second).Error)
+	payload = fmt.Sprintf(`{"code":"%v"}`, code)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/profile/email/confirm", token.AccessToken, payload))
+	assert.Nil(t, db.Db.Where("id = ?", user.ID).First(&stored).Error)
+	assert.Equal(t, user.Email, stored.Email)
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+)
+
+// failureStatus picks the status code for a failed request. Errors asking the client to retry
+// later answer with 429 and a Retry-After header, passwords refused by the password policy
+// with 422, and every other failure with 403.
+func failureStatus(c *fiber.Ctx, err error) int {
+	var retryErr *domain.RetryError
+	if errors.As(err, &retryErr) {
+		seconds := int(math.Ceil(retryErr.RetryAfter.Seconds()))
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(seconds, 1)))
+		return fiber.StatusTooManyRequests
+	}
+
+	var policyErr *domain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return fiber.StatusUnprocessableEntity
+	}
+
+	return fiber.StatusForbidden
+}
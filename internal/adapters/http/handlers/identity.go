@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/adapters/middleware"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// externalLoginCookie holds the secret a login with an identity provider is bound to, so it can
+// only be completed in the browser that started it
+const externalLoginCookie = "external_login_binding"
+
+// Handles the process of sending a user to an identity provider to log in with it
+func (h *AuthHandler) handleExternalLogin(c *fiber.Ctx) error {
+	binding := utils.GenerateSecureToken(32)
+	authURL, err := h.userService.StartExternalLogin(requestContext(c), c.Params("provider"), binding)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Login failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	setExternalLoginCookie(c, binding, time.Time{})
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// Handles the process of logging a user in once the identity provider sends them back
+func (h *AuthHandler) handleExternalLoginCallback(c *fiber.Ctx) error {
+	var query dto.ExternalLoginCallbackDto
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// The provider sends an error instead of a code when the user did not sign in
+	if query.Error != "" {
+		message := query.ErrorDescription
+		if message == "" {
+			message = query.Error
+		}
+
+		data := utils.FormatApiResponse(
+			"Login failed",
+			fiber.Map{"error": message},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	if status, res := middleware.Validate(&query); res != nil {
+		return c.Status(status).JSON(res)
+	}
+
+	query.Binding = c.Cookies(externalLoginCookie)
+	res, err := h.userService.CompleteExternalLogin(requestContext(c), c.Params("provider"), query)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Login failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	setExternalLoginCookie(c, "", time.Unix(0, 0))
+	data := utils.FormatApiResponse(
+		"User was logged in successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// setExternalLoginCookie sets the binding of a login with an identity provider. Lax same-site
+// cookies still come along when the provider redirects the user back.
+func setExternalLoginCookie(c *fiber.Ctx, binding string, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     externalLoginCookie,
+		Value:    binding,
+		Path:     "/api/v1/auth/oauth",
+		Expires:  expires,
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
@@ -0,0 +1,245 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/mail"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/mocks"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupIdentityTestServer(t *testing.T) (*fiber.App, *database.DB, *mail.Outbox, *test.OIDCProvider) {
+	provider, err := test.NewOIDCProvider()
+	assert.Nil(t, err)
+	t.Cleanup(provider.Close)
+	provider.Account = test.OIDCAccount{
+		Subject:       gofakeit.UUID(),
+		Email:         gofakeit.Email(),
+		EmailVerified: true,
+		Name:          gofakeit.Name(),
+	}
+
+	t.Setenv("IDENTITY_PROVIDERS", "acme")
+	t.Setenv("IDENTITY_PROVIDER_ACME_ISSUER", provider.Issuer())
+	t.Setenv("IDENTITY_PROVIDER_ACME_CLIENT_ID", provider.ClientID)
+	t.Setenv("IDENTITY_PROVIDER_ACME_CLIENT_SECRET", provider.ClientSecret)
+	t.Setenv("IDENTITY_PROVIDER_ACME_REDIRECT_URL", "http://localhost/api/v1/auth/oauth/acme/callback")
+
+	app, db, outbox, err := mocks.SetupTestServerWithOutbox()
+	assert.Nil(t, err)
+
+	return app, db, outbox, provider
+}
+
+// externalLogin is where the provider sends the user back to, along with the cookie their browser
+// got when the login started
+type externalLogin struct {
+	callback string
+	cookie   *http.Cookie
+}
+
+// startExternalLogin follows a login through the provider and returns the callback it sends the user back to
+func startExternalLogin(t *testing.T, app *fiber.App, provider *test.OIDCProvider) externalLogin {
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/auth/oauth/acme", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+
+	var cookie *http.Cookie
+	for _, candidate := range resp.Cookies() {
+		if candidate.Name == "external_login_binding" {
+			cookie = candidate
+		}
+	}
+	if assert.NotNil(t, cookie, "Expected the login to be bound to the browser") {
+		assert.True(t, cookie.HttpOnly)
+		assert.Equal(t, "/api/v1/auth/oauth", cookie.Path)
+	}
+
+	callback, err := provider.Authorize(resp.Header.Get("Location"))
+	assert.Nil(t, err)
+
+	callbackURL, err := url.Parse(callback)
+	assert.Nil(t, err)
+	return externalLogin{callback: callbackURL.RequestURI(), cookie: cookie}
+}
+
+func completeExternalLogin(t *testing.T, app *fiber.App, login externalLogin) (int, dto.LoginResponse) {
+	req := httptest.NewRequest("GET", login.callback, nil)
+	if login.cookie != nil {
+		req.AddCookie(login.cookie)
+	}
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+
+	var response struct {
+		Data dto.LoginResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&response)
+	return resp.StatusCode, response.Data
+}
+
+func TestExternalLoginCreatesAUser(t *testing.T) {
+	app, db, _, provider := setupIdentityTestServer(t)
+
+	status, login := completeExternalLogin(t, app, startExternalLogin(t, app, provider))
+	assert.Equal(t, http.StatusOK, status)
+	assert.NotNil(t, login.Token)
+	assert.Equal(t, provider.Account.Email, login.User.Email)
+	assert.Equal(t, provider.Account.Name, login.User.FullName)
+	assert.False(t, login.User.EmailVerifiedAt.IsZero(), "Expected the email verified by the provider to be verified")
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, login.Token.AccessToken))
+
+	// Logging in again uses the linked identity
+	status, again := completeExternalLogin(t, app, startExternalLogin(t, app, provider))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, login.User.ID, again.User.ID)
+
+	var identities []domain.ExternalIdentity
+	db.Db.Where("user_id = ?", login.User.ID).Find(&identities)
+	assert.Len(t, identities, 1)
+	assert.Equal(t, "acme", identities[0].Provider)
+	assert.Equal(t, provider.Account.Subject, identities[0].Subject)
+}
+
+func TestExternalLoginLinksAccountWithVerifiedEmail(t *testing.T) {
+	app, db, _, provider := setupIdentityTestServer(t)
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	// An address the provider did not verify can not take the account over
+	provider.Account.Email = user.Email
+	provider.Account.EmailVerified = false
+	status, _ := completeExternalLogin(t, app, startExternalLogin(t, app, provider))
+	assert.Equal(t, http.StatusForbidden, status)
+
+	provider.Account.EmailVerified = true
+	status, login := completeExternalLogin(t, app, startExternalLogin(t, app, provider))
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, user.ID, login.User.ID)
+
+	// The password keeps working next to the provider
+	assert.NotEmpty(t, loginTestUser(t, app, user.Email, "password").AccessToken)
+}
+
+func TestExternalLoginDoesNotLinkUnverifiedAccounts(t *testing.T) {
+	app, db, _, provider := setupIdentityTestServer(t)
+
+	// Someone registered the address of the provider account first, with a password they know
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Db.Model(&domain.User{}).Where("id = ?", user.ID).Update("email_verified_at", nil).Error)
+
+	provider.Account.Email = user.Email
+	status, _ := completeExternalLogin(t, app, startExternalLogin(t, app, provider))
+	assert.Equal(t, http.StatusForbidden, status)
+
+	var count int64
+	db.Db.Model(&domain.ExternalIdentity{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Equal(t, int64(0), count, "Expected the identity not to be linked")
+
+	var stored domain.User
+	assert.Nil(t, db.Db.Where("id = ?", user.ID).First(&stored).Error)
+	assert.Nil(t, stored.EmailVerifiedAt, "Expected the account to stay unverified")
+	login := fmt.Sprintf(`{"email":"%v","password":"password"}`, user.Email)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/login", "", login))
+}
+
+func TestExternalLoginWithUnverifiedEmail(t *testing.T) {
+	app, db, outbox, provider := setupIdentityTestServer(t)
+	provider.Account.EmailVerified = false
+
+	status, _ := completeExternalLogin(t, app, startExternalLogin(t, app, provider))
+	assert.Equal(t, http.StatusForbidden, status)
+
+	var user domain.User
+	assert.Nil(t, db.Db.Where("email = ?", provider.Account.Email).First(&user).Error)
+	assert.Nil(t, user.EmailVerifiedAt)
+
+	message, ok := outbox.LastTo(provider.Account.Email)
+	assert.True(t, ok, "Expected a verification code to be sent")
+	assert.Equal(t, "Verify your email address", message.Subject)
+}
+
+func TestExternalLoginStateIsSingleUse(t *testing.T) {
+	app, _, _, provider := setupIdentityTestServer(t)
+
+	callback := startExternalLogin(t, app, provider)
+	status, _ := completeExternalLogin(t, app, callback)
+	assert.Equal(t, http.StatusOK, status)
+
+	status, _ = completeExternalLogin(t, app, callback)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, _ = completeExternalLogin(t, app, externalLogin{callback: "/api/v1/auth/oauth/acme/callback?code=abc&state=unknown", cookie: callback.cookie})
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, _ = completeExternalLogin(t, app, externalLogin{callback: "/api/v1/auth/oauth/acme/callback?error=access_denied"})
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, _ = completeExternalLogin(t, app, externalLogin{callback: "/api/v1/auth/oauth/acme/callback"})
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+}
+
+func TestExternalLoginIsBoundToTheBrowser(t *testing.T) {
+	app, db, _, provider := setupIdentityTestServer(t)
+
+	// A callback an attacker got for their own account is refused in another browser
+	attacker := startExternalLogin(t, app, provider)
+	victim := startExternalLogin(t, app, provider)
+	status, _ := completeExternalLogin(t, app, externalLogin{callback: attacker.callback})
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = completeExternalLogin(t, app, externalLogin{callback: attacker.callback, cookie: victim.cookie})
+	assert.Equal(t, http.StatusForbidden, status)
+
+	var count int64
+	db.Db.Model(&domain.User{}).Where("email = ?", provider.Account.Email).Count(&count)
+	assert.Equal(t, int64(0), count, "Expected no account to be logged into")
+
+	status, _ = completeExternalLogin(t, app, victim)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestExternalLoginRejectsInvalidIdTokens(t *testing.T) {
+	app, db, _, provider := setupIdentityTestServer(t)
+
+	tampered := map[string]func(claims jwt.MapClaims){
+		"wrong audience": func(claims jwt.MapClaims) { claims["aud"] = "another-client" },
+		"wrong issuer":   func(claims jwt.MapClaims) { claims["iss"] = "https://attacker.example.com" },
+		"wrong nonce":    func(claims jwt.MapClaims) { claims["nonce"] = "replayed" },
+		"expired":        func(claims jwt.MapClaims) { claims["exp"] = 1 },
+	}
+	for name, tamper := range tampered {
+		provider.TamperClaims = tamper
+		status, _ := completeExternalLogin(t, app, startExternalLogin(t, app, provider))
+		assert.Equal(t, http.StatusForbidden, status, "Expected an id_token with the %s to be refused", name)
+	}
+	provider.TamperClaims = nil
+
+	provider.ForgeSignature = true
+	status, _ := completeExternalLogin(t, app, startExternalLogin(t, app, provider))
+	assert.Equal(t, http.StatusForbidden, status, "Expected an id_token signed with an unknown key to be refused")
+
+	var count int64
+	db.Db.Model(&domain.User{}).Where("email = ?", provider.Account.Email).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func TestExternalLoginWithUnknownProvider(t *testing.T) {
+	app, _, _, _ := setupIdentityTestServer(t)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/auth/oauth/unknown", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/adapters/middleware"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// magicLinkCookie holds the secret a login link is bound to, so the link only works in the
+// browser that asked for it
+const magicLinkCookie = "magic_link_binding"
+
+// Handles the process of emailing a user a link to log in with
+func (h *AuthHandler) handleRequestMagicLink(c *fiber.Ctx) error {
+	var body dto.EmailDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	// A browser keeps its binding, so a link sent before still works during the resend cooldown
+	binding := c.Cookies(magicLinkCookie)
+	if binding == "" {
+		binding = utils.GenerateSecureToken(32)
+	}
+	if err := h.userService.RequestMagicLink(requestContext(c), body, binding); err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to send a login link",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(failureStatus(c, err)).JSON(data)
+	}
+
+	setMagicLinkCookie(c, binding, time.Time{})
+
+	data := utils.FormatApiResponse(
+		"If an account uses this email address, a login link was sent to it",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of logging a user in with the link they were emailed
+func (h *AuthHandler) handleMagicLinkCallback(c *fiber.Ctx) error {
+	var query dto.MagicLinkCallbackDto
+	if err := c.QueryParser(&query); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if status, res := middleware.Validate(&query); res != nil {
+		return c.Status(status).JSON(res)
+	}
+
+	query.Binding = c.Cookies(magicLinkCookie)
+	res, err := h.userService.CompleteMagicLink(requestContext(c), query)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Login failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	setMagicLinkCookie(c, "", time.Unix(0, 0))
+	data := utils.FormatApiResponse(
+		"User was logged in successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// setMagicLinkCookie sets the binding of a login link, which is only sent to the callback route.
+// Lax same-site cookies still come along when the link is opened from an email.
+func setMagicLinkCookie(c *fiber.Ctx, binding string, expires time.Time) {
+	c.Cookie(&fiber.Cookie{
+		Name:     magicLinkCookie,
+		Value:    binding,
+		Path:     "/api/v1/auth/magic-link",
+		Expires:  expires,
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+}
@@ -0,0 +1,163 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/mail"
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/mocks"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var magicLinkPattern = regexp.MustCompile(`https?://\S+`)
+
+func setupMagicLinkTestServer(t *testing.T) (*fiber.App, *database.DB, *mail.Outbox) {
+	t.Setenv("SERVER_URL", "http://localhost:8080")
+	app, db, outbox, err := mocks.SetupTestServerWithOutbox()
+	assert.Nil(t, err)
+
+	return app, db, outbox
+}
+
+// requestMagicLink asks for a login link and returns the cookie the link is bound to
+func requestMagicLink(t *testing.T, app *fiber.App, email string) *http.Cookie {
+	payload := fmt.Sprintf(`{"email":"%v"}`, email)
+	req := httptest.NewRequest("POST", "/api/v1/auth/magic-link", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "magic_link_binding" {
+			assert.True(t, cookie.HttpOnly)
+			return cookie
+		}
+	}
+
+	t.Fatal("Expected the login link to be bound to a cookie")
+	return nil
+}
+
+// lastMagicLink returns the path of the last login link emailed to an address
+func lastMagicLink(t *testing.T, outbox *mail.Outbox, email string) string {
+	message, ok := outbox.LastTo(email)
+	assert.True(t, ok, "Expected a login link to be sent")
+	assert.Equal(t, "Your login link", message.Subject)
+
+	link, err := url.Parse(magicLinkPattern.FindString(message.Text))
+	assert.Nil(t, err)
+	return link.RequestURI()
+}
+
+func openMagicLink(t *testing.T, app *fiber.App, link string, cookie *http.Cookie) int {
+	req := httptest.NewRequest("GET", link, nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	return resp.StatusCode
+}
+
+func TestMagicLinkLogin(t *testing.T) {
+	app, db, outbox := setupMagicLinkTestServer(t)
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	cookie := requestMagicLink(t, app, user.Email)
+	link := lastMagicLink(t, outbox, user.Email)
+	assert.True(t, strings.HasPrefix(link, "/api/v1/auth/magic-link/callback?token="))
+
+	// The link only works in the browser that asked for it
+	assert.Equal(t, http.StatusForbidden, openMagicLink(t, app, link, nil))
+	otherClient := &http.Cookie{Name: cookie.Name, Value: "another-browser"}
+	assert.Equal(t, http.StatusForbidden, openMagicLink(t, app, link, otherClient))
+
+	assert.Equal(t, http.StatusOK, openMagicLink(t, app, link, cookie))
+	assert.Equal(t, http.StatusForbidden, openMagicLink(t, app, link, cookie), "Expected the link to only work once")
+}
+
+func TestMagicLinkVerifiesEmail(t *testing.T) {
+	app, db, outbox := setupMagicLinkTestServer(t)
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	assert.Nil(t, db.Db.Model(&domain.User{}).Where("id = ?", user.ID).Update("email_verified_at", nil).Error)
+
+	cookie := requestMagicLink(t, app, user.Email)
+	assert.Equal(t, http.StatusOK, openMagicLink(t, app, lastMagicLink(t, outbox, user.Email), cookie))
+
+	var updated domain.User
+	assert.Nil(t, db.Db.Where("id = ?", user.ID).First(&updated).Error)
+	assert.NotNil(t, updated.EmailVerifiedAt)
+}
+
+func TestMagicLinkRequests(t *testing.T) {
+	app, db, outbox := setupMagicLinkTestServer(t)
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	// Unknown addresses are not revealed
+	requestMagicLink(t, app, "nobody@example.com")
+	_, ok := outbox.LastTo("nobody@example.com")
+	assert.False(t, ok)
+
+	// Requests during the resend cooldown are answered the same, and keep the link sent before working
+	cookie := requestMagicLink(t, app, user.Email)
+	link := lastMagicLink(t, outbox, user.Email)
+	req := httptest.NewRequest("POST", "/api/v1/auth/magic-link", strings.NewReader(fmt.Sprintf(`{"email":"%v"}`, user.Email)))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(cookie)
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, outbox.Messages(), 1, "Expected no new email during the cooldown")
+	assert.Equal(t, http.StatusOK, openMagicLink(t, app, link, cookie))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, requestStatus(t, app, "POST", "/api/v1/auth/magic-link", "", `{"email":"invalid"}`))
+
+	// Access tokens and forged tokens are not login links
+	cookie = &http.Cookie{Name: "magic_link_binding", Value: "binding"}
+	assert.Equal(t, http.StatusForbidden, openMagicLink(t, app, "/api/v1/auth/magic-link/callback?token="+token.AccessToken, cookie))
+	assert.Equal(t, http.StatusForbidden, openMagicLink(t, app, "/api/v1/auth/magic-link/callback?token=forged", cookie))
+	assert.Equal(t, http.StatusUnprocessableEntity, openMagicLink(t, app, "/api/v1/auth/magic-link/callback", cookie))
+}
+
+// failingMailer is a mailer that can not reach its mail server
+type failingMailer struct{}
+
+func (failingMailer) Send(ctx context.Context, mail dto.MailDto) error {
+	return errors.New("mail server unavailable")
+}
+
+func TestMagicLinkRequestHidesMailerFailures(t *testing.T) {
+	t.Setenv("TOKEN_SECRET_KEY", "mockValue")
+	db, err := database.SetupMockDB()
+	assert.Nil(t, err)
+	cfg, err := config.Load(config.Options{})
+	assert.Nil(t, err)
+	app, err := services.NewServer(cfg, db, failingMailer{}).PrepareServer()
+	assert.Nil(t, err)
+
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	// A known address answers like an unknown one even when its email can not be sent
+	requestMagicLink(t, app, "nobody@example.com")
+	requestMagicLink(t, app, user.Email)
+}
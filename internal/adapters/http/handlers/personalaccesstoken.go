@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// Handles the process of creating a personal access token
+func (h *AuthHandler) handleCreatePersonalAccessToken(c *fiber.Ctx) error {
+	var body dto.CreatePersonalAccessTokenDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	res, err := h.userService.CreatePersonalAccessToken(requestContext(c), userId, body)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to create personal access token",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Personal access token was created. Copy it now, it will not be shown again.",
+		res,
+	)
+	return c.Status(fiber.StatusCreated).JSON(data)
+}
+
+// Handles the process of listing a user's personal access tokens
+func (h *AuthHandler) handleListPersonalAccessTokens(c *fiber.Ctx) error {
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	tokens, err := h.userService.ListPersonalAccessTokens(requestContext(c), userId)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to list personal access tokens",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Personal access tokens",
+		tokens,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of revoking one of a user's personal access tokens
+func (h *AuthHandler) handleRevokePersonalAccessToken(c *fiber.Ctx) error {
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	err := h.userService.RevokePersonalAccessToken(requestContext(c), userId, c.Params("id"))
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to revoke personal access token",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Personal access token was revoked successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
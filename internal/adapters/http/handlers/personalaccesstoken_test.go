@@ -0,0 +1,115 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/mocks"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func createTestPersonalAccessToken(t *testing.T, app *fiber.App, accessToken string, scopes string) dto.PersonalAccessTokenDto {
+	payload := fmt.Sprintf(`{"name":"CI","scopes":%v,"expires_in_days":30}`, scopes)
+	req := httptest.NewRequest("POST", "/api/v1/auth/tokens", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var created struct {
+		Data dto.PersonalAccessTokenDto `json:"data"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&created))
+	return created.Data
+}
+
+func requestStatus(t *testing.T, app *fiber.App, method string, path string, accessToken string, payload string) int {
+	req := httptest.NewRequest(method, path, strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	return resp.StatusCode
+}
+
+func TestPersonalAccessTokenScopes(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	_, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	readToken := createTestPersonalAccessToken(t, app, token.AccessToken, `["resume:read"]`)
+	assert.True(t, strings.HasPrefix(readToken.Token, "vrp_"))
+	assert.Equal(t, []string{"resume:read"}, readToken.Scopes)
+
+	payload := `{"summary":"Test","skills":["Go"],"experience":[],"education":[]}`
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "GET", "/api/v1/resume/list", readToken.Token, ""))
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/resume/create", readToken.Token, payload))
+
+	writeToken := createTestPersonalAccessToken(t, app, token.AccessToken, `["resume:write"]`)
+	assert.Equal(t, http.StatusCreated, requestStatus(t, app, "POST", "/api/v1/resume/create", writeToken.Token, payload))
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "GET", "/api/v1/resume/list", writeToken.Token, ""))
+
+	// Routes without scopes, like the account routes, do not accept personal access tokens
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "GET", "/api/v1/auth/profile", writeToken.Token, ""))
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "POST", "/api/v1/auth/tokens", writeToken.Token, `{"name":"CI","scopes":["resume:write"],"expires_in_days":30}`))
+
+	assert.Equal(t, http.StatusUnauthorized, requestStatus(t, app, "GET", "/api/v1/resume/list", "vrp_unknown", ""))
+}
+
+func TestListAndRevokePersonalAccessTokens(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	_, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	created := createTestPersonalAccessToken(t, app, token.AccessToken, `["resume:read","resume:read"]`)
+	assert.Equal(t, []string{"resume:read"}, created.Scopes)
+
+	req := httptest.NewRequest("GET", "/api/v1/auth/tokens", nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var list struct {
+		Data []dto.PersonalAccessTokenDto `json:"data"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&list))
+	if assert.Len(t, list.Data, 1) {
+		assert.Equal(t, created.ID, list.Data[0].ID)
+		assert.Empty(t, list.Data[0].Token, "Expected the token value to only be shown once")
+	}
+
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "DELETE", "/api/v1/auth/tokens/"+created.ID, token.AccessToken, ""))
+	assert.Equal(t, http.StatusUnauthorized, requestStatus(t, app, "GET", "/api/v1/resume/list", created.Token, ""))
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "DELETE", "/api/v1/auth/tokens/"+created.ID, token.AccessToken, ""))
+}
+
+func TestCreatePersonalAccessTokenValidation(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	_, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	for _, payload := range []string{
+		`{"name":"CI","scopes":["admin"],"expires_in_days":30}`,
+		`{"name":"CI","scopes":[],"expires_in_days":30}`,
+		`{"name":"CI","scopes":["resume:read"],"expires_in_days":0}`,
+		`{"name":"","scopes":["resume:read"],"expires_in_days":30}`,
+	} {
+		status := requestStatus(t, app, "POST", "/api/v1/auth/tokens", token.AccessToken, payload)
+		assert.Equal(t, http.StatusUnprocessableEntity, status, payload)
+	}
+}
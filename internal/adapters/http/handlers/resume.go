@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/adapters/jsonresume"
 	"github.com/stivo-m/vise-resume/internal/adapters/middleware"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
 	"github.com/stivo-m/vise-resume/internal/core/dto"
 	"github.com/stivo-m/vise-resume/internal/core/ports"
 	"github.com/stivo-m/vise-resume/internal/core/utils"
@@ -14,35 +18,143 @@ type ResumeHandler struct {
 	resumeService ports.ResumeService
 	userPort      ports.UserPort
 	tokenPort     ports.TokenService
+	auditPort     ports.AuditPort
 }
 
 func NewResumeHandler(
 	resumeService ports.ResumeService,
 	userPort ports.UserPort,
 	tokenPort ports.TokenService,
-
+	auditPort ports.AuditPort,
 ) *ResumeHandler {
 	return &ResumeHandler{
 		resumeService: resumeService,
 		userPort:      userPort,
 		tokenPort:     tokenPort,
+		auditPort:     auditPort,
 	}
 }
 
 func (h ResumeHandler) RegisterResumeRoutes(router fiber.Router) {
+	// Personal access tokens need resume:write to change resumes, which also lets them read
+	readScopes := []string{domain.ScopeResumeRead, domain.ScopeResumeWrite}
+	writeScopes := []string{domain.ScopeResumeWrite}
+
 	authRouter := router.Group("/resume")
 	authRouter.Post(
 		"/create",
 		middleware.ValidationMiddleware(&dto.CreateResumeDto{}),
-		middleware.AuthMiddleware(h.tokenPort, h.userPort),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, writeScopes...),
 		h.HandleCreateResume,
 	)
 
+	authRouter.Post(
+		"/import/json-resume",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, writeScopes...),
+		h.HandleImportJsonResume,
+	)
+
 	authRouter.Get(
 		"/list",
-		middleware.AuthMiddleware(h.tokenPort, h.userPort),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, readScopes...),
 		h.HandleFindResumes,
 	)
+
+	authRouter.Get(
+		"/:id",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, readScopes...),
+		h.HandleFindResume,
+	)
+
+	authRouter.Get(
+		"/:id/score",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, readScopes...),
+		h.HandleScoreResume,
+	)
+
+	authRouter.Post(
+		"/:id/match",
+		middleware.ValidationMiddleware(&dto.MatchResumeDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, readScopes...),
+		h.HandleMatchResume,
+	)
+
+	authRouter.Get(
+		"/:id/export.pdf",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, readScopes...),
+		h.HandleExportResume("pdf"),
+	)
+
+	authRouter.Get(
+		"/:id/export.html",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, readScopes...),
+		h.HandleExportResume("html"),
+	)
+
+	authRouter.Get(
+		"/:id/export.md",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, readScopes...),
+		h.HandleExportResume("md"),
+	)
+
+	authRouter.Get(
+		"/:id/export.json",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, readScopes...),
+		h.HandleExportResume("json"),
+	)
+
+	authRouter.Patch(
+		"/:id",
+		middleware.ValidationMiddleware(&dto.UpdateResumeDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, writeScopes...),
+		h.HandleUpdateResume,
+	)
+
+	authRouter.Delete(
+		"/:id",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, writeScopes...),
+		h.HandleDeleteResume,
+	)
+
+	authRouter.Post(
+		"/:id/experiences",
+		middleware.ValidationMiddleware(&dto.WorkExperienceDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, writeScopes...),
+		h.HandleAddWorkExperience,
+	)
+
+	authRouter.Patch(
+		"/:id/experiences/:experienceId",
+		middleware.ValidationMiddleware(&dto.UpdateWorkExperienceDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, writeScopes...),
+		h.HandleUpdateWorkExperience,
+	)
+
+	authRouter.Delete(
+		"/:id/experiences/:experienceId",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, writeScopes...),
+		h.HandleDeleteWorkExperience,
+	)
+
+	authRouter.Post(
+		"/:id/education",
+		middleware.ValidationMiddleware(&dto.EducationDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, writeScopes...),
+		h.HandleAddEducation,
+	)
+
+	authRouter.Patch(
+		"/:id/education/:educationId",
+		middleware.ValidationMiddleware(&dto.UpdateEducationDto{}),
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, writeScopes...),
+		h.HandleUpdateEducation,
+	)
+
+	authRouter.Delete(
+		"/:id/education/:educationId",
+		middleware.AuthMiddleware(h.tokenPort, h.userPort, h.auditPort, writeScopes...),
+		h.HandleDeleteEducation,
+	)
 }
 
 // Handles the process of creating a new resume
@@ -73,6 +185,50 @@ func (h *ResumeHandler) HandleCreateResume(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(data)
 }
 
+// Handles the process of creating a resume from a JSON Resume document
+func (h *ResumeHandler) HandleImportJsonResume(c *fiber.Ctx) error {
+	var document jsonresume.Resume
+	if err := c.BodyParser(&document); err != nil {
+		res := utils.FormatApiResponse(
+			"The request body is invalid",
+			err.Error(),
+		)
+		return c.Status(fiber.StatusBadRequest).JSON(res)
+	}
+
+	body, err := jsonresume.ToCreateResumeDto(document)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"The JSON Resume document is invalid",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(data)
+	}
+
+	if status, res := middleware.Validate(&body); res != nil {
+		return c.Status(status).JSON(res)
+	}
+
+	userId := c.Locals("user_id")
+	res, err := h.resumeService.CreateResume(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		body,
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Resume import failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Resume was imported successfully",
+		res,
+	)
+	return c.Status(fiber.StatusCreated).JSON(data)
+}
+
 // Handles the process of listing resumes
 func (h *ResumeHandler) HandleFindResumes(c *fiber.Ctx) error {
 	userId := c.Locals("user_id")
@@ -96,3 +252,308 @@ func (h *ResumeHandler) HandleFindResumes(c *fiber.Ctx) error {
 	)
 	return c.Status(fiber.StatusOK).JSON(data)
 }
+
+// Handles the process of showing a single resume
+func (h *ResumeHandler) HandleFindResume(c *fiber.Ctx) error {
+	userId := c.Locals("user_id")
+	res, err := h.resumeService.FindResume(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		c.Params("id"),
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to find resume",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Resume obtained successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of explaining the score of a resume
+func (h *ResumeHandler) HandleScoreResume(c *fiber.Ctx) error {
+	userId := c.Locals("user_id")
+	res, err := h.resumeService.ScoreResume(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		c.Params("id"),
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to score resume",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Resume score obtained successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of matching a resume against a job description
+func (h *ResumeHandler) HandleMatchResume(c *fiber.Ctx) error {
+	var body dto.MatchResumeDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals("user_id")
+	res, err := h.resumeService.MatchResume(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		c.Params("id"),
+		body,
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to match resume",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Resume matched successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of updating a resume
+func (h *ResumeHandler) HandleUpdateResume(c *fiber.Ctx) error {
+	var body dto.UpdateResumeDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals("user_id")
+	err := h.resumeService.UpdateResume(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		c.Params("id"),
+		body,
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Resume update failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Resume was updated successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of deleting a resume
+func (h *ResumeHandler) HandleDeleteResume(c *fiber.Ctx) error {
+	userId := c.Locals("user_id")
+	err := h.resumeService.DeleteResume(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		c.Params("id"),
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Resume deletion failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Resume was deleted successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of adding a work experience to a resume
+func (h *ResumeHandler) HandleAddWorkExperience(c *fiber.Ctx) error {
+	var body dto.WorkExperienceDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals("user_id")
+	err := h.resumeService.AddWorkExperience(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		c.Params("id"),
+		body,
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to add work experience",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Work experience was added successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusCreated).JSON(data)
+}
+
+// Handles the process of updating a work experience on a resume
+func (h *ResumeHandler) HandleUpdateWorkExperience(c *fiber.Ctx) error {
+	var body dto.UpdateWorkExperienceDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals("user_id")
+	err := h.resumeService.UpdateWorkExperience(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		c.Params("id"),
+		c.Params("experienceId"),
+		body,
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Work experience update failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Work experience was updated successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of removing a work experience from a resume
+func (h *ResumeHandler) HandleDeleteWorkExperience(c *fiber.Ctx) error {
+	userId := c.Locals("user_id")
+	err := h.resumeService.DeleteWorkExperience(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		c.Params("id"),
+		c.Params("experienceId"),
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Work experience deletion failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Work experience was deleted successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of adding an education record to a resume
+func (h *ResumeHandler) HandleAddEducation(c *fiber.Ctx) error {
+	var body dto.EducationDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals("user_id")
+	err := h.resumeService.AddEducation(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		c.Params("id"),
+		body,
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to add education",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Education was added successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusCreated).JSON(data)
+}
+
+// Handles the process of updating an education record on a resume
+func (h *ResumeHandler) HandleUpdateEducation(c *fiber.Ctx) error {
+	var body dto.UpdateEducationDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals("user_id")
+	err := h.resumeService.UpdateEducation(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		c.Params("id"),
+		c.Params("educationId"),
+		body,
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Education update failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Education was updated successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of removing an education record from a resume
+func (h *ResumeHandler) HandleDeleteEducation(c *fiber.Ctx) error {
+	userId := c.Locals("user_id")
+	err := h.resumeService.DeleteEducation(
+		context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+		c.Params("id"),
+		c.Params("educationId"),
+	)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Education deletion failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Education was deleted successfully",
+		nil,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of downloading a resume in the given format
+func (h *ResumeHandler) HandleExportResume(format string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userId := c.Locals("user_id")
+		res, err := h.resumeService.ExportResume(
+			context.WithValue(context.Background(), utils.USER_ID_KEY, userId),
+			c.Params("id"),
+			format,
+		)
+		if err != nil {
+			data := utils.FormatApiResponse(
+				"Resume export failed",
+				fiber.Map{"error": err.Error()},
+			)
+			return c.Status(fiber.StatusForbidden).JSON(data)
+		}
+
+		c.Set(fiber.HeaderContentType, res.ContentType)
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, res.FileName))
+		return c.Status(fiber.StatusOK).SendStream(bytes.NewReader(res.Content), len(res.Content))
+	}
+}
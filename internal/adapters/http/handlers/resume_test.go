@@ -1,12 +1,18 @@
 package handlers_test
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stivo-m/vise-resume/internal/adapters/jsonresume"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
 	"github.com/stivo-m/vise-resume/internal/core/mocks"
 	"github.com/stivo-m/vise-resume/internal/core/test"
 	"github.com/stretchr/testify/assert"
@@ -118,3 +124,550 @@ func TestListResumeSuccess(t *testing.T) {
 	assert.Contains(t, string(body), `"Resumes obtained successfully"`)
 	assert.NotContains(t, string(body), `"Unable to find resumes"`)
 }
+
+func TestFindResumeSuccess(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s", resume.ID), nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+	assert.NotNil(t, resp)
+
+	body := make([]byte, resp.ContentLength)
+	resp.Body.Read(body)
+	assert.Contains(t, string(body), `"Resume obtained successfully"`)
+	assert.Contains(t, string(body), resume.Experiences[0].ID)
+	assert.Contains(t, string(body), resume.Education[0].ID)
+}
+
+func TestFindResumeOwnedByAnotherUser(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	owner, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	_, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, owner.ID)
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s", resume.ID), nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Nil(t, err)
+	assert.NotNil(t, resp)
+
+	body := make([]byte, resp.ContentLength)
+	resp.Body.Read(body)
+	assert.Contains(t, string(body), `"Unable to find resume"`)
+	assert.NotContains(t, string(body), resume.Summary)
+}
+
+func TestUpdateResumeSuccess(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	payload := `{"summary":"Updated summary","skills":["Rust"]}`
+	req := httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/resume/%s", resume.ID), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s", resume.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	body := make([]byte, resp.ContentLength)
+	resp.Body.Read(body)
+	assert.Contains(t, string(body), `"Updated summary"`)
+	assert.Contains(t, string(body), `"Rust"`)
+}
+
+func TestUpdateResumeValidationErrors(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	payload := `{"skills":[]}`
+	req := httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/resume/%s", resume.ID), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Nil(t, err)
+
+	// The summary column holds 200 characters
+	payload = fmt.Sprintf(`{"summary":"%s"}`, strings.Repeat("a", 201))
+	req = httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/resume/%s", resume.ID), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Nil(t, err)
+}
+
+func TestDeleteResumeSuccess(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/resume/%s", resume.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s", resume.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Nil(t, err)
+}
+
+func TestManageWorkExperiences(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	payload := `{"company_name":"Another Company","role":"Staff Engineer","start_date":"2021-01-02T15:04:05Z"}`
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/resume/%s/experiences", resume.ID), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Nil(t, err)
+
+	experienceId := resume.Experiences[0].ID
+	payload = `{"role":"Principal Engineer","end_date":"2020-12-31T00:00:00Z"}`
+	req = httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/resume/%s/experiences/%s", resume.ID, experienceId), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	req = httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/resume/%s/experiences/%s", resume.ID, experienceId), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s", resume.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	body := make([]byte, resp.ContentLength)
+	resp.Body.Read(body)
+	assert.Contains(t, string(body), `"Staff Engineer"`)
+	assert.NotContains(t, string(body), experienceId)
+}
+
+func TestManageEducation(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	payload := `{"school_name":"Another School","course":"Mathematics","start_date":"2010-01-02T15:04:05Z"}`
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/resume/%s/education", resume.ID), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Nil(t, err)
+
+	educationId := resume.Education[0].ID
+	payload = `{"course":"Software Engineering"}`
+	req = httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/resume/%s/education/%s", resume.ID, educationId), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s", resume.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	body := make([]byte, resp.ContentLength)
+	resp.Body.Read(body)
+	assert.Contains(t, string(body), `"Software Engineering"`)
+	assert.Contains(t, string(body), `"Another School"`)
+
+	req = httptest.NewRequest("DELETE", fmt.Sprintf("/api/v1/resume/%s/education/%s", resume.ID, gofakeit.UUID()), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Nil(t, err)
+}
+
+func TestResumeEndDatesCanBeClearedButNotPrecedeStartDates(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	payload := `{"summary":"Test","skills":["Go"],"experience":[{"company_name":"Test Company","role":"Engineer","start_date":"2019-01-02T00:00:00Z","end_date":"2018-01-02T00:00:00Z"}],"education":[]}`
+	assert.Equal(t, http.StatusUnprocessableEntity, requestStatus(t, app, "POST", "/api/v1/resume/create", token.AccessToken, payload))
+
+	payload = `{"company_name":"Another Company","role":"Engineer","start_date":"2021-01-02T00:00:00Z","end_date":"2020-01-02T00:00:00Z"}`
+	assert.Equal(t, http.StatusUnprocessableEntity, requestStatus(t, app, "POST", fmt.Sprintf("/api/v1/resume/%s/experiences", resume.ID), token.AccessToken, payload))
+
+	payload = `{"school_name":"Another School","course":"Mathematics","start_date":"2010-01-02T00:00:00Z","end_date":"2009-01-02T00:00:00Z"}`
+	assert.Equal(t, http.StatusUnprocessableEntity, requestStatus(t, app, "POST", fmt.Sprintf("/api/v1/resume/%s/education", resume.ID), token.AccessToken, payload))
+
+	experiencePath := fmt.Sprintf("/api/v1/resume/%s/experiences/%s", resume.ID, resume.Experiences[0].ID)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "PATCH", experiencePath, token.AccessToken, `{"end_date":"2018-12-31T00:00:00Z"}`))
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "PATCH", experiencePath, token.AccessToken, `{"end_date":"2020-12-31T00:00:00Z"}`))
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "PATCH", experiencePath, token.AccessToken, `{"start_date":"2021-01-02T00:00:00Z"}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, requestStatus(t, app, "PATCH", experiencePath, token.AccessToken, `{"end_date":"2020-12-31T00:00:00Z","clear_end_date":true}`))
+
+	var experience domain.WorkExperience
+	db.Db.First(&experience, "id = ?", resume.Experiences[0].ID)
+	assert.NotNil(t, experience.EndDate)
+	assert.Equal(t, 2019, experience.StartDate.Year())
+
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "PATCH", experiencePath, token.AccessToken, `{"start_date":"2021-01-02T00:00:00Z","clear_end_date":true}`))
+	experience = domain.WorkExperience{}
+	db.Db.First(&experience, "id = ?", resume.Experiences[0].ID)
+	assert.Nil(t, experience.EndDate, "Expected the role to be the current one")
+	assert.Equal(t, 2021, experience.StartDate.Year())
+
+	educationPath := fmt.Sprintf("/api/v1/resume/%s/education/%s", resume.ID, resume.Education[0].ID)
+	assert.Equal(t, http.StatusForbidden, requestStatus(t, app, "PATCH", educationPath, token.AccessToken, `{"end_date":"2014-06-30T00:00:00Z"}`))
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "PATCH", educationPath, token.AccessToken, `{"end_date":"2018-06-30T00:00:00Z"}`))
+	assert.Equal(t, http.StatusOK, requestStatus(t, app, "PATCH", educationPath, token.AccessToken, `{"clear_end_date":true}`))
+
+	var education domain.Education
+	db.Db.First(&education, "id = ?", resume.Education[0].ID)
+	assert.Nil(t, education.EndDate)
+}
+
+func TestExportResumeAsPdf(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s/export.pdf", resume.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+	assert.Equal(t, "application/pdf", resp.Header.Get("Content-Type"))
+	assert.Contains(t, resp.Header.Get("Content-Disposition"), fmt.Sprintf("resume-%s.pdf", resume.ID))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(body), "%PDF-1.4"))
+	assert.True(t, strings.HasSuffix(string(body), "%%EOF\n"))
+}
+
+func TestExportResumeOwnedByAnotherUser(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	owner, _, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	_, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, owner.ID)
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s/export.pdf", resume.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Nil(t, err)
+}
+
+func TestCreateResumeWithUnknownTemplate(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	_, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	payload := `{"summary":"Test","skills":["Go"],"template_id":"does-not-exist","experience":[],"education":[]}`
+
+	req := httptest.NewRequest("POST", "/api/v1/resume/create", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Nil(t, err)
+
+	body := make([]byte, resp.ContentLength)
+	resp.Body.Read(body)
+	assert.Contains(t, string(body), `"template does-not-exist does not exist"`)
+}
+
+func TestExportResumeWithChosenTemplate(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	payload := `{"template_id":"modern"}`
+	req := httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/resume/%s", resume.ID), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s/export.html", resume.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header.Get("Content-Type"))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	assert.Contains(t, string(body), `class="theme-modern"`)
+	assert.Contains(t, string(body), user.Email)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s/export.md", resume.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	body, err = io.ReadAll(resp.Body)
+	assert.Nil(t, err)
+	assert.Contains(t, string(body), fmt.Sprintf("# %s", user.FullName))
+	assert.Contains(t, string(body), "## Experience")
+}
+
+func TestImportAndExportJsonResume(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	payload := `{"basics":{"summary":"Imported summary"},"work":[{"name":"Acme","position":"Engineer","startDate":"2019-03-01"}],"education":[{"institution":"State University","area":"Physics","startDate":"2012-09-01","endDate":"2016-06-30"}],"skills":[{"name":"Go"}]}`
+
+	req := httptest.NewRequest("POST", "/api/v1/resume/import/json-resume", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Nil(t, err)
+
+	var created struct {
+		Data dto.ResumeDto `json:"data"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&created))
+	assert.NotEmpty(t, created.Data.ID)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s/export.json", created.Data.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+
+	var exported jsonresume.Resume
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&exported))
+	assert.Equal(t, user.Email, exported.Basics.Email)
+	assert.Equal(t, "Imported summary", exported.Basics.Summary)
+	assert.Equal(t, []jsonresume.Work{{Name: "Acme", Position: "Engineer", StartDate: "2019-03-01"}}, exported.Work)
+	assert.Equal(t, []jsonresume.Education{{Institution: "State University", Area: "Physics", StartDate: "2012-09-01", EndDate: "2016-06-30"}}, exported.Education)
+	assert.Equal(t, []jsonresume.Skill{{Name: "Go"}}, exported.Skills)
+}
+
+func TestImportJsonResumeValidationErrors(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	_, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	payload := `{"basics":{"summary":"Imported summary"},"work":[{"name":"Acme","startDate":"2019-03-01"}],"skills":[{"name":"Go"}]}`
+
+	req := httptest.NewRequest("POST", "/api/v1/resume/import/json-resume", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Nil(t, err)
+
+	body := make([]byte, resp.ContentLength)
+	resp.Body.Read(body)
+	assert.Contains(t, string(body), `"one or more of the required fields are invalid or missing"`)
+}
+
+func TestResumeScoreIsStoredAndExplained(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	_, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+
+	payload := `{"summary":"Test","skills":["Javascript","Go"],"experience":[{"company_name":"Test Company","role":"Software Engineer","start_date":"2019-01-02T15:04:05Z"}],"education":[{"school_name":"Test School","course":"Test Course","start_date":"2006-01-02T15:04:05Z"}]}`
+
+	req := httptest.NewRequest("POST", "/api/v1/resume/create", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Nil(t, err)
+
+	var created struct {
+		Data dto.ResumeDto `json:"data"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&created))
+	assert.Greater(t, created.Data.Score, 0)
+
+	payload = `{"summary":"Engineer who cut infrastructure costs by 30% while growing the platform to 2 million users across 12 markets."}`
+	req = httptest.NewRequest("PATCH", fmt.Sprintf("/api/v1/resume/%s", created.Data.ID), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	req = httptest.NewRequest("GET", fmt.Sprintf("/api/v1/resume/%s/score", created.Data.ID), nil)
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err = app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	var score struct {
+		Data dto.ResumeScoreDto `json:"data"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&score))
+	assert.Equal(t, 100, score.Data.MaxScore)
+	assert.Greater(t, score.Data.Score, created.Data.Score)
+	assert.Len(t, score.Data.Criteria, 6)
+
+	var stored domain.Resume
+	assert.Nil(t, db.Db.Where("id = ?", created.Data.ID).First(&stored).Error)
+	assert.Equal(t, score.Data.Score, stored.Score)
+}
+
+func TestMatchResumeAgainstJobDescription(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	payload := `{"job_description":"We need an engineer fluent in Go and SQL. Experience with Kubernetes and Terraform is required."}`
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/resume/%s/match", resume.ID), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+
+	var match struct {
+		Data dto.ResumeMatchDto `json:"data"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&match))
+	assert.Equal(t, resume.ID, match.Data.ResumeId)
+	assert.Contains(t, match.Data.MatchedKeywords, "go")
+	assert.Contains(t, match.Data.MatchedKeywords, "sql")
+	assert.Contains(t, match.Data.MissingKeywords, "kubernetes")
+	assert.Contains(t, match.Data.MissingKeywords, "terraform")
+	assert.Greater(t, match.Data.MatchPercentage, 0)
+}
+
+func TestMatchResumeValidationErrors(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest("POST", fmt.Sprintf("/api/v1/resume/%s/match", resume.ID), strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token.AccessToken))
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Nil(t, err)
+}
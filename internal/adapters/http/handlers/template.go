@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+type TemplateHandler struct {
+	resumeService ports.ResumeService
+}
+
+func NewTemplateHandler(resumeService ports.ResumeService) *TemplateHandler {
+	return &TemplateHandler{
+		resumeService: resumeService,
+	}
+}
+
+func (h TemplateHandler) RegisterTemplateRoutes(router fiber.Router) {
+	templateRouter := router.Group("/templates")
+	templateRouter.Get(
+		"/",
+		h.HandleListTemplates,
+	)
+}
+
+// Handles the process of listing the templates a resume can be rendered with
+func (h *TemplateHandler) HandleListTemplates(c *fiber.Ctx) error {
+	templates := h.resumeService.ListTemplates(context.Background())
+
+	data := utils.FormatApiResponse(
+		"Templates obtained successfully",
+		templates,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
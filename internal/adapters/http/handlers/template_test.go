@@ -0,0 +1,29 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/core/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListTemplates(t *testing.T) {
+	app, _, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest("GET", "/api/v1/templates", nil)
+	resp, err := app.Test(req)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Nil(t, err)
+	assert.NotNil(t, resp)
+
+	body := make([]byte, resp.ContentLength)
+	resp.Body.Read(body)
+	assert.Contains(t, string(body), `"Templates obtained successfully"`)
+	assert.Contains(t, string(body), `"id":"classic"`)
+	assert.Contains(t, string(body), `"id":"modern"`)
+	assert.Contains(t, string(body), `"id":"minimal"`)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// Handles the process of generating a new two-factor secret for a user
+func (h *AuthHandler) handleTwoFactorSetup(c *fiber.Ctx) error {
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	res, err := h.userService.SetupTwoFactor(requestContext(c), userId)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Two-factor setup failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Add the secret to your authenticator app and confirm it with a code to enable two-factor authentication",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of enabling two-factor authentication with a first code
+func (h *AuthHandler) handleTwoFactorEnable(c *fiber.Ctx) error {
+	var body dto.TwoFactorCodeDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	userId := c.Locals(utils.USER_ID_KEY).(string)
+	res, err := h.userService.EnableTwoFactor(requestContext(c), userId, body)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Unable to enable two-factor authentication",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"Two-factor authentication was enabled. Keep the recovery codes somewhere safe, they will not be shown again.",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
+
+// Handles the process of completing a login with a second factor
+func (h *AuthHandler) handleTwoFactorVerify(c *fiber.Ctx) error {
+	var body dto.TwoFactorVerifyDto
+	if err := c.BodyParser(&body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	res, err := h.userService.VerifyTwoFactor(requestContext(c), body)
+	if err != nil {
+		data := utils.FormatApiResponse(
+			"Login failed",
+			fiber.Map{"error": err.Error()},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(data)
+	}
+
+	data := utils.FormatApiResponse(
+		"User was logged in successfully",
+		res,
+	)
+	return c.Status(fiber.StatusOK).JSON(data)
+}
@@ -0,0 +1,159 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/mocks"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func postTwoFactor(t *testing.T, app *fiber.App, path string, accessToken string, payload string, data interface{}) int {
+	req := httptest.NewRequest("POST", "/api/v1/auth/2fa/"+path, strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	if accessToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", accessToken))
+	}
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+
+	if data != nil {
+		body := struct {
+			Data interface{} `json:"data"`
+		}{Data: data}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	}
+	return resp.StatusCode
+}
+
+// loginWithChallenge logs a user with two-factor authentication in and returns their challenge token
+func loginWithChallenge(t *testing.T, app *fiber.App, email string) string {
+	payload := fmt.Sprintf(`{"email":"%v", "password": "password"}`, email)
+	req := httptest.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var login struct {
+		Data dto.LoginResponse `json:"data"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&login))
+	assert.Nil(t, login.Data.Token, "Expected no tokens before the second factor")
+	if !assert.NotNil(t, login.Data.TwoFactor) {
+		return ""
+	}
+	return login.Data.TwoFactor.ChallengeToken
+}
+
+// enableTestTwoFactor turns two-factor authentication on for a user and returns their secret and recovery codes
+func enableTestTwoFactor(t *testing.T, app *fiber.App, accessToken string) (string, []string) {
+	var setup dto.TwoFactorSetupResponse
+	assert.Equal(t, http.StatusOK, postTwoFactor(t, app, "setup", accessToken, "", &setup))
+	assert.NotEmpty(t, setup.Secret)
+	assert.Contains(t, setup.URI, "otpauth://totp/")
+
+	code, err := services.NewTOTPService("").GenerateCode(setup.Secret, time.Now())
+	assert.Nil(t, err)
+
+	var recovery dto.RecoveryCodesResponse
+	status := postTwoFactor(t, app, "enable", accessToken, fmt.Sprintf(`{"code":"%v"}`, code), &recovery)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Len(t, recovery.RecoveryCodes, 10)
+
+	return setup.Secret, recovery.RecoveryCodes
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, _ := test.GetAuthenticatedTestUser(db)
+	secret, _ := enableTestTwoFactor(t, app, token.AccessToken)
+
+	challenge := loginWithChallenge(t, app, user.Email)
+
+	status := postTwoFactor(t, app, "verify", "", fmt.Sprintf(`{"challenge_token":"%v","code":"000000"}`, challenge), nil)
+	assert.Equal(t, http.StatusForbidden, status)
+
+	// The code used to enable two-factor authentication can not be replayed, so use the next one
+	code, err := services.NewTOTPService("").GenerateCode(secret, time.Now().Add(30*time.Second))
+	assert.Nil(t, err)
+
+	var login dto.LoginResponse
+	status = postTwoFactor(t, app, "verify", "", fmt.Sprintf(`{"challenge_token":"%v","code":"%v"}`, challenge, code), &login)
+	assert.Equal(t, http.StatusOK, status)
+	if assert.NotNil(t, login.Token) {
+		assert.Equal(t, http.StatusOK, profileStatus(t, app, login.Token.AccessToken))
+	}
+
+	status = postTwoFactor(t, app, "verify", "", fmt.Sprintf(`{"challenge_token":"%v","code":"%v"}`, challenge, code), nil)
+	assert.Equal(t, http.StatusForbidden, status, "Expected a challenge to only be answered once")
+}
+
+func TestTwoFactorRecoveryCodesWorkOnce(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, _ := test.GetAuthenticatedTestUser(db)
+	_, recoveryCodes := enableTestTwoFactor(t, app, token.AccessToken)
+
+	challenge := loginWithChallenge(t, app, user.Email)
+	status := postTwoFactor(t, app, "verify", "", fmt.Sprintf(`{"challenge_token":"%v","code":"%v"}`, challenge, strings.ToUpper(recoveryCodes[0])), nil)
+	assert.Equal(t, http.StatusOK, status)
+
+	challenge = loginWithChallenge(t, app, user.Email)
+	status = postTwoFactor(t, app, "verify", "", fmt.Sprintf(`{"challenge_token":"%v","code":"%v"}`, challenge, recoveryCodes[0]), nil)
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+func TestTwoFactorChallengeAllowsFiveCodes(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, _ := test.GetAuthenticatedTestUser(db)
+	enableTestTwoFactor(t, app, token.AccessToken)
+
+	challenge := loginWithChallenge(t, app, user.Email)
+	for i := 0; i < 5; i++ {
+		status := postTwoFactor(t, app, "verify", "", fmt.Sprintf(`{"challenge_token":"%v","code":"abcde-fghij"}`, challenge), nil)
+		assert.Equal(t, http.StatusForbidden, status)
+	}
+
+	var count int64
+	db.Db.Model(&domain.Token{}).Where("user_id = ? AND type = ?", user.ID, "2fa-challenge").Count(&count)
+	assert.Equal(t, int64(0), count, "Expected the challenge to be discarded after five wrong codes")
+
+	// The next login gets a fresh set of attempts
+	var twoFactor domain.TwoFactor
+	assert.Nil(t, db.Db.Where("user_id = ?", user.ID).First(&twoFactor).Error)
+	assert.Equal(t, 0, twoFactor.FailedAttempts)
+}
+
+func TestTwoFactorEnableRequiresAValidCode(t *testing.T) {
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	user, token, _ := test.GetAuthenticatedTestUser(db)
+
+	status := postTwoFactor(t, app, "enable", token.AccessToken, `{"code":"123456"}`, nil)
+	assert.Equal(t, http.StatusForbidden, status, "Expected enabling to require a setup first")
+
+	assert.Equal(t, http.StatusOK, postTwoFactor(t, app, "setup", token.AccessToken, "", nil))
+	status = postTwoFactor(t, app, "enable", token.AccessToken, `{"code":"abcdef"}`, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+	// Without a confirmed code the login is not changed
+	tokens := loginTestUser(t, app, user.Email, "password")
+	assert.NotEmpty(t, tokens.AccessToken)
+}
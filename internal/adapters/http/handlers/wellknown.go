@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+)
+
+type WellKnownHandler struct {
+	tokenPort ports.TokenService
+}
+
+func NewWellKnownHandler(tokenPort ports.TokenService) *WellKnownHandler {
+	return &WellKnownHandler{
+		tokenPort: tokenPort,
+	}
+}
+
+func (h WellKnownHandler) RegisterWellKnownRoutes(router fiber.Router) {
+	wellKnownRouter := router.Group("/.well-known")
+	wellKnownRouter.Get(
+		"/jwks.json",
+		h.HandleJWKS,
+	)
+}
+
+// Handles the process of publishing the public keys other services verify our tokens with.
+// The set is returned as is, without the usual response envelope, as JWKS clients expect.
+func (h *WellKnownHandler) HandleJWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.Status(fiber.StatusOK).JSON(h.tokenPort.JWKS())
+}
@@ -0,0 +1,60 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/mocks"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWKSPublishesSigningKeys(t *testing.T) {
+	dir := t.TempDir()
+	key, err := services.GenerateSigningKey("eddsa")
+	assert.Nil(t, err)
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "test-key.pem"), key, 0o600))
+	t.Setenv("TOKEN_SIGNING_KEYS_DIR", dir)
+
+	app, db, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var jwks dto.JWKSet
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&jwks))
+	if assert.Len(t, jwks.Keys, 1) {
+		assert.Equal(t, "test-key", jwks.Keys[0].KeyID)
+		assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+		assert.Equal(t, "EdDSA", jwks.Keys[0].Algorithm)
+		assert.NotEmpty(t, jwks.Keys[0].X)
+	}
+
+	// Tokens signed with the key authenticate requests
+	_, token, err := test.GetAuthenticatedTestUser(db)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, profileStatus(t, app, token.AccessToken))
+}
+
+func TestJWKSWithSharedSecretIsEmpty(t *testing.T) {
+	app, _, err := mocks.SetupTestServer()
+	assert.Nil(t, err)
+
+	req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+	resp, err := app.Test(req)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var jwks dto.JWKSet
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&jwks))
+	assert.Empty(t, jwks.Keys, "Expected the HS256 secret not to be published")
+}
@@ -0,0 +1,84 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Responses larger than this are refused rather than read into memory
+const maxResponseSize = 1 << 20
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func defaultClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+// newTokenRequest builds the request exchanging an authorization code, authenticating the client
+// with HTTP basic authentication or with the client_id and client_secret form fields
+func newTokenRequest(ctx context.Context, endpoint string, form url.Values, clientID string, clientSecret string, basicAuth bool) (*http.Request, error) {
+	if !basicAuth {
+		form.Set("client_id", clientID)
+		form.Set("client_secret", clientSecret)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	if basicAuth {
+		// RFC 6749 has the credentials form encoded before they are put in the header
+		request.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+	}
+
+	return request, nil
+}
+
+// doJSON sends a request and decodes its JSON response into target, turning OAuth2 error
+// responses and unexpected statuses into errors
+func doJSON(client *http.Client, request *http.Request, target interface{}) error {
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		var failure tokenResponse
+		if json.Unmarshal(body, &failure) == nil && failure.Error != "" {
+			return fmt.Errorf("%s responded with %s: %s", request.URL.Host, failure.Error, failure.ErrorDescription)
+		}
+		return fmt.Errorf("%s responded with status %d", request.URL.Host, response.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, target); err != nil {
+		return fmt.Errorf("%s sent an invalid response: %w", request.URL.Host, err)
+	}
+
+	// Some providers answer a failed token request with a 200 and an error field
+	if failure, ok := target.(*tokenResponse); ok && failure.Error != "" {
+		return fmt.Errorf("%s responded with %s: %s", request.URL.Host, failure.Error, failure.ErrorDescription)
+	}
+
+	return nil
+}
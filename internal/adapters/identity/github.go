@@ -0,0 +1,147 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+// GitHubConfig describes the OAuth app registered with GitHub
+type GitHubConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// GitHubProvider signs users in with GitHub. GitHub does not issue ID tokens, so the identity is
+// read from its API with the access token the code is exchanged for.
+type GitHubProvider struct {
+	config   GitHubConfig
+	client   *http.Client
+	authURL  string
+	tokenURL string
+	apiURL   string
+}
+
+type gitHubUser struct {
+	ID    int64  `json:"id"`
+	Login string `json:"login"`
+	Name  string `json:"name"`
+}
+
+type gitHubEmail struct {
+	Email    string `json:"email"`
+	Primary  bool   `json:"primary"`
+	Verified bool   `json:"verified"`
+}
+
+func NewGitHubProvider(config GitHubConfig, client *http.Client) *GitHubProvider {
+	if client == nil {
+		client = defaultClient()
+	}
+
+	return &GitHubProvider{
+		config:   config,
+		client:   client,
+		authURL:  "https://github.com/login/oauth/authorize",
+		tokenURL: "https://github.com/login/oauth/access_token",
+		apiURL:   "https://api.github.com",
+	}
+}
+
+func (p *GitHubProvider) Name() string {
+	return "github"
+}
+
+func (p *GitHubProvider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	return authCodeURL(p.authURL, url.Values{
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {"read:user user:email"},
+		"state":                 {state},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	})
+}
+
+// Exchange ignores the nonce, which only applies to ID tokens
+func (p *GitHubProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*dto.ExternalIdentityDto, error) {
+	form := url.Values{
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	request, err := newTokenRequest(ctx, p.tokenURL, form, p.config.ClientID, p.config.ClientSecret, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var token tokenResponse
+	if err := doJSON(p.client, request, &token); err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("the token response has no access_token")
+	}
+
+	var user gitHubUser
+	if err := p.get(ctx, token.AccessToken, "/user", &user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, errors.New("the GitHub user has no id")
+	}
+
+	var emails []gitHubEmail
+	if err := p.get(ctx, token.AccessToken, "/user/emails", &emails); err != nil {
+		return nil, err
+	}
+
+	identity := dto.ExternalIdentityDto{
+		Provider: p.Name(),
+		Subject:  strconv.FormatInt(user.ID, 10),
+		FullName: user.Name,
+	}
+	if identity.FullName == "" {
+		identity.FullName = user.Login
+	}
+
+	identity.Email, identity.EmailVerified = chooseGitHubEmail(emails)
+	return &identity, nil
+}
+
+func (p *GitHubProvider) get(ctx context.Context, accessToken string, path string, target interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, p.apiURL+path, nil)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Accept", "application/vnd.github+json")
+	request.Header.Set("Authorization", "Bearer "+accessToken)
+	return doJSON(p.client, request, target)
+}
+
+// chooseGitHubEmail prefers the primary address, but a verified address over one that is not as
+// only a verified address proves ownership
+func chooseGitHubEmail(emails []gitHubEmail) (string, bool) {
+	var chosen *gitHubEmail
+	for i, email := range emails {
+		if email.Primary && email.Verified {
+			return email.Email, true
+		}
+
+		if chosen == nil || (email.Verified && !chosen.Verified) || (email.Primary && !chosen.Verified) {
+			chosen = &emails[i]
+		}
+	}
+
+	if chosen == nil {
+		return "", false
+	}
+
+	return chosen.Email, chosen.Verified
+}
@@ -0,0 +1,91 @@
+package identity
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProviders(t *testing.T) {
+	providers := NewProviders(config.IdentityConfig{Providers: []config.IdentityProviderConfig{
+		{
+			Name:         "company-sso",
+			Issuer:       "https://sso.example.com",
+			ClientID:     "client",
+			ClientSecret: "secret",
+			RedirectURL:  "https://api.example.com/callback",
+		},
+		{
+			Name:         "github",
+			ClientID:     "client",
+			ClientSecret: "secret",
+			RedirectURL:  "https://api.example.com/callback",
+		},
+	}})
+	assert.Len(t, providers, 2)
+	assert.Equal(t, "company-sso", providers[0].Name())
+	assert.IsType(t, &OIDCProvider{}, providers[0])
+	assert.Equal(t, "github", providers[1].Name())
+	assert.IsType(t, &GitHubProvider{}, providers[1])
+
+	assert.Empty(t, NewProviders(config.IdentityConfig{}))
+}
+
+func TestPublicKeysFromJWKs(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	edKey, _, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	encode := base64.RawURLEncoding.EncodeToString
+	ec := jsonWebKey{KeyType: "EC", Curve: "P-256", X: encode(ecKey.X.Bytes()), Y: encode(ecKey.Y.Bytes())}
+	key, err := ec.publicKey()
+	assert.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(key))
+
+	okp := jsonWebKey{KeyType: "OKP", Curve: "Ed25519", X: encode(edKey)}
+	key, err = okp.publicKey()
+	assert.NoError(t, err)
+	assert.True(t, edKey.Equal(key))
+
+	_, err = jsonWebKey{KeyType: "oct"}.publicKey()
+	assert.Error(t, err)
+	_, err = jsonWebKey{KeyType: "EC", Curve: "P-192"}.publicKey()
+	assert.Error(t, err)
+}
+
+func TestEmailVerifiedAcceptsStrings(t *testing.T) {
+	var claims idTokenClaims
+	assert.NoError(t, json.Unmarshal([]byte(`{"email_verified":"true"}`), &claims))
+	assert.True(t, bool(claims.EmailVerified))
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"email_verified":false}`), &claims))
+	assert.False(t, bool(claims.EmailVerified))
+}
+
+func TestChooseGitHubEmail(t *testing.T) {
+	email, verified := chooseGitHubEmail([]gitHubEmail{
+		{Email: "old@example.com", Verified: true},
+		{Email: "primary@example.com", Primary: true},
+	})
+	assert.Equal(t, "old@example.com", email)
+	assert.True(t, verified)
+
+	email, verified = chooseGitHubEmail([]gitHubEmail{
+		{Email: "other@example.com", Verified: true},
+		{Email: "primary@example.com", Primary: true, Verified: true},
+	})
+	assert.Equal(t, "primary@example.com", email)
+	assert.True(t, verified)
+
+	email, verified = chooseGitHubEmail(nil)
+	assert.Empty(t, email)
+	assert.False(t, verified)
+}
@@ -0,0 +1,275 @@
+package identity
+
+import (
+	"context"
+	"crypto"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+// OIDCConfig describes the client registered with an OpenID Connect provider
+type OIDCConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// Scopes default to openid, email and profile
+	Scopes []string
+}
+
+// OIDCProvider signs users in with any OpenID Connect provider, such as Google or a company's
+// single sign-on. The endpoints are discovered from the issuer, and ID tokens are verified
+// against the keys the provider publishes before they are trusted.
+type OIDCProvider struct {
+	config OIDCConfig
+	client *http.Client
+	now    func() time.Time
+
+	mu        sync.Mutex
+	discovery *discoveryDocument
+	keys      map[string]crypto.PublicKey
+}
+
+type discoveryDocument struct {
+	Issuer                string   `json:"issuer"`
+	AuthorizationEndpoint string   `json:"authorization_endpoint"`
+	TokenEndpoint         string   `json:"token_endpoint"`
+	JWKSURI               string   `json:"jwks_uri"`
+	TokenAuthMethods      []string `json:"token_endpoint_auth_methods_supported"`
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce           string       `json:"nonce"`
+	AuthorizedParty string       `json:"azp"`
+	Email           string       `json:"email"`
+	EmailVerified   flexibleBool `json:"email_verified"`
+	Name            string       `json:"name"`
+}
+
+// flexibleBool accepts booleans sent as strings, which some providers do for email_verified
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+	*b = flexibleBool(value == "true")
+	return nil
+}
+
+// Algorithms ID tokens may be signed with; symmetric ones are refused as the client secret
+// would be the key
+var idTokenAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+func NewOIDCProvider(config OIDCConfig, client *http.Client) *OIDCProvider {
+	return NewOIDCProviderWithClock(config, client, time.Now)
+}
+
+// NewOIDCProviderWithClock creates an OpenID Connect provider reading the time from now
+func NewOIDCProviderWithClock(config OIDCConfig, client *http.Client, now func() time.Time) *OIDCProvider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	if client == nil {
+		client = defaultClient()
+	}
+
+	return &OIDCProvider{config: config, client: client, now: now}
+}
+
+func (p *OIDCProvider) Name() string {
+	return p.config.Name
+}
+
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return authCodeURL(discovery.AuthorizationEndpoint, url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.config.ClientID},
+		"redirect_uri":          {p.config.RedirectURL},
+		"scope":                 {strings.Join(p.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	})
+}
+
+func (p *OIDCProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*dto.ExternalIdentityDto, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.config.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+
+	// client_secret_basic is the default when the provider does not say what it supports
+	basicAuth := len(discovery.TokenAuthMethods) == 0 || slices.Contains(discovery.TokenAuthMethods, "client_secret_basic")
+	request, err := newTokenRequest(ctx, discovery.TokenEndpoint, form, p.config.ClientID, p.config.ClientSecret, basicAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	var token tokenResponse
+	if err := doJSON(p.client, request, &token); err != nil {
+		return nil, err
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("the token response has no id_token")
+	}
+
+	claims, err := p.verifyIDToken(ctx, discovery, token.IDToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.Nonce), []byte(nonce)) != 1 {
+		return nil, errors.New("the id_token nonce does not match the login")
+	}
+
+	return &dto.ExternalIdentityDto{
+		Provider:      p.config.Name,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		FullName:      claims.Name,
+	}, nil
+}
+
+// verifyIDToken checks the signature, issuer, audience and lifetime of an ID token
+func (p *OIDCProvider) verifyIDToken(ctx context.Context, discovery *discoveryDocument, idToken string) (*idTokenClaims, error) {
+	var claims idTokenClaims
+	_, err := jwt.ParseWithClaims(idToken, &claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.verificationKey(ctx, discovery, kid)
+	},
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(discovery.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(p.now),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("the id_token has no subject")
+	}
+
+	// A token meant for several clients has to name us as the party it was issued to
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.config.ClientID {
+		return nil, errors.New("the id_token was issued to another client")
+	}
+
+	return &claims, nil
+}
+
+// verificationKey returns the published key with the given ID, fetching the keys again when it
+// is unknown as the provider may have rotated them
+func (p *OIDCProvider) verificationKey(ctx context.Context, discovery *discoveryDocument, kid string) (crypto.PublicKey, error) {
+	p.mu.Lock()
+	keys := p.keys
+	p.mu.Unlock()
+
+	key, ok := findKey(keys, kid)
+	if ok {
+		return key, nil
+	}
+
+	keys, err := fetchKeys(ctx, p.client, discovery.JWKSURI)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	key, ok = findKey(keys, kid)
+	if !ok {
+		return nil, fmt.Errorf("the signing key %q is not published by the provider", kid)
+	}
+
+	return key, nil
+}
+
+// discover fetches the provider configuration once and keeps it
+func (p *OIDCProvider) discover(ctx context.Context) (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	issuer := strings.TrimSuffix(p.config.Issuer, "/")
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var discovery discoveryDocument
+	if err := doJSON(p.client, request, &discovery); err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("the provider claims to be %q instead of %q", discovery.Issuer, p.config.Issuer)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("the provider configuration is missing an endpoint")
+	}
+
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+// findKey looks a key up by ID; a token without one can only use the key of a single key set
+func findKey(keys map[string]crypto.PublicKey, kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(keys) == 1 {
+		for _, key := range keys {
+			return key, true
+		}
+	}
+
+	key, ok := keys[kid]
+	return key, ok
+}
+
+// authCodeURL adds the parameters of an authorization request to an endpoint
+func authCodeURL(endpoint string, params url.Values) (string, error) {
+	authURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	query := authURL.Query()
+	for name, values := range params {
+		query[name] = values
+	}
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
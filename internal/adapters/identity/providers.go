@@ -0,0 +1,33 @@
+package identity
+
+import (
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+)
+
+// NewProviders creates the configured identity providers: GitHub for the one named github and
+// an OpenID Connect provider for the others. The configuration is expected to be validated.
+func NewProviders(config config.IdentityConfig) []ports.IdentityProvider {
+	var providers []ports.IdentityProvider
+	for _, provider := range config.Providers {
+		if provider.Name == "github" {
+			providers = append(providers, NewGitHubProvider(GitHubConfig{
+				ClientID:     provider.ClientID,
+				ClientSecret: provider.ClientSecret,
+				RedirectURL:  provider.RedirectURL,
+			}, nil))
+			continue
+		}
+
+		providers = append(providers, NewOIDCProvider(OIDCConfig{
+			Name:         provider.Name,
+			Issuer:       provider.Issuer,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+		}, nil))
+	}
+
+	return providers
+}
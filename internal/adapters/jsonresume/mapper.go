@@ -0,0 +1,163 @@
+package jsonresume
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+// The schema allows partial ISO 8601 dates, so a year or a year and month are valid too
+var dateLayouts = []string{"2006-01-02", "2006-01", "2006"}
+
+const dateLayout = "2006-01-02"
+
+// ToCreateResumeDto maps a JSON Resume document into the payload used to create a resume
+func ToCreateResumeDto(document Resume) (dto.CreateResumeDto, error) {
+	payload := dto.CreateResumeDto{
+		Summary:     strings.TrimSpace(document.Basics.Summary),
+		Skills:      []string{},
+		Experiences: []dto.WorkExperienceDto{},
+		Education:   []dto.EducationDto{},
+	}
+
+	seen := make(map[string]bool)
+	for _, skill := range document.Skills {
+		for _, name := range append([]string{skill.Name}, skill.Keywords...) {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[strings.ToLower(name)] {
+				continue
+			}
+			seen[strings.ToLower(name)] = true
+			payload.Skills = append(payload.Skills, name)
+		}
+	}
+
+	for i, work := range document.Work {
+		startDate, endDate, err := parsePeriod(work.StartDate, work.EndDate)
+		if err != nil {
+			return payload, fmt.Errorf("work[%d]: %w", i, err)
+		}
+
+		company := work.Name
+		if company == "" {
+			company = work.Company
+		}
+
+		payload.Experiences = append(payload.Experiences, dto.WorkExperienceDto{
+			CompanyName: company,
+			Role:        work.Position,
+			StartDate:   startDate,
+			EndDate:     endDate,
+		})
+	}
+
+	for i, education := range document.Education {
+		startDate, endDate, err := parsePeriod(education.StartDate, education.EndDate)
+		if err != nil {
+			return payload, fmt.Errorf("education[%d]: %w", i, err)
+		}
+
+		course := education.Area
+		if course == "" {
+			course = education.StudyType
+		}
+
+		payload.Education = append(payload.Education, dto.EducationDto{
+			SchoolName: education.Institution,
+			Course:     course,
+			StudyType:  education.StudyType,
+			StartDate:  startDate,
+			EndDate:    endDate,
+		})
+	}
+
+	return payload, nil
+}
+
+// FromResume maps a stored resume, with its owner, experiences and education, into a JSON Resume document
+func FromResume(resume domain.Resume) Resume {
+	document := Resume{
+		Schema: SchemaURL,
+		Basics: Basics{
+			Summary: resume.Summary,
+		},
+		Work:      []Work{},
+		Education: []Education{},
+		Skills:    []Skill{},
+		Meta: &Meta{
+			Version:      "v1.0.0",
+			LastModified: resume.UpdatedAt.UTC().Format(time.RFC3339),
+		},
+	}
+
+	if resume.User != nil {
+		document.Basics.Name = resume.User.FullName
+		document.Basics.Email = resume.User.Email
+	}
+
+	for _, experience := range resume.Experiences {
+		document.Work = append(document.Work, Work{
+			Name:      experience.CompanyName,
+			Position:  experience.Role,
+			StartDate: experience.StartDate.Format(dateLayout),
+			EndDate:   formatOptionalDate(experience.EndDate),
+		})
+	}
+
+	for _, education := range resume.Education {
+		document.Education = append(document.Education, Education{
+			Institution: education.SchoolName,
+			Area:        education.Course,
+			StudyType:   education.StudyType,
+			StartDate:   education.StartDate.Format(dateLayout),
+			EndDate:     formatOptionalDate(education.EndDate),
+		})
+	}
+
+	for _, skill := range resume.Skills {
+		document.Skills = append(document.Skills, Skill{Name: skill})
+	}
+
+	return document
+}
+
+func parsePeriod(start string, end string) (time.Time, *time.Time, error) {
+	if start == "" {
+		return time.Time{}, nil, fmt.Errorf("startDate is required")
+	}
+
+	startDate, err := parseDate(start)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("startDate %w", err)
+	}
+
+	if end == "" {
+		return startDate, nil, nil
+	}
+
+	endDate, err := parseDate(end)
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("endDate %w", err)
+	}
+
+	return startDate, &endDate, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a valid ISO 8601 date", value)
+}
+
+func formatOptionalDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(dateLayout)
+}
@@ -0,0 +1,105 @@
+package jsonresume_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/database/repository"
+	"github.com/stivo-m/vise-resume/internal/adapters/jsonresume"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+const document = `{
+  "basics": {"name": "Jane Doe", "email": "jane@example.com", "summary": "Backend engineer focused on APIs"},
+  "work": [
+    {"name": "Acme", "position": "Senior Engineer", "startDate": "2019-03", "endDate": "2022-01-31"},
+    {"company": "Initech", "position": "Staff Engineer", "startDate": "2022-02-01"}
+  ],
+  "education": [
+    {"institution": "State University", "area": "Computer Science", "studyType": "Bachelor", "startDate": "2012", "endDate": "2016"}
+  ],
+  "skills": [
+    {"name": "Go", "keywords": ["gRPC", "go"]},
+    {"name": "PostgreSQL"}
+  ]
+}`
+
+func TestToCreateResumeDto(t *testing.T) {
+	var doc jsonresume.Resume
+	assert.Nil(t, json.Unmarshal([]byte(document), &doc))
+
+	payload, err := jsonresume.ToCreateResumeDto(doc)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "Backend engineer focused on APIs", payload.Summary)
+	assert.Equal(t, []string{"Go", "gRPC", "PostgreSQL"}, payload.Skills)
+	assert.Len(t, payload.Experiences, 2)
+	assert.Equal(t, "Acme", payload.Experiences[0].CompanyName)
+	assert.Equal(t, "2019-03-01", payload.Experiences[0].StartDate.Format("2006-01-02"))
+	assert.Equal(t, "Initech", payload.Experiences[1].CompanyName)
+	assert.Nil(t, payload.Experiences[1].EndDate)
+	assert.Equal(t, "Computer Science", payload.Education[0].Course)
+	assert.Equal(t, "Bachelor", payload.Education[0].StudyType)
+}
+
+func TestToCreateResumeDtoRejectsInvalidDates(t *testing.T) {
+	doc := jsonresume.Resume{
+		Work: []jsonresume.Work{{Name: "Acme", Position: "Engineer", StartDate: "March 2019"}},
+	}
+
+	_, err := jsonresume.ToCreateResumeDto(doc)
+	assert.ErrorContains(t, err, `work[0]: startDate "March 2019" is not a valid ISO 8601 date`)
+
+	doc = jsonresume.Resume{
+		Education: []jsonresume.Education{{Institution: "State University", Area: "Physics"}},
+	}
+
+	_, err = jsonresume.ToCreateResumeDto(doc)
+	assert.ErrorContains(t, err, "education[0]: startDate is required")
+}
+
+func TestRoundTripThroughDatabase(t *testing.T) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err, "Failed to setup test database")
+
+	ctx := context.Background()
+	user, err := repository.NewUserRepository(db).CreateUser(ctx, test.GenerateFakeUser())
+	assert.Nil(t, err)
+
+	var original jsonresume.Resume
+	assert.Nil(t, json.Unmarshal([]byte(document), &original))
+
+	payload, err := jsonresume.ToCreateResumeDto(original)
+	assert.Nil(t, err)
+
+	repo := repository.NewResumeRepository(db)
+	created, err := repo.CreateResume(ctx, dto.ResumeDto{UserId: user.ID, Summary: payload.Summary, Skills: payload.Skills})
+	assert.Nil(t, err)
+	assert.Nil(t, repo.AddWorkExperiences(ctx, created.ID, payload.Experiences))
+	assert.Nil(t, repo.AddEducation(ctx, created.ID, payload.Education))
+
+	stored, err := repo.FindResumeById(ctx, created.ID)
+	assert.Nil(t, err)
+
+	exported := jsonresume.FromResume(*stored)
+	assert.Equal(t, jsonresume.SchemaURL, exported.Schema)
+	assert.Equal(t, user.FullName, exported.Basics.Name)
+	assert.Equal(t, user.Email, exported.Basics.Email)
+	assert.Equal(t, original.Basics.Summary, exported.Basics.Summary)
+	assert.Equal(t, []jsonresume.Work{
+		{Name: "Acme", Position: "Senior Engineer", StartDate: "2019-03-01", EndDate: "2022-01-31"},
+		{Name: "Initech", Position: "Staff Engineer", StartDate: "2022-02-01"},
+	}, exported.Work)
+	assert.Equal(t, []jsonresume.Education{
+		{Institution: "State University", Area: "Computer Science", StudyType: "Bachelor", StartDate: "2012-01-01", EndDate: "2016-01-01"},
+	}, exported.Education)
+	assert.Equal(t, []jsonresume.Skill{{Name: "Go"}, {Name: "gRPC"}, {Name: "PostgreSQL"}}, exported.Skills)
+
+	reimported, err := jsonresume.ToCreateResumeDto(exported)
+	assert.Nil(t, err)
+	assert.Equal(t, payload, reimported)
+}
@@ -0,0 +1,26 @@
+package jsonresume
+
+import (
+	"encoding/json"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+)
+
+type Renderer struct {
+}
+
+func NewRenderer() *Renderer {
+	return &Renderer{}
+}
+
+func (r Renderer) Format() string {
+	return "json"
+}
+
+func (r Renderer) ContentType() string {
+	return "application/json"
+}
+
+func (r Renderer) Render(resume domain.Resume) ([]byte, error) {
+	return json.MarshalIndent(FromResume(resume), "", "  ")
+}
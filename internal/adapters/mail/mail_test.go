@@ -0,0 +1,168 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderEveryTemplate(t *testing.T) {
+	mailer, err := NewMailer("Vise Resume <no-reply@example.com>", NewOutbox())
+	assert.NoError(t, err)
+
+	for _, template := range []string{"email-verification", "password-reset", "account-deletion"} {
+		message, err := mailer.Render(dto.MailDto{
+			To:       "jane@example.com",
+			Template: template,
+			Data:     map[string]interface{}{"Name": "Jane <Doe>", "Code": "123456"},
+		})
+		assert.NoError(t, err, template)
+		assert.NotEmpty(t, message.Subject, template)
+		assert.Contains(t, message.Text, "Jane <Doe>", template)
+		assert.Contains(t, message.Text, "123456", template)
+		assert.Contains(t, message.HTML, "Jane &lt;Doe&gt;", template)
+		assert.Contains(t, message.HTML, "123456", template)
+	}
+}
+
+func TestRenderUnknownTemplate(t *testing.T) {
+	mailer, err := NewMailer("no-reply@example.com", NewOutbox())
+	assert.NoError(t, err)
+
+	err = mailer.Send(context.Background(), dto.MailDto{To: "jane@example.com", Template: "missing"})
+	assert.Error(t, err)
+}
+
+func TestOutboxKeepsDeliveredMessages(t *testing.T) {
+	outbox := NewOutbox()
+	mailer, err := NewMailer("no-reply@example.com", outbox)
+	assert.NoError(t, err)
+
+	for _, to := range []string{"jane@example.com", "john@example.com"} {
+		err = mailer.Send(context.Background(), dto.MailDto{
+			To:       to,
+			Template: "password-reset",
+			Data:     map[string]interface{}{"Name": "Jane", "Code": "654321"},
+		})
+		assert.NoError(t, err)
+	}
+
+	assert.Len(t, outbox.Messages(), 2)
+	message, ok := outbox.LastTo("jane@example.com")
+	assert.True(t, ok)
+	assert.Equal(t, "Reset your password", message.Subject)
+
+	_, ok = outbox.LastTo("nobody@example.com")
+	assert.False(t, ok)
+}
+
+func TestFileOutboxWritesMessages(t *testing.T) {
+	dir := t.TempDir()
+	mailer, err := NewMailer("no-reply@example.com", NewFileOutbox(dir))
+	assert.NoError(t, err)
+
+	err = mailer.Send(context.Background(), dto.MailDto{
+		To:       "jane@example.com",
+		Template: "email-verification",
+		Data:     map[string]interface{}{"Name": "Jane", "Code": "123456"},
+	})
+	assert.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.True(t, strings.HasSuffix(entries[0].Name(), "jane@example.com.eml"))
+
+	content, err := os.ReadFile(dir + "/" + entries[0].Name())
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "To: jane@example.com\r\n")
+	assert.Contains(t, string(content), "Content-Type: multipart/alternative")
+	assert.Contains(t, string(content), "Content-Type: text/plain; charset=utf-8")
+	assert.Contains(t, string(content), "Content-Type: text/html; charset=utf-8")
+	assert.Contains(t, string(content), "123456")
+}
+
+// fakeSMTPServer accepts a single SMTP session and returns the commands and data it received
+func fakeSMTPServer(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	received := make(chan string, 1)
+
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- ""
+			return
+		}
+		defer conn.Close()
+
+		var transcript strings.Builder
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				break
+			}
+			transcript.WriteString(line)
+
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					reply("250 OK")
+				}
+				continue
+			}
+
+			switch command := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case command == "DATA":
+				inData = true
+				reply("354 Start mail input")
+			case command == "QUIT":
+				reply("221 Bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+		received <- transcript.String()
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestSMTPTransportDeliversMessages(t *testing.T) {
+	address, received := fakeSMTPServer(t)
+	host, portValue, _ := net.SplitHostPort(address)
+	port, _ := strconv.Atoi(portValue)
+
+	mailer, err := NewMailer("Vise Resume <no-reply@example.com>", NewSMTPTransport(host, port, "", ""))
+	assert.NoError(t, err)
+
+	err = mailer.Send(context.Background(), dto.MailDto{
+		To:       "jane@example.com",
+		Template: "email-verification",
+		Data:     map[string]interface{}{"Name": "Jane", "Code": "123456"},
+	})
+	assert.NoError(t, err)
+
+	transcript := <-received
+	assert.Contains(t, transcript, "MAIL FROM:<no-reply@example.com>")
+	assert.Contains(t, transcript, "RCPT TO:<jane@example.com>")
+	assert.Contains(t, transcript, "Subject: Verify your email address")
+	assert.Contains(t, transcript, "123456")
+}
@@ -0,0 +1,129 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+//go:embed templates/*
+var templateFiles embed.FS
+
+// Transport delivers rendered messages, e.g. over SMTP or into an outbox
+type Transport interface {
+	Deliver(ctx context.Context, message Message) error
+}
+
+// Mailer renders messages from the embedded templates and hands them to a transport.
+// Every template consists of a <name>.txt file, which also defines the subject,
+// and a <name>.html file.
+type Mailer struct {
+	from      string
+	transport Transport
+	templates map[string]mailTemplate
+}
+
+type mailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+func NewMailer(from string, transport Transport) (*Mailer, error) {
+	files, err := fs.Glob(templateFiles, "templates/*.txt")
+	if err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]mailTemplate, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".txt")
+
+		text, err := texttemplate.ParseFS(templateFiles, file)
+		if err != nil {
+			return nil, err
+		}
+
+		html, err := htmltemplate.ParseFS(templateFiles, "templates/"+name+".html")
+		if err != nil {
+			return nil, err
+		}
+
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("mail template %q does not define a subject", name)
+		}
+
+		templates[name] = mailTemplate{text: text, html: html}
+	}
+
+	return &Mailer{
+		from:      from,
+		transport: transport,
+		templates: templates,
+	}, nil
+}
+
+// NewMailerFromConfig creates a mailer using the configured transport: "smtp" sends through
+// the SMTP server, "file", the default for local development, writes messages to the outbox
+// directory and "memory" keeps them in memory.
+func NewMailerFromConfig(config config.MailConfig) (*Mailer, error) {
+	var transport Transport
+	switch config.Driver {
+	case "smtp":
+		transport = NewSMTPTransport(config.SMTP.Host, config.SMTP.Port, config.SMTP.Username, config.SMTP.Password)
+	case "file":
+		transport = NewFileOutbox(config.OutboxDir)
+	case "memory":
+		transport = NewOutbox()
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", config.Driver)
+	}
+
+	return NewMailer(config.From, transport)
+}
+
+func (m *Mailer) Send(ctx context.Context, payload dto.MailDto) error {
+	message, err := m.Render(payload)
+	if err != nil {
+		return err
+	}
+
+	return m.transport.Deliver(ctx, *message)
+}
+
+// Render builds the message for payload without delivering it
+func (m *Mailer) Render(payload dto.MailDto) (*Message, error) {
+	template, ok := m.templates[payload.Template]
+	if !ok {
+		return nil, fmt.Errorf("unknown mail template %q", payload.Template)
+	}
+
+	var subject, textBody, htmlBody bytes.Buffer
+	if err := template.text.ExecuteTemplate(&subject, "subject", payload.Data); err != nil {
+		return nil, err
+	}
+	if err := template.text.Execute(&textBody, payload.Data); err != nil {
+		return nil, err
+	}
+	if err := template.html.Execute(&htmlBody, payload.Data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		From:    m.from,
+		To:      payload.To,
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    textBody.String(),
+		HTML:    htmlBody.String(),
+		Date:    time.Now(),
+	}, nil
+}
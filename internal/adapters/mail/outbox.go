@@ -0,0 +1,77 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+)
+
+// Outbox keeps delivered messages in memory, which lets tests
+// read the codes sent to users without a mail server
+type Outbox struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+func (o *Outbox) Deliver(ctx context.Context, message Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.messages = append(o.messages, message)
+	return nil
+}
+
+// Messages returns every message delivered so far, oldest first
+func (o *Outbox) Messages() []Message {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append([]Message(nil), o.messages...)
+}
+
+// LastTo returns the most recent message delivered to the given address
+func (o *Outbox) LastTo(to string) (Message, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for i := len(o.messages) - 1; i >= 0; i-- {
+		if o.messages[i].To == to {
+			return o.messages[i], true
+		}
+	}
+
+	return Message{}, false
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9@._-]+`)
+
+// FileOutbox writes every message as an .eml file into a directory,
+// so messages sent during local development can be opened in a mail client
+type FileOutbox struct {
+	dir string
+}
+
+func NewFileOutbox(dir string) *FileOutbox {
+	return &FileOutbox{dir: dir}
+}
+
+func (o *FileOutbox) Deliver(ctx context.Context, message Message) error {
+	if err := os.MkdirAll(o.dir, 0o755); err != nil {
+		return err
+	}
+
+	content, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", message.Date.Format("20060102T150405.000000000"), unsafeFileChars.ReplaceAllString(message.To, "_"))
+	return os.WriteFile(filepath.Join(o.dir, name), content, 0o600)
+}
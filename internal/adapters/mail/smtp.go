@@ -0,0 +1,53 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+)
+
+// SMTPTransport delivers messages through an SMTP server, authenticating
+// with PLAIN auth when a username is configured
+type SMTPTransport struct {
+	host     string
+	port     int
+	username string
+	password string
+}
+
+func NewSMTPTransport(host string, port int, username string, password string) *SMTPTransport {
+	return &SMTPTransport{
+		host:     host,
+		port:     port,
+		username: username,
+		password: password,
+	}
+}
+
+func (t *SMTPTransport) Deliver(ctx context.Context, message Message) error {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	content, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if t.username != "" {
+		auth = smtp.PlainAuth("", t.username, t.password, t.host)
+	}
+
+	address := net.JoinHostPort(t.host, strconv.Itoa(t.port))
+	return smtp.SendMail(address, auth, from.Address, []string{to.Address}, content)
+}
@@ -3,14 +3,21 @@ package middleware
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
 	"github.com/stivo-m/vise-resume/internal/core/dto"
 	"github.com/stivo-m/vise-resume/internal/core/ports"
 	"github.com/stivo-m/vise-resume/internal/core/utils"
 )
 
-func AuthMiddleware(tokenPort ports.TokenService, userPort ports.UserPort) fiber.Handler {
+// AuthMiddleware authenticates requests made with an access token. Personal access tokens
+// are only accepted on routes that list scopes, and must have been granted one of them.
+// Refused tokens are recorded in the audit log.
+func AuthMiddleware(tokenPort ports.TokenService, userPort ports.UserPort, auditPort ports.AuditPort, scopes ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Check for authorization header and token
 		tokenString := c.Get("Authorization")
@@ -24,9 +31,14 @@ func AuthMiddleware(tokenPort ports.TokenService, userPort ports.UserPort) fiber
 
 		// Bearer token format: "Bearer <token>"
 		tokenString = tokenString[len("Bearer "):]
+		if strings.HasPrefix(tokenString, utils.PERSONAL_ACCESS_TOKEN_PREFIX) {
+			return authenticatePersonalAccessToken(c, userPort, auditPort, tokenString, scopes)
+		}
+
 		userId, err := tokenPort.VerifyToken(tokenString)
 
 		if err != nil {
+			auditRejectedToken(c, auditPort, "", "invalid or expired access token")
 			res := utils.FormatApiResponse(
 				"authentication failed",
 				err,
@@ -37,9 +49,11 @@ func AuthMiddleware(tokenPort ports.TokenService, userPort ports.UserPort) fiber
 		record, err := userPort.FindToken(context.Background(), dto.ManageTokenDto{
 			ID:          userId,
 			AccessToken: tokenString,
+			Type:        "access",
 		})
 
 		if err != nil {
+			auditRejectedToken(c, auditPort, userId, "revoked access token")
 			res := utils.FormatApiResponse(
 				"authentication failed",
 				err,
@@ -48,6 +62,7 @@ func AuthMiddleware(tokenPort ports.TokenService, userPort ports.UserPort) fiber
 		}
 
 		if record == nil || record.DeletedAt.Valid {
+			auditRejectedToken(c, auditPort, userId, "revoked access token")
 			res := utils.FormatApiResponse(
 				"authentication failed",
 				nil,
@@ -55,6 +70,8 @@ func AuthMiddleware(tokenPort ports.TokenService, userPort ports.UserPort) fiber
 			return c.Status(fiber.StatusUnauthorized).JSON(res)
 		}
 
+		trackTokenUsage(c, userPort, *record)
+
 		utils.TextLogger.Info("----------------------------------------------------------------")
 		utils.TextLogger.Info(fmt.Sprintf("authenticated user: %s", userId))
 		utils.TextLogger.Info("----------------------------------------------------------------")
@@ -64,3 +81,101 @@ func AuthMiddleware(tokenPort ports.TokenService, userPort ports.UserPort) fiber
 		return c.Next()
 	}
 }
+
+// authenticatePersonalAccessToken authenticates a request made with a personal access token
+func authenticatePersonalAccessToken(c *fiber.Ctx, userPort ports.UserPort, auditPort ports.AuditPort, tokenString string, scopes []string) error {
+	record, err := userPort.FindPersonalAccessToken(context.Background(), utils.HashToken(tokenString))
+	if err != nil || !record.ExpiresAt.After(time.Now()) {
+		actorId := ""
+		if err == nil {
+			actorId = record.UserId
+		}
+		auditRejectedToken(c, auditPort, actorId, "unknown or expired personal access token")
+		res := utils.FormatApiResponse(
+			"authentication failed",
+			nil,
+		)
+		return c.Status(fiber.StatusUnauthorized).JSON(res)
+	}
+
+	// Disabling an account revokes its logins but keeps its tokens, so check the owner on every use
+	user, err := userPort.FindUser(context.Background(), dto.FindUserDto{ID: record.UserId})
+	if err != nil || user.DisabledAt != nil {
+		auditRejectedToken(c, auditPort, record.UserId, "personal access token of a disabled account")
+		res := utils.FormatApiResponse(
+			"authentication failed",
+			nil,
+		)
+		return c.Status(fiber.StatusUnauthorized).JSON(res)
+	}
+
+	granted := record.ScopeList()
+	if !slices.ContainsFunc(scopes, func(scope string) bool { return slices.Contains(granted, scope) }) {
+		auditRejectedToken(c, auditPort, record.UserId, "personal access token without the scope of the route")
+		res := utils.FormatApiResponse(
+			"the token does not have the scope required by this route",
+			fiber.Map{"required_scopes": scopes},
+		)
+		return c.Status(fiber.StatusForbidden).JSON(res)
+	}
+
+	if record.LastUsedAt == nil || time.Since(*record.LastUsedAt) >= tokenUsageInterval {
+		err := userPort.UpdatePersonalAccessToken(context.Background(), record.ID, map[string]interface{}{"last_used_at": time.Now()})
+		if err != nil {
+			utils.TextLogger.Error("unable to record personal access token usage", "error", err)
+		}
+	}
+
+	c.Locals(utils.USER_ID_KEY, record.UserId)
+	c.Locals(utils.ACCESS_TOKEN_KEY, tokenString)
+	c.Locals(utils.TOKEN_SCOPES_KEY, granted)
+	return c.Next()
+}
+
+// tokenUsageInterval limits how often the last use of a token is written to the database
+const tokenUsageInterval = time.Minute
+
+// trackTokenUsage records the client using a token so users can recognise their sessions
+func trackTokenUsage(c *fiber.Ctx, userPort ports.UserPort, token domain.Token) {
+	client := Client(c)
+	recentlyUsed := token.LastUsedAt != nil && time.Since(*token.LastUsedAt) < tokenUsageInterval
+	if recentlyUsed && token.IpAddress == client.IpAddress && token.UserAgent == client.UserAgent {
+		return
+	}
+
+	err := userPort.UpdateToken(context.Background(), token.ID, map[string]interface{}{
+		"ip_address":   client.IpAddress,
+		"user_agent":   client.UserAgent,
+		"last_used_at": time.Now(),
+	})
+	if err != nil {
+		utils.TextLogger.Error("unable to record token usage", "error", err)
+	}
+}
+
+// Client describes the client making a request, keeping as much of its user agent as is stored
+func Client(c *fiber.Ctx) dto.ClientDto {
+	userAgent := strings.ToValidUTF8(c.Get(fiber.HeaderUserAgent), "")
+	if len(userAgent) > 255 {
+		userAgent = strings.ToValidUTF8(userAgent[:255], "")
+	}
+
+	return dto.ClientDto{IpAddress: c.IP(), UserAgent: userAgent}
+}
+
+// auditRejectedToken records a refused token in the audit log along with the user it belongs
+// to, when that is known
+func auditRejectedToken(c *fiber.Ctx, auditPort ports.AuditPort, actorId string, reason string) {
+	client := Client(c)
+	err := auditPort.AppendEvent(context.Background(), domain.AuditEvent{
+		ActorId:   actorId,
+		Action:    domain.AuditTokenRejected,
+		Outcome:   domain.AuditFailure,
+		IpAddress: client.IpAddress,
+		UserAgent: client.UserAgent,
+		Details:   reason,
+	})
+	if err != nil {
+		utils.TextLogger.Error("unable to record audit event", "action", domain.AuditTokenRejected, "error", err)
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"context"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// RequirePermission only lets users holding the permission through one of their roles through.
+// It has to run after [AuthMiddleware], which identifies the user.
+func RequirePermission(rolePort ports.RolePort, permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userId, _ := c.Locals(utils.USER_ID_KEY).(string)
+		if userId == "" {
+			res := utils.FormatApiResponse(
+				"unauthorized",
+				nil,
+			)
+			return c.Status(fiber.StatusUnauthorized).JSON(res)
+		}
+
+		permissions, err := rolePort.FindUserPermissions(context.Background(), userId)
+		if err != nil {
+			utils.TextLogger.Error("unable to load user permissions", "user", userId, "error", err)
+		}
+
+		if err != nil || !slices.Contains(permissions, permission) {
+			res := utils.FormatApiResponse(
+				"you do not have permission to perform this action",
+				fiber.Map{"required_permission": permission},
+			)
+			return c.Status(fiber.StatusForbidden).JSON(res)
+		}
+
+		return c.Next()
+	}
+}
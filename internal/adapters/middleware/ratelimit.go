@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// RateLimitMiddleware throttles failed attempts on a route, keyed by the client IP and by the
+// email address in the request body. Requests answered with 401 or 403 count as failures, and a
+// successful request clears the failures of its email address. Locked clients get a 429 with a
+// Retry-After header.
+func RateLimitMiddleware(limiter ports.RateLimiter, scope string) fiber.Handler {
+	return rateLimit(limiter, scope, false)
+}
+
+// RateLimitRequestsMiddleware throttles a route like [RateLimitMiddleware], except that every
+// request counts whatever its answer. It suits the routes that send emails, which answer the
+// same way whether an email was sent or not.
+func RateLimitRequestsMiddleware(limiter ports.RateLimiter, scope string) fiber.Handler {
+	return rateLimit(limiter, scope, true)
+}
+
+func rateLimit(limiter ports.RateLimiter, scope string, countEveryRequest bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx := context.Background()
+		ipKey := scope + ":ip:" + c.IP()
+		keys := []string{ipKey}
+
+		emailKey := ""
+		if email := requestEmail(c); email != "" {
+			emailKey = scope + ":email:" + email
+			keys = append(keys, emailKey)
+		}
+
+		wait, err := limiter.Allow(ctx, keys)
+		if err != nil {
+			// Failing open keeps the API usable when the rate limit store is unavailable
+			utils.TextLogger.Error("unable to check rate limit", "error", err)
+		}
+
+		if wait > 0 {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(int(math.Ceil(wait.Seconds())), 1)))
+			res := utils.FormatApiResponse(
+				"too many attempts, please try again later",
+				nil,
+			)
+			return c.Status(fiber.StatusTooManyRequests).JSON(res)
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		switch status := c.Response().StatusCode(); {
+		case countEveryRequest:
+			err = limiter.RecordFailure(ctx, keys)
+		case status == fiber.StatusUnauthorized || status == fiber.StatusForbidden:
+			err = limiter.RecordFailure(ctx, keys)
+		case status < 300 && emailKey != "":
+			err = limiter.RecordSuccess(ctx, []string{emailKey})
+		}
+
+		if err != nil {
+			utils.TextLogger.Error("unable to record rate limit attempt", "error", err)
+		}
+
+		return nil
+	}
+}
+
+// requestEmail reads the email address from a JSON request body, if there is one
+func requestEmail(c *fiber.Ctx) string {
+	var body struct {
+		Email string `json:"email"`
+	}
+
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(body.Email))
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
 	"github.com/stivo-m/vise-resume/internal/core/utils"
 )
 
@@ -33,36 +34,44 @@ func ValidationMiddleware(dto interface{}) fiber.Handler {
 			return c.Status(fiber.StatusBadRequest).JSON(res)
 		}
 
-		if err := validate.Struct(dtoInstance); err != nil {
-			if _, ok := err.(*validator.InvalidValidationError); ok {
-				res := utils.FormatApiResponse(
-					"Validation error",
-					err.Error(),
-				)
-				return c.Status(fiber.StatusInternalServerError).JSON(res)
+		if status, res := Validate(dtoInstance); res != nil {
+			return c.Status(status).JSON(res)
+		}
+
+		return c.Next()
+	}
+}
 
-			}
+// Validate runs the validation rules of the payload, returning the status code
+// and the response to send back when the payload is invalid
+func Validate(payload interface{}) (int, *dto.ApiResponse[any]) {
+	err := validate.Struct(payload)
+	if err == nil {
+		return fiber.StatusOK, nil
+	}
 
-			var errorList []ValidationErrorDto
-			for _, err := range err.(validator.ValidationErrors) {
-				field := utils.GetJSONFieldName(dtoInstance, err.StructField())
-				item := ValidationErrorDto{
-					Field:   field,
-					Rule:    err.Tag(),
-					Message: utils.GetValidationMessage(err, field),
-				}
-				errorList = append(errorList, item)
-			}
+	if _, ok := err.(*validator.InvalidValidationError); ok {
+		res := utils.FormatApiResponse(
+			"Validation error",
+			err.Error(),
+		)
+		return fiber.StatusInternalServerError, &res
+	}
 
-			if len(errorList) > 0 {
-				data := utils.FormatApiResponse(
-					"one or more of the required fields are invalid or missing",
-					errorList,
-				)
-				return c.Status(fiber.StatusUnprocessableEntity).JSON(data)
-			}
+	var errorList []ValidationErrorDto
+	for _, err := range err.(validator.ValidationErrors) {
+		field := utils.GetJSONFieldName(payload, err.StructField())
+		item := ValidationErrorDto{
+			Field:   field,
+			Rule:    err.Tag(),
+			Message: utils.GetValidationMessage(err, field),
 		}
-
-		return c.Next()
+		errorList = append(errorList, item)
 	}
+
+	res := utils.FormatApiResponse(
+		"one or more of the required fields are invalid or missing",
+		errorList,
+	)
+	return fiber.StatusUnprocessableEntity, &res
 }
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+)
+
+// MemoryStore keeps rate limit state in memory. It suits a single server
+// instance and local development; state is lost on restart.
+type MemoryStore struct {
+	mu     sync.Mutex
+	states map[string]domain.RateLimit
+	saves  int
+}
+
+// Entries without failures for this long are dropped from time to time so the store can not grow forever
+const memoryRetention = 24 * time.Hour
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{states: map[string]domain.RateLimit{}}
+}
+
+func (s *MemoryStore) Find(ctx context.Context, key string) (*domain.RateLimit, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.states[key]
+	if !ok {
+		return nil, nil
+	}
+	return &state, nil
+}
+
+func (s *MemoryStore) Save(ctx context.Context, state domain.RateLimit) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[state.Key] = state
+
+	s.saves++
+	if s.saves%1000 == 0 {
+		s.prune(time.Now().Add(-memoryRetention))
+	}
+	return nil
+}
+
+func (s *MemoryStore) prune(before time.Time) {
+	for key, state := range s.states {
+		locked := state.LockedUntil != nil && state.LockedUntil.After(time.Now())
+		if !locked && state.LastFailureAt.Before(before) {
+			delete(s.states, key)
+		}
+	}
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, key)
+	return nil
+}
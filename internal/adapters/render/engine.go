@@ -0,0 +1,187 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+// Engine is the registry of themes shared by every theme aware renderer.
+// It resolves the theme a resume has chosen and prepares the content of
+// the resume in the order the theme expects.
+type Engine struct {
+	mu           sync.RWMutex
+	themes       map[string]Theme
+	order        []string
+	defaultTheme string
+}
+
+func NewEngine(themes ...Theme) (*Engine, error) {
+	engine := &Engine{themes: make(map[string]Theme)}
+	for _, theme := range themes {
+		if err := engine.Register(theme); err != nil {
+			return nil, err
+		}
+	}
+
+	return engine, nil
+}
+
+// Register adds a theme to the engine. The first registered theme is used
+// for resumes that have not chosen one.
+func (e *Engine) Register(theme Theme) error {
+	if err := theme.validate(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if _, exists := e.themes[theme.ID]; exists {
+		return fmt.Errorf("theme %s is already registered", theme.ID)
+	}
+
+	e.themes[theme.ID] = theme
+	e.order = append(e.order, theme.ID)
+	if e.defaultTheme == "" {
+		e.defaultTheme = theme.ID
+	}
+
+	return nil
+}
+
+func (e *Engine) HasTemplate(id string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	_, ok := e.themes[id]
+	return ok
+}
+
+func (e *Engine) ListTemplates() []dto.TemplateDto {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	templates := []dto.TemplateDto{}
+	for _, id := range e.order {
+		theme := e.themes[id]
+		sections := make([]string, len(theme.Sections))
+		for i, section := range theme.Sections {
+			sections[i] = string(section)
+		}
+
+		templates = append(templates, dto.TemplateDto{
+			ID:          theme.ID,
+			Name:        theme.Name,
+			Description: theme.Description,
+			Layout:      string(theme.Layout),
+			Sections:    sections,
+			IsDefault:   theme.ID == e.defaultTheme,
+		})
+	}
+
+	return templates
+}
+
+// Resolve returns the theme with the given id, or the default theme
+// when the id is empty or no longer registered
+func (e *Engine) Resolve(id string) (Theme, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if theme, ok := e.themes[id]; ok {
+		return theme, nil
+	}
+
+	if theme, ok := e.themes[e.defaultTheme]; ok {
+		return theme, nil
+	}
+
+	return Theme{}, fmt.Errorf("no templates have been registered")
+}
+
+// View is the content of a resume arranged the way a theme wants it to be rendered
+type View struct {
+	Theme    Theme
+	Name     string
+	Email    string
+	Sections []SectionView
+}
+
+type SectionView struct {
+	Kind    Section
+	Title   string
+	Text    string
+	Entries []EntryView
+	Items   []string
+}
+
+type EntryView struct {
+	Title    string
+	Subtitle string
+	Period   string
+}
+
+// Prepare resolves the theme of the resume and lays out its non empty sections
+func (e *Engine) Prepare(resume domain.Resume) (*View, error) {
+	theme, err := e.Resolve(resume.TemplateId)
+	if err != nil {
+		return nil, err
+	}
+
+	view := View{Theme: theme}
+	if resume.User != nil {
+		view.Name = resume.User.FullName
+		view.Email = resume.User.Email
+	}
+
+	for _, kind := range theme.Sections {
+		section := SectionView{Kind: kind, Title: sectionTitles[kind]}
+
+		switch kind {
+		case SectionSummary:
+			section.Text = strings.TrimSpace(resume.Summary)
+		case SectionSkills:
+			section.Items = resume.Skills
+		case SectionExperience:
+			for _, experience := range resume.Experiences {
+				section.Entries = append(section.Entries, EntryView{
+					Title:    experience.Role,
+					Subtitle: experience.CompanyName,
+					Period:   formatPeriod(experience.StartDate, experience.EndDate),
+				})
+			}
+		case SectionEducation:
+			for _, education := range resume.Education {
+				title := education.Course
+				if education.StudyType != "" {
+					title = fmt.Sprintf("%s in %s", education.StudyType, education.Course)
+				}
+
+				section.Entries = append(section.Entries, EntryView{
+					Title:    title,
+					Subtitle: education.SchoolName,
+					Period:   formatPeriod(education.StartDate, education.EndDate),
+				})
+			}
+		}
+
+		if section.Text == "" && len(section.Entries) == 0 && len(section.Items) == 0 {
+			continue
+		}
+		view.Sections = append(view.Sections, section)
+	}
+
+	return &view, nil
+}
+
+func formatPeriod(start time.Time, end *time.Time) string {
+	if end == nil {
+		return fmt.Sprintf("%s - Present", start.Format("Jan 2006"))
+	}
+	return fmt.Sprintf("%s - %s", start.Format("Jan 2006"), end.Format("Jan 2006"))
+}
@@ -0,0 +1,127 @@
+package render_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/render"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func testResume(templateId string) domain.Resume {
+	end := time.Date(2022, 1, 31, 0, 0, 0, 0, time.UTC)
+	return domain.Resume{
+		Summary:    "Backend engineer focused on APIs",
+		Skills:     []string{"Go", "SQL"},
+		TemplateId: templateId,
+		User:       &domain.User{FullName: "Jane Doe", Email: "jane@example.com"},
+		Experiences: []domain.WorkExperience{
+			{CompanyName: "Acme", Role: "Senior Engineer", StartDate: time.Date(2019, 3, 1, 0, 0, 0, 0, time.UTC), EndDate: &end},
+		},
+		Education: []domain.Education{
+			{SchoolName: "State University", Course: "Computer Science", StartDate: time.Date(2014, 9, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+}
+
+func sectionKinds(view *render.View) []render.Section {
+	var kinds []render.Section
+	for _, section := range view.Sections {
+		kinds = append(kinds, section.Kind)
+	}
+	return kinds
+}
+
+func TestEngineRegistersValidThemes(t *testing.T) {
+	engine, err := render.NewEngine(render.DefaultThemes()...)
+	assert.NoError(t, err)
+
+	templates := engine.ListTemplates()
+	if assert.Len(t, templates, 3) {
+		assert.Equal(t, "classic", templates[0].ID)
+		assert.True(t, templates[0].IsDefault, "the first theme is the default")
+		assert.False(t, templates[1].IsDefault)
+	}
+	assert.True(t, engine.HasTemplate("modern"))
+	assert.False(t, engine.HasTemplate("unknown"))
+
+	assert.Error(t, engine.Register(render.DefaultThemes()[0]), "themes are registered once")
+	assert.Error(t, engine.Register(render.Theme{ID: "empty"}))
+	assert.Error(t, engine.Register(render.Theme{ID: "unknown", Sections: []render.Section{"hobbies"}}))
+	assert.Error(t, engine.Register(render.Theme{ID: "twice", Sections: []render.Section{render.SectionSkills, render.SectionSkills}}))
+	assert.Error(t, engine.Register(render.Theme{Sections: []render.Section{render.SectionSkills}}))
+
+	empty, err := render.NewEngine()
+	assert.NoError(t, err)
+	_, err = empty.Resolve("")
+	assert.Error(t, err)
+}
+
+func TestEngineSelectsTheThemeOfTheResume(t *testing.T) {
+	engine, err := render.NewEngine(render.DefaultThemes()...)
+	assert.NoError(t, err)
+
+	view, err := engine.Prepare(testResume("modern"))
+	assert.NoError(t, err)
+	assert.Equal(t, "modern", view.Theme.ID)
+	assert.Equal(t, []render.Section{render.SectionSummary, render.SectionSkills, render.SectionExperience, render.SectionEducation}, sectionKinds(view))
+
+	view, err = engine.Prepare(testResume("minimal"))
+	assert.NoError(t, err)
+	assert.Equal(t, []render.Section{render.SectionExperience, render.SectionEducation, render.SectionSkills, render.SectionSummary}, sectionKinds(view))
+
+	for _, id := range []string{"", "removed"} {
+		view, err = engine.Prepare(testResume(id))
+		assert.NoError(t, err)
+		assert.Equal(t, "classic", view.Theme.ID, "resumes without a registered theme use the default one")
+	}
+}
+
+func TestEnginePreparesTheContent(t *testing.T) {
+	engine, err := render.NewEngine(render.DefaultThemes()...)
+	assert.NoError(t, err)
+
+	resume := testResume("classic")
+	resume.Summary = "  "
+	resume.Skills = nil
+	view, err := engine.Prepare(resume)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Jane Doe", view.Name)
+	assert.Equal(t, "jane@example.com", view.Email)
+	assert.Equal(t, []render.Section{render.SectionExperience, render.SectionEducation}, sectionKinds(view), "empty sections are skipped")
+	assert.Equal(t, render.EntryView{Title: "Senior Engineer", Subtitle: "Acme", Period: "Mar 2019 - Jan 2022"}, view.Sections[0].Entries[0])
+	assert.Equal(t, render.EntryView{Title: "Computer Science", Subtitle: "State University", Period: "Sep 2014 - Present"}, view.Sections[1].Entries[0])
+
+	resume.Education[0].StudyType = "Bachelor"
+	view, err = engine.Prepare(resume)
+	assert.NoError(t, err)
+	assert.Equal(t, "Bachelor in Computer Science", view.Sections[1].Entries[0].Title)
+}
+
+func TestTextRenderersFollowTheTheme(t *testing.T) {
+	engine, err := render.NewEngine(render.DefaultThemes()...)
+	assert.NoError(t, err)
+
+	html, err := render.NewHTMLRenderer(engine).Render(testResume("modern"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(html), `<body class="theme-modern">`)
+	assert.Contains(t, string(html), "text-align: center;")
+	assert.Contains(t, string(html), "<h2>Experience</h2>")
+
+	html, err = render.NewHTMLRenderer(engine).Render(testResume("classic"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(html), "<h2>EXPERIENCE</h2>", "the classic theme uppercases its headings")
+
+	markdown, err := render.NewMarkdownRenderer(engine).Render(testResume("minimal"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(markdown), "# Jane Doe")
+	assert.Contains(t, string(markdown), "**Senior Engineer**, Acme")
+	assert.Less(t,
+		strings.Index(string(markdown), "## Experience"),
+		strings.Index(string(markdown), "## Summary"),
+		"the minimal theme puts the summary last",
+	)
+}
@@ -0,0 +1,87 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/render/pdf"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+)
+
+var htmlTemplate = template.Must(template.New("resume").Funcs(template.FuncMap{
+	"color": func(c pdf.Color) template.CSS {
+		return template.CSS(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+	},
+	"join": strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{if .Name}}{{.Name}} - {{end}}Resume</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; font-size: {{.Theme.Styles.BodySize}}pt; color: {{color .Theme.Styles.TextColor}}; max-width: 800px; margin: 40px auto; }
+header { text-align: {{if eq .Theme.Layout "centered"}}center{{else}}left{{end}}; }
+header h1 { font-size: {{.Theme.Styles.NameSize}}pt; margin: 0; }
+header p, .period { color: {{color .Theme.Styles.MutedColor}}; }
+h2 { font-size: {{.Theme.Styles.SectionSize}}pt; color: {{color .Theme.Styles.AccentColor}};{{if .Theme.Styles.SectionRules}} border-bottom: 1px solid {{color .Theme.Styles.RuleColor}};{{end}} }
+.entry { display: flex; justify-content: space-between; }
+.entry h3 { font-size: 1em; margin: 12px 0 2px; }
+</style>
+</head>
+<body class="theme-{{.Theme.ID}}">
+{{- if .Name}}
+<header>
+<h1>{{.Name}}</h1>
+<p>{{.Email}}</p>
+</header>
+{{- end}}
+{{- range .Sections}}
+<section class="{{.Kind}}">
+<h2>{{$.Theme.Heading .Title}}</h2>
+{{- if .Text}}
+<p>{{.Text}}</p>
+{{- end}}
+{{- range .Entries}}
+<div class="entry"><h3>{{.Title}}</h3><span class="period">{{.Period}}</span></div>
+<div>{{.Subtitle}}</div>
+{{- end}}
+{{- if .Items}}
+<p>{{join .Items ", "}}</p>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+type HTMLRenderer struct {
+	engine *Engine
+}
+
+func NewHTMLRenderer(engine *Engine) *HTMLRenderer {
+	return &HTMLRenderer{engine: engine}
+}
+
+func (r HTMLRenderer) Format() string {
+	return "html"
+}
+
+func (r HTMLRenderer) ContentType() string {
+	return "text/html; charset=utf-8"
+}
+
+func (r HTMLRenderer) Render(resume domain.Resume) ([]byte, error) {
+	view, err := r.engine.Prepare(resume)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, view); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,59 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+)
+
+var markdownTemplate = template.Must(template.New("resume").Funcs(template.FuncMap{
+	"join": strings.Join,
+}).Parse(`{{if .Name}}# {{.Name}}
+
+{{.Email}}
+{{end}}
+{{- range .Sections}}
+## {{$.Theme.Heading .Title}}
+{{if .Text}}
+{{.Text}}
+{{end}}
+{{- range .Entries}}
+**{{.Title}}**, {{.Subtitle}}  
+_{{.Period}}_
+{{end}}
+{{- if .Items}}
+{{join .Items ", "}}
+{{end}}
+{{- end}}`))
+
+type MarkdownRenderer struct {
+	engine *Engine
+}
+
+func NewMarkdownRenderer(engine *Engine) *MarkdownRenderer {
+	return &MarkdownRenderer{engine: engine}
+}
+
+func (r MarkdownRenderer) Format() string {
+	return "md"
+}
+
+func (r MarkdownRenderer) ContentType() string {
+	return "text/markdown; charset=utf-8"
+}
+
+func (r MarkdownRenderer) Render(resume domain.Resume) ([]byte, error) {
+	view, err := r.engine.Prepare(resume)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := markdownTemplate.Execute(&buf, view); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,180 @@
+// Package render turns resumes into downloadable documents using registered themes
+package render
+
+import (
+	"strings"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/render/pdf"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+)
+
+const (
+	margin       = 56.0
+	contentWidth = pdf.PageWidth - margin*2
+)
+
+type PDFRenderer struct {
+	engine *Engine
+}
+
+func NewPDFRenderer(engine *Engine) *PDFRenderer {
+	return &PDFRenderer{engine: engine}
+}
+
+func (r PDFRenderer) Format() string {
+	return "pdf"
+}
+
+func (r PDFRenderer) ContentType() string {
+	return "application/pdf"
+}
+
+func (r PDFRenderer) Render(resume domain.Resume) ([]byte, error) {
+	view, err := r.engine.Prepare(resume)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := newPdfLayout(view.Theme)
+	if view.Name != "" {
+		layout.heading(view.Name, view.Email)
+	}
+
+	for _, section := range view.Sections {
+		layout.section(section.Title)
+
+		switch section.Kind {
+		case SectionSummary:
+			layout.paragraph(section.Text)
+		case SectionSkills:
+			layout.paragraph(strings.Join(section.Items, ", "))
+		default:
+			for _, entry := range section.Entries {
+				layout.entry(entry)
+			}
+		}
+	}
+
+	return layout.doc.Bytes()
+}
+
+// pdfLayout keeps track of the vertical position on the page and
+// starts a new page whenever the content would overflow the bottom margin
+type pdfLayout struct {
+	doc   *pdf.Document
+	theme Theme
+	y     float64
+}
+
+func newPdfLayout(theme Theme) *pdfLayout {
+	doc := pdf.New()
+	doc.AddPage()
+	return &pdfLayout{doc: doc, theme: theme, y: margin}
+}
+
+func (l *pdfLayout) ensureSpace(height float64) {
+	if l.y+height > pdf.PageHeight-margin {
+		l.doc.AddPage()
+		l.y = margin
+	}
+}
+
+// headerX returns where a header line starts, based on the header alignment of the theme
+func (l *pdfLayout) headerX(font pdf.Font, size float64, text string) float64 {
+	if l.theme.Layout == LayoutCentered {
+		return (pdf.PageWidth - pdf.TextWidth(font, size, text)) / 2
+	}
+	return margin
+}
+
+func (l *pdfLayout) heading(name string, email string) {
+	styles := l.theme.Styles
+
+	l.y += styles.NameSize
+	l.doc.SetFont(pdf.HelveticaBold, styles.NameSize)
+	l.doc.SetTextColor(styles.TextColor)
+	l.doc.Text(l.headerX(pdf.HelveticaBold, styles.NameSize, name), l.y, name)
+
+	l.y += 16
+	l.doc.SetFont(pdf.Helvetica, 10)
+	l.doc.SetTextColor(styles.MutedColor)
+	l.doc.Text(l.headerX(pdf.Helvetica, 10, email), l.y, email)
+	l.y += 8
+}
+
+func (l *pdfLayout) section(title string) {
+	styles := l.theme.Styles
+	title = l.theme.Heading(title)
+
+	l.ensureSpace(40)
+	l.y += styles.SectionSize * 2
+	l.doc.SetFont(pdf.HelveticaBold, styles.SectionSize)
+	l.doc.SetTextColor(styles.AccentColor)
+	l.doc.Text(margin, l.y, title)
+
+	l.y += 6
+	if styles.SectionRules {
+		l.doc.SetDrawColor(styles.RuleColor)
+		l.doc.Line(margin, l.y, margin+contentWidth, l.y, 0.75)
+	}
+	l.y += 4
+}
+
+func (l *pdfLayout) paragraph(text string) {
+	styles := l.theme.Styles
+	lineHeight := styles.BodySize * 1.45
+
+	l.doc.SetFont(pdf.Helvetica, styles.BodySize)
+	l.doc.SetTextColor(styles.TextColor)
+	for _, line := range pdf.WrapText(pdf.Helvetica, styles.BodySize, text, contentWidth) {
+		l.ensureSpace(lineHeight)
+		l.y += lineHeight
+		l.doc.Text(margin, l.y, line)
+	}
+}
+
+// entryGap separates the title of an entry from its period on the right
+const entryGap = 12.0
+
+func (l *pdfLayout) entry(entry EntryView) {
+	styles := l.theme.Styles
+	titleSize := styles.BodySize + 0.5
+	periodSize := styles.BodySize - 0.5
+	lineHeight := styles.BodySize * 1.45
+
+	// The title wraps before the period, which stays on the right of its first line
+	periodWidth := pdf.TextWidth(pdf.Helvetica, periodSize, entry.Period)
+	titles := pdf.WrapText(pdf.HelveticaBold, titleSize, entry.Title, contentWidth-periodWidth-entryGap)
+	subtitles := pdf.WrapText(pdf.Helvetica, styles.BodySize, entry.Subtitle, contentWidth)
+	if len(titles) == 0 {
+		titles = []string{""}
+	}
+	l.ensureSpace(18 + lineHeight*float64(len(titles)-1) + 14)
+
+	l.y += 18
+	l.doc.SetFont(pdf.Helvetica, periodSize)
+	l.doc.SetTextColor(styles.MutedColor)
+	l.doc.Text(margin+contentWidth-periodWidth, l.y, entry.Period)
+
+	l.doc.SetFont(pdf.HelveticaBold, titleSize)
+	l.doc.SetTextColor(styles.TextColor)
+	for i, line := range titles {
+		if i > 0 {
+			l.ensureSpace(lineHeight)
+			l.y += lineHeight
+		}
+		l.doc.Text(margin, l.y, line)
+	}
+
+	l.doc.SetFont(pdf.Helvetica, styles.BodySize)
+	l.doc.SetTextColor(styles.MutedColor)
+	for i, line := range subtitles {
+		step := lineHeight
+		if i == 0 {
+			step = 14
+		}
+		l.ensureSpace(step)
+		l.y += step
+		l.doc.Text(margin, l.y, line)
+	}
+}
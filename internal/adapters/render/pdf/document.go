@@ -0,0 +1,200 @@
+// Package pdf is a small, dependency free PDF writer. It only supports what is
+// needed to lay out text based documents: pages, the standard Helvetica fonts,
+// coloured text and straight lines.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page dimensions in points
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+type Color struct {
+	R, G, B uint8
+}
+
+var Black = Color{0, 0, 0}
+
+type page struct {
+	content bytes.Buffer
+}
+
+// Document holds the pages of a PDF file while it is being drawn.
+// Coordinates are in points, measured from the top left corner of the page.
+type Document struct {
+	pages     []*page
+	font      Font
+	fontSize  float64
+	textColor Color
+	drawColor Color
+}
+
+func New() *Document {
+	return &Document{
+		font:      Helvetica,
+		fontSize:  12,
+		textColor: Black,
+		drawColor: Black,
+	}
+}
+
+// AddPage starts a new page, all subsequent drawing happens on it
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &page{})
+}
+
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+func (d *Document) SetFont(font Font, size float64) {
+	d.font = font
+	d.fontSize = size
+}
+
+func (d *Document) SetTextColor(color Color) {
+	d.textColor = color
+}
+
+func (d *Document) SetDrawColor(color Color) {
+	d.drawColor = color
+}
+
+// Text draws a single line of text with its baseline at y
+func (d *Document) Text(x, y float64, text string) {
+	p := d.currentPage()
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %s rg %.2f %.2f Td (%s) Tj ET\n",
+		fontResource(d.font), d.fontSize, colorOperands(d.textColor), x, PageHeight-y, escape(text),
+	)
+}
+
+// Line draws a straight line between two points
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	p := d.currentPage()
+	fmt.Fprintf(&p.content, "%s RG %.2f w %.2f %.2f m %.2f %.2f l S\n",
+		colorOperands(d.drawColor), width, x1, PageHeight-y1, x2, PageHeight-y2,
+	)
+}
+
+// WriteTo serialises the document, returning the number of bytes written
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Objects 1-4 are the catalog, page tree and fonts. Every page then takes
+	// two objects: the page dictionary followed by its content stream.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object(fontObject(Helvetica))
+	object(fontObject(HelveticaBold))
+
+	for i, p := range d.pages {
+		object(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, 6+i*2,
+		))
+
+		stream, err := compress(p.content.Bytes())
+		if err != nil {
+			return 0, err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", len(stream), stream))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// Bytes returns the serialised document
+func (d *Document) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (d *Document) currentPage() *page {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+	return d.pages[len(d.pages)-1]
+}
+
+func fontResource(font Font) string {
+	if font == HelveticaBold {
+		return "F2"
+	}
+	return "F1"
+}
+
+func fontObject(font Font) string {
+	return fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", font)
+}
+
+func colorOperands(color Color) string {
+	return fmt.Sprintf("%.3f %.3f %.3f", float64(color.R)/255, float64(color.G)/255, float64(color.B)/255)
+}
+
+// escape converts the text to WinAnsi bytes and escapes the characters
+// that have a special meaning inside a PDF string literal
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 32 && r <= 126:
+			b.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+func compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := zlib.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,102 @@
+package pdf
+
+import "strings"
+
+// Font is one of the standard Type 1 fonts every PDF reader ships with,
+// which keeps documents small since nothing needs to be embedded.
+type Font string
+
+const (
+	Helvetica     Font = "Helvetica"
+	HelveticaBold Font = "Helvetica-Bold"
+)
+
+// defaultWidth is used for glyphs outside of the printable ASCII range
+const defaultWidth = 556
+
+// Glyph widths for the printable ASCII range (32-126) in 1/1000 of the font size,
+// taken from the Adobe font metrics of each font.
+var widths = map[Font][95]int{
+	Helvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	},
+	HelveticaBold: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	},
+}
+
+// TextWidth returns the width, in points, of the text when drawn with the given font and size
+func TextWidth(font Font, size float64, text string) float64 {
+	table := widths[font]
+	total := 0
+	for _, r := range text {
+		if r >= 32 && r <= 126 {
+			total += table[r-32]
+		} else {
+			total += defaultWidth
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// WrapText splits the text into lines that fit within maxWidth, breaking on whitespace.
+// Words that are longer than a full line, e.g. links, are broken between characters.
+func WrapText(font Font, size float64, text string, maxWidth float64) []string {
+	var lines []string
+	var current string
+
+	for _, word := range strings.Fields(text) {
+		for TextWidth(font, size, word) > maxWidth {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+
+			head, rest := splitWord(font, size, word, maxWidth)
+			lines = append(lines, head)
+			word = rest
+		}
+		if word == "" {
+			continue
+		}
+
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+
+		if current != "" && TextWidth(font, size, candidate) > maxWidth {
+			lines = append(lines, current)
+			current = word
+			continue
+		}
+		current = candidate
+	}
+
+	if current != "" {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// splitWord cuts the longest start of word that fits within maxWidth, keeping at least one
+// character so a line that can not hold any still makes progress
+func splitWord(font Font, size float64, word string, maxWidth float64) (string, string) {
+	runes := []rune(word)
+	end := 1
+	for end < len(runes) && TextWidth(font, size, string(runes[:end+1])) <= maxWidth {
+		end++
+	}
+	return string(runes[:end]), string(runes[end:])
+}
@@ -0,0 +1,68 @@
+package pdf_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/render/pdf"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextWidth(t *testing.T) {
+	assert.Equal(t, 0.0, pdf.TextWidth(pdf.Helvetica, 10, ""))
+	// "A" is 667/1000 of the font size in Helvetica and 722/1000 in Helvetica-Bold
+	assert.InDelta(t, 6.67, pdf.TextWidth(pdf.Helvetica, 10, "A"), 0.001)
+	assert.InDelta(t, 7.22, pdf.TextWidth(pdf.HelveticaBold, 10, "A"), 0.001)
+	assert.InDelta(t, 5.56, pdf.TextWidth(pdf.Helvetica, 10, "é"), 0.001)
+}
+
+func TestWrapText(t *testing.T) {
+	text := "Designed and built the platform that schedules deliveries for every warehouse in the region"
+	lines := pdf.WrapText(pdf.Helvetica, 10, text, 150)
+
+	assert.Greater(t, len(lines), 1)
+	assert.Equal(t, text, strings.Join(lines, " "), "no word is lost or split")
+	for _, line := range lines {
+		assert.LessOrEqual(t, pdf.TextWidth(pdf.Helvetica, 10, line), 150.0, line)
+	}
+
+	assert.Empty(t, pdf.WrapText(pdf.Helvetica, 10, "   ", 150))
+	assert.Equal(t, []string{"one two"}, pdf.WrapText(pdf.Helvetica, 10, " one\ttwo ", 150))
+}
+
+func TestWrapTextBreaksLongWords(t *testing.T) {
+	word := "https://example.com/" + strings.Repeat("a", 80)
+	lines := pdf.WrapText(pdf.Helvetica, 10, "see "+word+" now", 100)
+
+	assert.Equal(t, "see", lines[0])
+	assert.Greater(t, len(lines), 3)
+	assert.Equal(t, "see"+word+"now", strings.ReplaceAll(strings.Join(lines, ""), " ", ""))
+	for _, line := range lines {
+		assert.LessOrEqual(t, pdf.TextWidth(pdf.Helvetica, 10, line), 100.0, line)
+	}
+
+	// A line too narrow for a single character still makes progress
+	assert.Equal(t, []string{"a", "b"}, pdf.WrapText(pdf.Helvetica, 10, "ab", 1))
+}
+
+func TestDocumentBytes(t *testing.T) {
+	doc := pdf.New()
+	assert.Equal(t, 0, doc.PageCount())
+
+	doc.Text(56, 56, "Hello (world)")
+	doc.AddPage()
+	doc.Line(56, 60, 500, 60, 1)
+	assert.Equal(t, 2, doc.PageCount())
+
+	data, err := doc.Bytes()
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(data, []byte("%%EOF\n")))
+	assert.Contains(t, string(data), "/Count 2")
+	assert.Contains(t, string(data), "xref\n0 9\n")
+
+	empty, err := pdf.New().Bytes()
+	assert.NoError(t, err)
+	assert.Contains(t, string(empty), "/Count 1", "a document always has a page")
+}
@@ -0,0 +1,117 @@
+package render_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/render"
+	"github.com/stivo-m/vise-resume/internal/adapters/render/pdf"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	contentStream = regexp.MustCompile(`(?s)/FlateDecode >>\nstream\n(.*?)\nendstream`)
+	textOperator  = regexp.MustCompile(`BT /(F\d) ([\d.]+) Tf [\d. ]+ rg ([\d.]+) ([\d.]+) Td \((.*)\) Tj ET`)
+)
+
+// drawnText is a line of text drawn on a page of a rendered PDF
+type drawnText struct {
+	page  int
+	font  pdf.Font
+	size  float64
+	x     float64
+	y     float64
+	value string
+}
+
+// drawnTexts inflates the page contents of a rendered PDF and lists the text drawn on them
+func drawnTexts(t *testing.T, document []byte) []drawnText {
+	var texts []drawnText
+	for page, match := range contentStream.FindAllSubmatch(document, -1) {
+		reader, err := zlib.NewReader(bytes.NewReader(match[1]))
+		assert.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+
+		for _, operator := range textOperator.FindAllStringSubmatch(string(content), -1) {
+			font := pdf.Helvetica
+			if operator[1] == "F2" {
+				font = pdf.HelveticaBold
+			}
+			size, _ := strconv.ParseFloat(operator[2], 64)
+			x, _ := strconv.ParseFloat(operator[3], 64)
+			y, _ := strconv.ParseFloat(operator[4], 64)
+			texts = append(texts, drawnText{page: page, font: font, size: size, x: x, y: pdf.PageHeight - y, value: operator[5]})
+		}
+	}
+
+	return texts
+}
+
+func TestPDFWrapsLongEntryTitles(t *testing.T) {
+	engine, err := render.NewEngine(render.DefaultThemes()...)
+	assert.NoError(t, err)
+
+	resume := testResume("classic")
+	title := "Senior Principal Staff Software Engineer and Technical Lead for Payments, Billing and Subscription Platforms"
+	resume.Experiences[0].Role = title
+	document, err := render.NewPDFRenderer(engine).Render(resume)
+	assert.NoError(t, err)
+
+	var titleLines []drawnText
+	for _, text := range drawnTexts(t, document) {
+		assert.LessOrEqual(t, text.x+pdf.TextWidth(text.font, text.size, text.value), pdf.PageWidth-56+0.01, text.value)
+		if text.font == pdf.HelveticaBold && strings.Contains(title, text.value) {
+			titleLines = append(titleLines, text)
+		}
+	}
+
+	if assert.Greater(t, len(titleLines), 1, "the title is wrapped") {
+		var words []string
+		for i, line := range titleLines {
+			words = append(words, line.value)
+			if i > 0 {
+				assert.Greater(t, line.y, titleLines[i-1].y, "every line is drawn below the one before")
+			}
+		}
+		assert.Equal(t, title, strings.Join(words, " "))
+	}
+}
+
+func TestPDFStartsNewPagesWhenFull(t *testing.T) {
+	engine, err := render.NewEngine(render.DefaultThemes()...)
+	assert.NoError(t, err)
+
+	resume := testResume("classic")
+	resume.Experiences = nil
+	for i := 0; i < 40; i++ {
+		resume.Experiences = append(resume.Experiences, domain.WorkExperience{
+			CompanyName: "Company " + strconv.Itoa(i),
+			Role:        "Engineer",
+			StartDate:   time.Date(2000+i%20, 1, 1, 0, 0, 0, 0, time.UTC),
+		})
+	}
+
+	document, err := render.NewPDFRenderer(engine).Render(resume)
+	assert.NoError(t, err)
+	assert.Contains(t, string(document), "/Count 3")
+
+	pages := map[int]bool{}
+	for _, text := range drawnTexts(t, document) {
+		pages[text.page] = true
+		assert.LessOrEqual(t, text.y, pdf.PageHeight-56, "%q is drawn in the bottom margin", text.value)
+		assert.GreaterOrEqual(t, text.y, 56.0, "%q is drawn in the top margin", text.value)
+	}
+	assert.Len(t, pages, 3)
+
+	short, err := render.NewPDFRenderer(engine).Render(testResume("classic"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(short), "/Count 1")
+}
@@ -0,0 +1,142 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/render/pdf"
+)
+
+type Section string
+
+const (
+	SectionSummary    Section = "summary"
+	SectionExperience Section = "experience"
+	SectionEducation  Section = "education"
+	SectionSkills     Section = "skills"
+)
+
+var sectionTitles = map[Section]string{
+	SectionSummary:    "Summary",
+	SectionExperience: "Experience",
+	SectionEducation:  "Education",
+	SectionSkills:     "Skills",
+}
+
+// Layout controls how the header of the document is positioned
+type Layout string
+
+const (
+	LayoutClassic  Layout = "classic"
+	LayoutCentered Layout = "centered"
+)
+
+type Styles struct {
+	BodySize          float64
+	NameSize          float64
+	SectionSize       float64
+	TextColor         pdf.Color
+	MutedColor        pdf.Color
+	AccentColor       pdf.Color
+	RuleColor         pdf.Color
+	UppercaseSections bool
+	SectionRules      bool
+}
+
+// Theme describes how a resume looks once rendered. Every exporter
+// resolves the theme of a resume through the [Engine] before rendering.
+type Theme struct {
+	ID          string
+	Name        string
+	Description string
+	Layout      Layout
+	Styles      Styles
+	Sections    []Section
+}
+
+func (t Theme) validate() error {
+	if t.ID == "" {
+		return fmt.Errorf("theme id is required")
+	}
+
+	if len(t.Sections) == 0 {
+		return fmt.Errorf("theme %s does not render any section", t.ID)
+	}
+
+	seen := make(map[Section]bool)
+	for _, section := range t.Sections {
+		if _, ok := sectionTitles[section]; !ok {
+			return fmt.Errorf("theme %s uses an unknown section %q", t.ID, section)
+		}
+		if seen[section] {
+			return fmt.Errorf("theme %s renders section %q more than once", t.ID, section)
+		}
+		seen[section] = true
+	}
+
+	return nil
+}
+
+// Heading formats a section title the way the theme displays it
+func (t Theme) Heading(title string) string {
+	if t.Styles.UppercaseSections {
+		return strings.ToUpper(title)
+	}
+	return title
+}
+
+// DefaultThemes are the themes every engine starts with
+func DefaultThemes() []Theme {
+	return []Theme{
+		{
+			ID:          "classic",
+			Name:        "Classic",
+			Description: "A traditional single column resume with ruled section headings",
+			Layout:      LayoutClassic,
+			Styles: Styles{
+				BodySize:          10.5,
+				NameSize:          22,
+				SectionSize:       13,
+				TextColor:         pdf.Black,
+				MutedColor:        pdf.Color{R: 110, G: 110, B: 110},
+				AccentColor:       pdf.Color{R: 31, G: 78, B: 121},
+				RuleColor:         pdf.Color{R: 200, G: 200, B: 200},
+				UppercaseSections: true,
+				SectionRules:      true,
+			},
+			Sections: []Section{SectionSummary, SectionExperience, SectionEducation, SectionSkills},
+		},
+		{
+			ID:          "modern",
+			Name:        "Modern",
+			Description: "A centered header with skills brought forward for quick scanning",
+			Layout:      LayoutCentered,
+			Styles: Styles{
+				BodySize:    10.5,
+				NameSize:    24,
+				SectionSize: 14,
+				TextColor:   pdf.Color{R: 33, G: 37, B: 41},
+				MutedColor:  pdf.Color{R: 108, G: 117, B: 125},
+				AccentColor: pdf.Color{R: 13, G: 148, B: 136},
+				RuleColor:   pdf.Color{R: 13, G: 148, B: 136},
+			},
+			Sections: []Section{SectionSummary, SectionSkills, SectionExperience, SectionEducation},
+		},
+		{
+			ID:          "minimal",
+			Name:        "Minimal",
+			Description: "Black and white with no decoration, suited to applicant tracking systems",
+			Layout:      LayoutClassic,
+			Styles: Styles{
+				BodySize:    10,
+				NameSize:    18,
+				SectionSize: 12,
+				TextColor:   pdf.Black,
+				MutedColor:  pdf.Black,
+				AccentColor: pdf.Black,
+				RuleColor:   pdf.Black,
+			},
+			Sections: []Section{SectionExperience, SectionEducation, SectionSkills, SectionSummary},
+		},
+	}
+}
@@ -0,0 +1,294 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting of the application. It is loaded once at startup by [Load] and
+// handed to the parts that need it, instead of each of them reading the environment.
+type Config struct {
+	Server       ServerConfig       `yaml:"server"`
+	Database     DatabaseConfig     `yaml:"database"`
+	Token        TokenConfig        `yaml:"token"`
+	Password     PasswordConfig     `yaml:"password"`
+	Mail         MailConfig         `yaml:"mail"`
+	RateLimit    RateLimitConfig    `yaml:"rate_limit"`
+	Identity     IdentityConfig     `yaml:"identity"`
+	Verification VerificationConfig `yaml:"verification"`
+}
+
+type ServerConfig struct {
+	Port int    `yaml:"port" env:"SERVER_PORT"`
+	URL  string `yaml:"url" env:"SERVER_URL"`
+	// MagicLinkURL is where the login links sent by email point to, the callback route of URL
+	// when it is empty
+	MagicLinkURL string `yaml:"magic_link_url" env:"MAGIC_LINK_URL"`
+}
+
+type DatabaseConfig struct {
+	Host     string `yaml:"host" env:"DB_HOST"`
+	Port     int    `yaml:"port" env:"DB_PORT"`
+	User     string `yaml:"user" env:"DB_USER"`
+	Password string `yaml:"password" env:"DB_PASSWORD"`
+	Name     string `yaml:"name" env:"DB_NAME"`
+}
+
+// TokenConfig selects the keys access tokens are signed with: the PEM files in SigningKeysDir,
+// or SecretKey with HS256 without a directory. With both, SecretKey only verifies older tokens.
+type TokenConfig struct {
+	SigningKeysDir string `yaml:"signing_keys_dir" env:"TOKEN_SIGNING_KEYS_DIR"`
+	SigningKeyId   string `yaml:"signing_key_id" env:"TOKEN_SIGNING_KEY_ID"`
+	SecretKey      string `yaml:"secret_key" env:"TOKEN_SECRET_KEY"`
+	Issuer         string `yaml:"issuer" env:"TOKEN_ISSUER"`
+	Audience       string `yaml:"audience" env:"TOKEN_AUDIENCE"`
+}
+
+// PasswordConfig is the Argon2id cost of password hashes, zero keeping the default, and the
+// peppers as id:secret pairs, the last one hashing new passwords. Memory is in KiB.
+type PasswordConfig struct {
+	Argon2Memory      uint32   `yaml:"argon2_memory" env:"PASSWORD_ARGON2_MEMORY"`
+	Argon2Iterations  uint32   `yaml:"argon2_iterations" env:"PASSWORD_ARGON2_ITERATIONS"`
+	Argon2Parallelism uint8    `yaml:"argon2_parallelism" env:"PASSWORD_ARGON2_PARALLELISM"`
+	Peppers           []string `yaml:"peppers" env:"PASSWORD_PEPPERS"`
+}
+
+// MailConfig selects how emails are delivered: "smtp" sends them through SMTP, "file" writes
+// them to OutboxDir and "memory" keeps them in memory
+type MailConfig struct {
+	From      string     `yaml:"from" env:"MAIL_FROM"`
+	Driver    string     `yaml:"driver" env:"MAIL_DRIVER"`
+	OutboxDir string     `yaml:"outbox_dir" env:"MAIL_OUTBOX_DIR"`
+	SMTP      SMTPConfig `yaml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" env:"SMTP_PORT"`
+	Username string `yaml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+}
+
+// RateLimitConfig selects where the attempts counted by the rate limiter are kept, "database"
+// or "memory"
+type RateLimitConfig struct {
+	Store string `yaml:"store" env:"RATE_LIMIT_STORE"`
+}
+
+// VerificationConfig is how long the codes sent by email stay valid, as durations like 15m or
+// 24h, zero keeping the default
+type VerificationConfig struct {
+	EmailVerificationTTL time.Duration `yaml:"email_verification_ttl" env:"VERIFICATION_EMAIL_VERIFICATION_TTL"`
+	PasswordResetTTL     time.Duration `yaml:"password_reset_ttl" env:"VERIFICATION_PASSWORD_RESET_TTL"`
+	EmailChangeTTL       time.Duration `yaml:"email_change_ttl" env:"VERIFICATION_EMAIL_CHANGE_TTL"`
+	MagicLinkTTL         time.Duration `yaml:"magic_link_ttl" env:"VERIFICATION_MAGIC_LINK_TTL"`
+	AccountDeletionTTL   time.Duration `yaml:"account_deletion_ttl" env:"VERIFICATION_ACCOUNT_DELETION_TTL"`
+}
+
+// IdentityConfig lists the identity providers users can log in with. In the environment they
+// are named in IDENTITY_PROVIDERS, see [IdentityProviderConfig].
+type IdentityConfig struct {
+	Providers []IdentityProviderConfig `yaml:"providers"`
+}
+
+// IdentityProviderConfig configures an OpenID Connect provider, or GitHub when it is named
+// github. In the environment its settings are IDENTITY_PROVIDER_<NAME>_CLIENT_ID,
+// _CLIENT_SECRET, _REDIRECT_URL, _ISSUER and _SCOPES, separated by spaces.
+type IdentityProviderConfig struct {
+	Name         string   `yaml:"name"`
+	Issuer       string   `yaml:"issuer"`
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"`
+	Scopes       []string `yaml:"scopes"`
+}
+
+// Default is the configuration used for the settings no source sets
+func Default() Config {
+	return Config{
+		Server:   ServerConfig{Port: 8080, URL: "http://localhost"},
+		Database: DatabaseConfig{Host: "localhost", Port: 5432, User: "postgres", Name: "postgres"},
+		Mail: MailConfig{
+			From:      "Vise Resume <no-reply@localhost>",
+			Driver:    "file",
+			OutboxDir: "outbox",
+			SMTP:      SMTPConfig{Port: 587},
+		},
+		RateLimit: RateLimitConfig{Store: "database"},
+	}
+}
+
+// Options are the sources a configuration is loaded from
+type Options struct {
+	// File is a YAML configuration file, which has to exist when set
+	File string
+	// EnvFile is a .env file of KEY=VALUE lines, skipped when it does not exist
+	EnvFile string
+	// Environ is the environment as KEY=VALUE pairs, os.Environ() when nil
+	Environ []string
+}
+
+// DefaultOptions reads the file named by CONFIG_FILE, or config.yaml when it exists, and the
+// .env file of the working directory
+func DefaultOptions() Options {
+	file := os.Getenv("CONFIG_FILE")
+	if file == "" {
+		if _, err := os.Stat("config.yaml"); err == nil {
+			file = "config.yaml"
+		}
+	}
+
+	return Options{File: file, EnvFile: ".env"}
+}
+
+// Load builds the configuration from the defaults, overridden by the YAML file, then by the
+// .env file and last by the environment, and validates it
+func Load(options Options) (*Config, error) {
+	config := Default()
+
+	if options.File != "" {
+		content, err := os.ReadFile(options.File)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read configuration file: %w", err)
+		}
+
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s: %w", options.File, err)
+		}
+	}
+
+	env := map[string]string{}
+	if options.EnvFile != "" {
+		values, err := readEnvFile(options.EnvFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		for key, value := range values {
+			env[key] = value
+		}
+	}
+
+	environ := options.Environ
+	if environ == nil {
+		environ = os.Environ()
+	}
+	for _, entry := range environ {
+		if key, value, ok := strings.Cut(entry, "="); ok {
+			env[key] = value
+		}
+	}
+
+	if problems := applyEnv(&config, env); len(problems) > 0 {
+		return nil, ValidationError(problems)
+	}
+	applyIdentityEnv(&config.Identity, env)
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// ValidationError lists everything wrong with a configuration, so it can all be fixed at once
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e, "\n  - ")
+}
+
+var providerName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,49}$`)
+
+// Validate checks the settings that can be checked without using them, e.g. that the server
+// has a port and the token service a key
+func (c Config) Validate() error {
+	var problems ValidationError
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(validPort(c.Server.Port), "server.port (SERVER_PORT) has to be between 1 and 65535")
+	check(c.Server.URL == "" || absoluteURL(c.Server.URL), "server.url (SERVER_URL) has to be an absolute URL")
+	check(c.Server.MagicLinkURL == "" || absoluteURL(c.Server.MagicLinkURL), "server.magic_link_url (MAGIC_LINK_URL) has to be an absolute URL")
+
+	check(c.Database.Host != "", "database.host (DB_HOST) has to be set")
+	check(validPort(c.Database.Port), "database.port (DB_PORT) has to be between 1 and 65535")
+	check(c.Database.User != "", "database.user (DB_USER) has to be set")
+	check(c.Database.Name != "", "database.name (DB_NAME) has to be set")
+
+	check(c.Token.SigningKeysDir != "" || c.Token.SecretKey != "",
+		"either token.signing_keys_dir (TOKEN_SIGNING_KEYS_DIR) or token.secret_key (TOKEN_SECRET_KEY) has to be set")
+
+	for _, pepper := range c.Password.Peppers {
+		id, secret, ok := strings.Cut(pepper, ":")
+		check(ok && id != "" && secret != "", "password.peppers (PASSWORD_PEPPERS) has to list id:secret pairs")
+	}
+
+	_, err := mail.ParseAddress(c.Mail.From)
+	check(err == nil, "mail.from (MAIL_FROM) has to be an email address")
+	switch c.Mail.Driver {
+	case "smtp":
+		check(c.Mail.SMTP.Host != "", "mail.smtp.host (SMTP_HOST) is required when the mail driver is smtp")
+		check(validPort(c.Mail.SMTP.Port), "mail.smtp.port (SMTP_PORT) has to be between 1 and 65535")
+	case "file":
+		check(c.Mail.OutboxDir != "", "mail.outbox_dir (MAIL_OUTBOX_DIR) is required when the mail driver is file")
+	case "memory":
+	default:
+		check(false, "mail.driver (MAIL_DRIVER) has to be smtp, file or memory, not %q", c.Mail.Driver)
+	}
+
+	check(c.RateLimit.Store == "database" || c.RateLimit.Store == "memory",
+		"rate_limit.store (RATE_LIMIT_STORE) has to be database or memory, not %q", c.RateLimit.Store)
+
+	check(c.Verification.EmailVerificationTTL >= 0, "verification.email_verification_ttl (VERIFICATION_EMAIL_VERIFICATION_TTL) cannot be negative")
+	check(c.Verification.PasswordResetTTL >= 0, "verification.password_reset_ttl (VERIFICATION_PASSWORD_RESET_TTL) cannot be negative")
+	check(c.Verification.EmailChangeTTL >= 0, "verification.email_change_ttl (VERIFICATION_EMAIL_CHANGE_TTL) cannot be negative")
+	check(c.Verification.MagicLinkTTL >= 0, "verification.magic_link_ttl (VERIFICATION_MAGIC_LINK_TTL) cannot be negative")
+	check(c.Verification.AccountDeletionTTL >= 0, "verification.account_deletion_ttl (VERIFICATION_ACCOUNT_DELETION_TTL) cannot be negative")
+
+	names := map[string]bool{}
+	for _, provider := range c.Identity.Providers {
+		if !providerName.MatchString(provider.Name) {
+			check(false, "identity provider name %q may only use lowercase letters, digits and dashes", provider.Name)
+			continue
+		}
+		check(!names[provider.Name], "identity provider %s is defined twice", provider.Name)
+		names[provider.Name] = true
+
+		prefix := identityEnvPrefix(provider.Name)
+		check(provider.ClientID != "", "the client_id (%sCLIENT_ID) of identity provider %s has to be set", prefix, provider.Name)
+		check(provider.ClientSecret != "", "the client_secret (%sCLIENT_SECRET) of identity provider %s has to be set", prefix, provider.Name)
+		check(absoluteURL(provider.RedirectURL), "the redirect_url (%sREDIRECT_URL) of identity provider %s has to be an absolute URL", prefix, provider.Name)
+		if provider.Name != "github" {
+			check(absoluteURL(provider.Issuer), "the issuer (%sISSUER) of identity provider %s has to be an absolute URL", prefix, provider.Name)
+		}
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
+
+func validPort(port int) bool {
+	return port > 0 && port <= 65535
+}
+
+func absoluteURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && parsed.Scheme != "" && parsed.Host != ""
+}
@@ -0,0 +1,169 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+server:
+  port: 9000
+  url: https://api.example.com
+database:
+  host: db.example.com
+  name: resumes
+token:
+  secret_key: from-file
+password:
+  peppers: ["a:first-pepper-secret"]
+verification:
+  password_reset_ttl: 30m
+  magic_link_ttl: 5m
+`)
+	envFile := writeFile(t, ".env", `
+# Comments and blank lines are skipped
+export DB_NAME=from-env-file
+TOKEN_SECRET_KEY="from env file" # quoted values keep their hashes #
+MAIL_FROM='Resumes <resumes@example.com>'
+PASSWORD_ARGON2_MEMORY=12288 # KiB
+`)
+
+	cfg, err := config.Load(config.Options{
+		File:    file,
+		EnvFile: envFile,
+		Environ: []string{"TOKEN_SECRET_KEY=from-environment", "SERVER_URL=", "VERIFICATION_MAGIC_LINK_TTL=20m", "PASSWORD_PEPPERS=a:first-pepper-secret, b:second-pepper-secret"},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, 9000, cfg.Server.Port)
+	assert.Equal(t, "https://api.example.com", cfg.Server.URL, "empty variables are skipped")
+	assert.Equal(t, "db.example.com", cfg.Database.Host)
+	assert.Equal(t, 5432, cfg.Database.Port, "defaults are kept")
+	assert.Equal(t, "from-env-file", cfg.Database.Name)
+	assert.Equal(t, "from-environment", cfg.Token.SecretKey)
+	assert.Equal(t, "Resumes <resumes@example.com>", cfg.Mail.From)
+	assert.Equal(t, uint32(12288), cfg.Password.Argon2Memory)
+	assert.Equal(t, []string{"a:first-pepper-secret", "b:second-pepper-secret"}, cfg.Password.Peppers)
+	assert.Equal(t, 30*time.Minute, cfg.Verification.PasswordResetTTL)
+	assert.Equal(t, 20*time.Minute, cfg.Verification.MagicLinkTTL)
+	assert.Zero(t, cfg.Verification.EmailVerificationTTL)
+}
+
+func TestLoadSkipsMissingEnvFile(t *testing.T) {
+	cfg, err := config.Load(config.Options{
+		EnvFile: filepath.Join(t.TempDir(), ".env"),
+		Environ: []string{"TOKEN_SECRET_KEY=secret"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, config.Default().Server, cfg.Server)
+
+	_, err = config.Load(config.Options{File: filepath.Join(t.TempDir(), "config.yaml")})
+	assert.Error(t, err, "a configuration file that was asked for has to exist")
+}
+
+func TestLoadReportsEveryProblem(t *testing.T) {
+	_, err := config.Load(config.Options{Environ: []string{"SERVER_PORT=http", "PASSWORD_ARGON2_PARALLELISM=300", "VERIFICATION_EMAIL_CHANGE_TTL=1 hour"}})
+	assert.ErrorContains(t, err, `SERVER_PORT has to be a number, not "http"`)
+	assert.ErrorContains(t, err, "PASSWORD_ARGON2_PARALLELISM has to be a number up to 255")
+	assert.ErrorContains(t, err, `VERIFICATION_EMAIL_CHANGE_TTL has to be a duration like 15m or 24h, not "1 hour"`)
+
+	_, err = config.Load(config.Options{Environ: []string{
+		"SERVER_PORT=70000",
+		"MAIL_DRIVER=smtp",
+		"RATE_LIMIT_STORE=redis",
+		"PASSWORD_PEPPERS=missing-secret",
+		"VERIFICATION_PASSWORD_RESET_TTL=-5m",
+	}})
+	var problems config.ValidationError
+	if assert.ErrorAs(t, err, &problems) {
+		assert.Equal(t, config.ValidationError{
+			"server.port (SERVER_PORT) has to be between 1 and 65535",
+			"either token.signing_keys_dir (TOKEN_SIGNING_KEYS_DIR) or token.secret_key (TOKEN_SECRET_KEY) has to be set",
+			"password.peppers (PASSWORD_PEPPERS) has to list id:secret pairs",
+			"mail.smtp.host (SMTP_HOST) is required when the mail driver is smtp",
+			`rate_limit.store (RATE_LIMIT_STORE) has to be database or memory, not "redis"`,
+			"verification.password_reset_ttl (VERIFICATION_PASSWORD_RESET_TTL) cannot be negative",
+		}, problems)
+	}
+
+	file := writeFile(t, "config.yaml", "server:\n  prot: 9000\n")
+	_, err = config.Load(config.Options{File: file, Environ: []string{}})
+	assert.ErrorContains(t, err, "field prot not found")
+
+	envFile := writeFile(t, ".env", "TOKEN_SECRET_KEY\n")
+	_, err = config.Load(config.Options{EnvFile: envFile, Environ: []string{}})
+	assert.ErrorContains(t, err, ".env:1: expected KEY=VALUE")
+}
+
+func TestLoadIdentityProviders(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+token:
+  secret_key: secret
+identity:
+  providers:
+    - name: company-sso
+      issuer: https://sso.example.com
+      client_id: client
+      redirect_url: https://api.example.com/callback
+      scopes: [openid, email]
+`)
+	environ := []string{
+		"IDENTITY_PROVIDER_COMPANY_SSO_CLIENT_SECRET=secret",
+		"IDENTITY_PROVIDER_COMPANY_SSO_SCOPES=openid profile",
+	}
+
+	cfg, err := config.Load(config.Options{File: file, Environ: environ})
+	assert.NoError(t, err)
+	if assert.Len(t, cfg.Identity.Providers, 1) {
+		assert.Equal(t, config.IdentityProviderConfig{
+			Name:         "company-sso",
+			Issuer:       "https://sso.example.com",
+			ClientID:     "client",
+			ClientSecret: "secret",
+			RedirectURL:  "https://api.example.com/callback",
+			Scopes:       []string{"openid", "profile"},
+		}, cfg.Identity.Providers[0])
+	}
+
+	environ = append(environ,
+		"IDENTITY_PROVIDERS=company-sso, github",
+		"IDENTITY_PROVIDER_GITHUB_CLIENT_ID=client",
+		"IDENTITY_PROVIDER_GITHUB_CLIENT_SECRET=secret",
+		"IDENTITY_PROVIDER_GITHUB_REDIRECT_URL=https://api.example.com/callback",
+	)
+	cfg, err = config.Load(config.Options{File: file, Environ: environ})
+	assert.NoError(t, err)
+	if assert.Len(t, cfg.Identity.Providers, 2) {
+		assert.Equal(t, "company-sso", cfg.Identity.Providers[0].Name)
+		assert.Equal(t, "github", cfg.Identity.Providers[1].Name)
+	}
+
+	cfg, err = config.Load(config.Options{Environ: []string{"TOKEN_SECRET_KEY=secret", "IDENTITY_PROVIDERS=", "IDENTITY_PROVIDER_GITHUB_CLIENT_ID=client"}})
+	assert.NoError(t, err)
+	assert.Empty(t, cfg.Identity.Providers)
+
+	cfg, err = config.Load(config.Options{File: file, Environ: []string{"IDENTITY_PROVIDERS=", "IDENTITY_PROVIDER_COMPANY_SSO_CLIENT_SECRET=secret"}})
+	assert.NoError(t, err)
+	assert.Len(t, cfg.Identity.Providers, 1, "an empty list keeps the providers of the file")
+
+	_, err = config.Load(config.Options{File: file, Environ: []string{}})
+	assert.ErrorContains(t, err, "IDENTITY_PROVIDER_COMPANY_SSO_CLIENT_SECRET")
+
+	_, err = config.Load(config.Options{File: file, Environ: []string{"IDENTITY_PROVIDERS=acme", "IDENTITY_PROVIDER_ACME_CLIENT_ID=client"}})
+	assert.ErrorContains(t, err, "IDENTITY_PROVIDER_ACME_ISSUER")
+
+	_, err = config.Load(config.Options{File: file, Environ: []string{"IDENTITY_PROVIDERS=Not Valid"}})
+	assert.ErrorContains(t, err, `identity provider name "Not Valid"`)
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// applyEnv sets the fields tagged with the name of a variable of env, recursing into nested
+// structs. Empty variables are skipped like unset ones.
+func applyEnv(config interface{}, env map[string]string) []string {
+	var problems []string
+	value := reflect.ValueOf(config).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		name, tagged := value.Type().Field(i).Tag.Lookup("env")
+		if !tagged {
+			if field.Kind() == reflect.Struct {
+				problems = append(problems, applyEnv(field.Addr().Interface(), env)...)
+			}
+			continue
+		}
+
+		raw := strings.TrimSpace(env[name])
+		if raw == "" {
+			continue
+		}
+
+		if field.Type() == reflect.TypeOf(time.Duration(0)) {
+			duration, err := time.ParseDuration(raw)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s has to be a duration like 15m or 24h, not %q", name, raw))
+				continue
+			}
+			field.SetInt(int64(duration))
+			continue
+		}
+
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(raw)
+		case reflect.Int:
+			number, err := strconv.ParseInt(raw, 10, 0)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s has to be a number, not %q", name, raw))
+				continue
+			}
+			field.SetInt(number)
+		case reflect.Uint8, reflect.Uint32:
+			bits := field.Type().Bits()
+			number, err := strconv.ParseUint(raw, 10, bits)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s has to be a number up to %d, not %q", name, uint64(1)<<bits-1, raw))
+				continue
+			}
+			field.SetUint(number)
+		case reflect.Slice:
+			// Lists are separated by commas
+			var items []string
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			field.Set(reflect.ValueOf(items))
+		default:
+			panic(fmt.Sprintf("config: unsupported type %s of %s", field.Type(), name))
+		}
+	}
+
+	return problems
+}
+
+// applyIdentityEnv replaces the providers with the ones named in IDENTITY_PROVIDERS, unless it
+// is empty, and overrides their settings with IDENTITY_PROVIDER_<NAME>_* so secrets can be
+// kept out of the configuration file
+func applyIdentityEnv(identity *IdentityConfig, env map[string]string) {
+	providers := identity.Providers
+	if raw := strings.TrimSpace(env["IDENTITY_PROVIDERS"]); raw != "" {
+		providers = nil
+		for _, name := range strings.Split(raw, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+
+			provider := IdentityProviderConfig{Name: name}
+			for _, existing := range identity.Providers {
+				if existing.Name == name {
+					provider = existing
+				}
+			}
+			providers = append(providers, provider)
+		}
+	}
+
+	for i := range providers {
+		prefix := identityEnvPrefix(providers[i].Name)
+		settings := map[string]*string{
+			"ISSUER":        &providers[i].Issuer,
+			"CLIENT_ID":     &providers[i].ClientID,
+			"CLIENT_SECRET": &providers[i].ClientSecret,
+			"REDIRECT_URL":  &providers[i].RedirectURL,
+		}
+		for key, setting := range settings {
+			if value := strings.TrimSpace(env[prefix+key]); value != "" {
+				*setting = value
+			}
+		}
+		if scopes := strings.Fields(env[prefix+"SCOPES"]); len(scopes) > 0 {
+			providers[i].Scopes = scopes
+		}
+	}
+
+	identity.Providers = providers
+}
+
+func identityEnvPrefix(name string) string {
+	return "IDENTITY_PROVIDER_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+}
+
+// readEnvFile reads the KEY=VALUE lines of a .env file. Blank lines and lines starting with #
+// are skipped, and values may be quoted.
+func readEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, number)
+		}
+
+		value = strings.TrimSpace(value)
+		if value != "" && (value[0] == '"' || value[0] == '\'') {
+			// Quoted values end at the closing quote, only a comment may follow it
+			end := strings.IndexByte(value[1:], value[0]) + 1
+			if end == 0 {
+				return nil, fmt.Errorf("%s:%d: unterminated quoted value", path, number)
+			}
+			if rest := strings.TrimSpace(value[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				return nil, fmt.Errorf("%s:%d: unexpected text after the quoted value", path, number)
+			}
+			value = value[1:end]
+		} else if comment := strings.Index(value, " #"); comment >= 0 {
+			value = strings.TrimSpace(value[:comment])
+		}
+		values[key] = value
+	}
+
+	return values, scanner.Err()
+}
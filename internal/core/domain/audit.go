@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Actions recorded in the audit log
+const (
+	AuditUserRegistered         = "user.registered"
+	AuditLogin                  = "auth.login"
+	AuditTwoFactorLogin         = "auth.login.two_factor"
+	AuditMagicLinkLogin         = "auth.login.magic_link"
+	AuditExternalLogin          = "auth.login.external"
+	AuditLogout                 = "auth.logout"
+	AuditLogoutAll              = "auth.logout_all"
+	AuditSessionRevoked         = "auth.session_revoked"
+	AuditTokenRejected          = "auth.token_rejected"
+	AuditEmailVerified          = "email.verified"
+	AuditEmailChanged           = "email.changed"
+	AuditPasswordResetRequested = "password.reset_requested"
+	AuditPasswordReset          = "password.reset"
+	AuditPasswordChanged        = "password.changed"
+	AuditTwoFactorEnabled       = "two_factor.enabled"
+	AuditTokenCreated           = "personal_access_token.created"
+	AuditTokenRevoked           = "personal_access_token.revoked"
+	AuditAccountDeleted         = "account.deletion_requested"
+	AuditAccountPurged          = "account.purged"
+	AuditUserVerified           = "admin.user_verified"
+	AuditUserDisabled           = "admin.user_disabled"
+	AuditUserEnabled            = "admin.user_enabled"
+	AuditPasswordResetForced    = "admin.password_reset_forced"
+	AuditRoleAssigned           = "admin.role_assigned"
+	AuditRoleRemoved            = "admin.role_removed"
+)
+
+// Outcomes of an audited action
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// AuditEvent is an entry of the append-only audit log of authentication and account events.
+// ActorId is the user who acted, when known. Every event keeps the hash of the event before
+// it, so changing or removing an event breaks the chain from there on.
+type AuditEvent struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement:false"`
+	CreatedAt    time.Time `gorm:"not null"`
+	ActorId      string    `gorm:"type:uuid;default:null;index"`
+	Action       string    `gorm:"size:50;not null;index"`
+	Outcome      string    `gorm:"size:20;not null"`
+	IpAddress    string    `gorm:"size:45;default:null"`
+	UserAgent    string    `gorm:"size:255;default:null"`
+	Details      string    `gorm:"size:255;default:null"`
+	PreviousHash string    `gorm:"size:64;not null"`
+	Hash         string    `gorm:"size:64;not null;uniqueIndex"`
+}
+
+// ComputeHash hashes the event along with the hash of the event before it
+func (e AuditEvent) ComputeHash() string {
+	// Encoding the fields as a JSON array keeps values containing separators from running together
+	fields, _ := json.Marshal([]interface{}{
+		e.ID,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		e.ActorId,
+		e.Action,
+		e.Outcome,
+		e.IpAddress,
+		e.UserAgent,
+		e.Details,
+		e.PreviousHash,
+	})
+
+	sum := sha256.Sum256(fields)
+	return hex.EncodeToString(sum[:])
+}
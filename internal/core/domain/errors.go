@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// RetryError is returned when an action is refused for now but can be tried again later
+type RetryError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *RetryError) Error() string {
+	return e.Message
+}
+
+// PasswordPolicyError is returned when a password does not meet the password policy
+type PasswordPolicyError struct {
+	Problems []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password is too weak: " + strings.Join(e.Problems, ", ")
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// ExternalIdentity links a user to the account they have with an identity provider such as
+// Google or GitHub. Subject is the provider's identifier of that account, which never changes
+// even when the email address does.
+type ExternalIdentity struct {
+	Base
+	UserId      string     `gorm:"type:uuid;not null;index;"`
+	Provider    string     `gorm:"size:50;not null;uniqueIndex:idx_external_identities_provider_subject"`
+	Subject     string     `gorm:"size:255;not null;uniqueIndex:idx_external_identities_provider_subject"`
+	Email       string     `gorm:"size:255;not null"`
+	LastLoginAt *time.Time `gorm:"default:null"`
+}
+
+// LoginState remembers a login started with an identity provider until the provider sends the
+// user back. Only hashes of the state and of the binding, the secret handed to the browser that
+// started the login, are stored; the code verifier and nonce are checked when the authorization
+// code is exchanged.
+type LoginState struct {
+	Base
+	StateHash    string    `gorm:"size:64;not null;uniqueIndex"`
+	BindingHash  string    `gorm:"size:64;not null;default:''"`
+	Provider     string    `gorm:"size:50;not null"`
+	CodeVerifier string    `gorm:"size:128;not null"`
+	Nonce        string    `gorm:"size:64;not null"`
+	ExpiresAt    time.Time `gorm:"not null"`
+}
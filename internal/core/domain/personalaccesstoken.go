@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Scopes a personal access token can be granted
+const (
+	ScopeResumeRead  = "resume:read"
+	ScopeResumeWrite = "resume:write"
+)
+
+// PersonalAccessToken is a long lived credential a user creates for scripts and integrations.
+// Only a hash of the token is stored, and it can only be used on routes that accept one of its scopes.
+type PersonalAccessToken struct {
+	Base
+	UserId     string     `gorm:"type:uuid;not null;index;"`
+	Name       string     `gorm:"size:100;not null"`
+	TokenHash  string     `gorm:"size:64;not null;uniqueIndex"`
+	Scopes     string     `gorm:"size:255;not null"`
+	ExpiresAt  time.Time  `gorm:"not null"`
+	LastUsedAt *time.Time `gorm:"default:null"`
+}
+
+// ScopeList returns the scopes of the token, which are stored separated by spaces
+func (t PersonalAccessToken) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
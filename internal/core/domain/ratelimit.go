@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// RateLimit tracks the failed attempts made with one key, e.g. an email address or a client IP
+type RateLimit struct {
+	Key           string     `gorm:"primaryKey;size:255"`
+	Failures      int        `gorm:"not null;default:0"`
+	LastFailureAt time.Time  `gorm:"not null"`
+	LockedUntil   *time.Time `gorm:"default:null"`
+}
@@ -12,8 +12,10 @@ type Resume struct {
 	Score       int            `gorm:"default:0"`
 	Summary     string         `gorm:"size:200;default:null;"`
 	Skills      pq.StringArray `gorm:"type:text[]"`
+	TemplateId  string         `gorm:"size:50;default:null;"`
 	Experiences []WorkExperience
-	Education   []WorkExperience
+	Education   []Education
+	User        *User
 }
 
 type WorkExperience struct {
@@ -30,6 +32,33 @@ type Education struct {
 	ResumeId   string `gorm:"type:uuid;not null;index;"`
 	SchoolName string `gorm:"size:255; not null"`
 	Course     string `gorm:"size:255; not null"`
+	StudyType  string `gorm:"size:100;default:null;"`
 	StartDate  time.Time
 	EndDate    *time.Time
 }
+
+func (r Resume) HasExperience(id string) bool {
+	return r.FindExperience(id) != nil
+}
+
+func (r Resume) HasEducation(id string) bool {
+	return r.FindEducation(id) != nil
+}
+
+func (r Resume) FindExperience(id string) *WorkExperience {
+	for i := range r.Experiences {
+		if r.Experiences[i].ID == id {
+			return &r.Experiences[i]
+		}
+	}
+	return nil
+}
+
+func (r Resume) FindEducation(id string) *Education {
+	for i := range r.Education {
+		if r.Education[i].ID == id {
+			return &r.Education[i]
+		}
+	}
+	return nil
+}
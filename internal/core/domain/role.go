@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// Permissions that can be granted to a role
+const (
+	PermissionUsersRead   = "users:read"
+	PermissionUsersManage = "users:manage"
+	PermissionResumesRead = "resumes:read"
+	PermissionRolesManage = "roles:manage"
+	PermissionAuditRead   = "audit:read"
+)
+
+// Role groups permissions that are granted to users together, e.g. to the support team
+type Role struct {
+	Base
+	Name        string           `gorm:"size:50;not null;uniqueIndex" json:"name"`
+	Description string           `gorm:"size:255" json:"description"`
+	Permissions []RolePermission `json:"-"`
+}
+
+type RolePermission struct {
+	RoleId     string `gorm:"type:uuid;primaryKey"`
+	Permission string `gorm:"size:50;primaryKey"`
+}
+
+type UserRole struct {
+	UserId    string `gorm:"type:uuid;primaryKey"`
+	RoleId    string `gorm:"type:uuid;primaryKey"`
+	CreatedAt time.Time
+}
+
+// PermissionList returns the names of the permissions granted by the role
+func (r Role) PermissionList() []string {
+	permissions := make([]string, 0, len(r.Permissions))
+	for _, permission := range r.Permissions {
+		permissions = append(permissions, permission.Permission)
+	}
+	return permissions
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// TwoFactor holds the TOTP secret of a user. It stays pending until the user
+// confirms it with a first code, which sets EnabledAt. LastUsedStep keeps a code
+// from being used twice and FailedAttempts counts wrong codes since the last good one.
+type TwoFactor struct {
+	Base
+	UserId         string     `gorm:"type:uuid;not null;uniqueIndex;"`
+	Secret         string     `gorm:"size:64;not null"`
+	EnabledAt      *time.Time `gorm:"default:null"`
+	LastUsedStep   int64      `gorm:"not null;default:0"`
+	FailedAttempts int        `gorm:"not null;default:0"`
+}
+
+// RecoveryCode is a one time code that replaces a TOTP code when the user has
+// lost their authenticator. Only a hash of the code, keyed with the TOTP secret, is stored.
+type RecoveryCode struct {
+	Base
+	UserId   string     `gorm:"type:uuid;not null;index;"`
+	CodeHash string     `gorm:"size:150;not null"`
+	UsedAt   *time.Time `gorm:"default:null"`
+}
@@ -4,12 +4,21 @@ import "time"
 
 type User struct {
 	Base
-	FullName        string          `gorm:"size:100;"  json:"full_name"`
-	Email           string          `gorm:"size:150;not null;unique" json:"email"`
-	EmailVerifiedAt *time.Time      `gorm:"default:null" json:"email_verified_at"`
-	Password        Password        `json:"-"`
-	Tokens          []Token         `json:"-"`
-	Verifications   []Verifications `json:"-"`
+	FullName        string     `gorm:"size:100;"  json:"full_name"`
+	Email           string     `gorm:"size:150;not null;unique" json:"email"`
+	EmailVerifiedAt *time.Time `gorm:"default:null" json:"email_verified_at"`
+	// DisabledAt is set when an administrator disables the account, which blocks every login
+	DisabledAt *time.Time `gorm:"default:null" json:"disabled_at"`
+	// PasswordResetRequired blocks logins until the user resets their password
+	PasswordResetRequired bool `gorm:"not null;default:false" json:"-"`
+	// DeletionRequestedAt is set when the user deletes their account, which is purged
+	// for good once the grace period has passed unless they log in again before that
+	DeletionRequestedAt *time.Time `gorm:"default:null" json:"deletion_requested_at"`
+	// PendingEmail is the address the user asked to switch to, until they confirm it
+	PendingEmail  string          `gorm:"size:150;default:null" json:"-"`
+	Password      Password        `json:"-"`
+	Tokens        []Token         `json:"-"`
+	Verifications []Verifications `json:"-"`
 }
 
 type Password struct {
@@ -18,15 +27,31 @@ type Password struct {
 	UserId string `gorm:"type:uuid;not null;index;"`
 }
 
+// Token is an access or refresh token issued to a user. Tokens issued by the same
+// login share a FamilyId, and refresh tokens are stored hashed and marked as used
+// once they have been exchanged for a new pair. Access tokens also record the
+// client that last used them, which is what users see as their sessions.
 type Token struct {
 	Base
-	AccessToken string `gorm:"type:varchar(500);not null" json:"access_token"`
-	UserId      string `gorm:"type:uuid;not null;index;"`
+	AccessToken string     `gorm:"type:text;not null" json:"access_token"`
+	UserId      string     `gorm:"type:uuid;not null;index;"`
+	Type        string     `gorm:"size:20;not null;default:access"`
+	FamilyId    string     `gorm:"type:uuid;default:null;index;"`
+	ExpiresAt   *time.Time `gorm:"default:null"`
+	UsedAt      *time.Time `gorm:"default:null"`
+	IpAddress   string     `gorm:"size:45;default:null"`
+	UserAgent   string     `gorm:"size:255;default:null"`
+	LastUsedAt  *time.Time `gorm:"default:null"`
 }
 
+// Verifications are one time codes sent to a user. A code stops working once it
+// expires or after too many wrong guesses. SentTo is the address the code went to.
 type Verifications struct {
 	Base
-	Type   string `gorm:"size:20;not null"`
-	UserId string `gorm:"type:uuid;not null;index;"`
-	Code   string `gorm:"size:20;not null;"`
+	Type      string     `gorm:"size:20;not null"`
+	UserId    string     `gorm:"type:uuid;not null;index;"`
+	Code      string     `gorm:"size:20;not null;"`
+	SentTo    string     `gorm:"size:150;not null;default:''"`
+	ExpiresAt *time.Time `gorm:"default:null"`
+	Attempts  int        `gorm:"not null;default:0"`
 }
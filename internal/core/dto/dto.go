@@ -18,6 +18,7 @@ type EmailDto struct {
 }
 
 type ResetPasswordDto struct {
+	Email    string `json:"email" validate:"required,email"`
 	Code     string `json:"code"  validate:"required,min=6,max=6"`
 	Password string `json:"password"  validate:"required,min=5,max=255"`
 }
@@ -31,12 +32,232 @@ type FindUserDto struct {
 type ManageTokenDto struct {
 	ID          string `json:"id"`
 	AccessToken string `json:"access_token"`
+	Type        string
+	FamilyId    string
+	ExpiresAt   *time.Time
+	// WithRevoked also lists tokens that were revoked, for the account export
+	WithRevoked bool
+}
+
+type RefreshTokenDto struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type SessionDto struct {
+	ID         string     `json:"id"`
+	IpAddress  string     `json:"ip_address"`
+	UserAgent  string     `json:"user_agent"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	Current    bool       `json:"current"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+type TwoFactorCodeDto struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// TwoFactorVerifyDto answers a login challenge with either a TOTP code or a recovery code
+type TwoFactorVerifyDto struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required,min=6,max=32"`
+}
+
+// MagicLinkCallbackDto carries the token of a login link
+type MagicLinkCallbackDto struct {
+	Token string `query:"token" json:"token" validate:"required"`
+	// Binding is the secret given to the client that asked for the link
+	Binding string `query:"-" json:"-"`
+}
+
+// LinkTokenDto is what a signed link sent by email vouches for
+type LinkTokenDto struct {
+	Purpose string
+	UserID  string
+	// Code is the verification code stored for the link, which makes the link single use
+	Code string
+	// Binding is a hash of the secret of the client that asked for the link
+	Binding   string
+	ExpiresAt time.Time
+}
+
+// ExternalLoginCallbackDto is what an identity provider sends the user back with after they signed in there
+type ExternalLoginCallbackDto struct {
+	Code             string `query:"code" json:"code" validate:"required"`
+	State            string `query:"state" json:"state" validate:"required"`
+	Error            string `query:"error" json:"error"`
+	ErrorDescription string `query:"error_description" json:"error_description"`
+	// Binding is the secret given to the browser that started the login
+	Binding string `query:"-" json:"-"`
+}
+
+// ExternalIdentityDto is the account an identity provider vouches for once a user signed in with it
+type ExternalIdentityDto struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	FullName      string
+}
+
+type CreatePersonalAccessTokenDto struct {
+	Name          string   `json:"name" validate:"required,min=1,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,oneof=resume:read resume:write"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"required,min=1,max=365"`
+}
+
+// PersonalAccessTokenDto describes a personal access token. Token is only set
+// in the response that creates it, as it can not be recovered afterwards.
+type PersonalAccessTokenDto struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	Token      string     `json:"token,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+type LogoutAllDto struct {
+	KeepCurrent bool `json:"keep_current"`
+}
+
+// SearchUsersDto filters and paginates the users listed by administrators
+type SearchUsersDto struct {
+	Search string `query:"search"`
+	Page   int    `query:"page"`
+	Limit  int    `query:"limit"`
+}
+
+type AdminUserDto struct {
+	ID                    string     `json:"id"`
+	FullName              string     `json:"full_name"`
+	Email                 string     `json:"email"`
+	EmailVerifiedAt       *time.Time `json:"email_verified_at"`
+	DisabledAt            *time.Time `json:"disabled_at"`
+	PasswordResetRequired bool       `json:"password_reset_required"`
+	Roles                 []string   `json:"roles,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+type UserListDto struct {
+	Users []AdminUserDto `json:"users"`
+	Total int64          `json:"total"`
+	Page  int            `json:"page"`
+	Limit int            `json:"limit"`
+}
+
+type RoleDto struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type AssignRoleDto struct {
+	Role string `json:"role" validate:"required,max=50"`
+}
+
+// ClientDto describes the client making a request, for the events recorded in the audit log
+type ClientDto struct {
+	IpAddress string
+	UserAgent string
+}
+
+// AuditQueryDto filters and paginates the events of the audit log, newest first.
+// Since and Until are RFC 3339 timestamps.
+type AuditQueryDto struct {
+	ActorId string `query:"actor_id" validate:"omitempty,uuid"`
+	Action  string `query:"action" validate:"omitempty,max=50"`
+	Outcome string `query:"outcome" validate:"omitempty,oneof=success failure"`
+	Since   string `query:"since" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Until   string `query:"until" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Page    int    `query:"page"`
+	Limit   int    `query:"limit"`
+}
+
+type AuditEventDto struct {
+	ID        int64     `json:"id"`
+	ActorId   string    `json:"actor_id,omitempty"`
+	Action    string    `json:"action"`
+	Outcome   string    `json:"outcome"`
+	IpAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	Details   string    `json:"details,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AuditEventListDto struct {
+	Events []AuditEventDto `json:"events"`
+	Total  int64           `json:"total"`
+	Page   int             `json:"page"`
+	Limit  int             `json:"limit"`
+}
+
+// AuditVerificationDto reports whether the hash chain of the audit log is intact, and otherwise
+// the first event that does not match the one before it
+type AuditVerificationDto struct {
+	Valid    bool   `json:"valid"`
+	Events   int64  `json:"events"`
+	BrokenAt *int64 `json:"broken_at,omitempty"`
+	Reason   string `json:"reason,omitempty"`
 }
 
 type UpdateUserDto struct {
 	FullName string `json:"full_name"`
 }
 
+// ChangePasswordDto changes the password of a logged in user, optionally signing their other sessions out
+type ChangePasswordDto struct {
+	CurrentPassword     string `json:"current_password" validate:"required"`
+	Password            string `json:"password" validate:"required,min=5,max=100"`
+	LogoutOtherSessions bool   `json:"logout_other_sessions"`
+}
+
+// ChangeEmailDto asks for the email address of an account to be changed, confirming it with the password
+type ChangeEmailDto struct {
+	Email    string `json:"email" validate:"required,email,max=150"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ConfirmEmailChangeDto struct {
+	Code string `json:"code" validate:"required,min=6,max=6"`
+}
+
+// DeleteAccountDto confirms the deletion of an account with its password, or with a code sent to
+// its email address when the user never chose a password, like after logging in with an identity
+// provider
+type DeleteAccountDto struct {
+	Password string `json:"password" validate:"required_without=Code"`
+	Code     string `json:"code" validate:"required_without=Password,omitempty,len=6"`
+}
+
+type AccountDeletionDto struct {
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// AccountProfileExportDto is the profile written to the account export
+type AccountProfileExportDto struct {
+	ID                  string     `json:"id"`
+	FullName            string     `json:"full_name"`
+	Email               string     `json:"email"`
+	EmailVerifiedAt     *time.Time `json:"email_verified_at"`
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// VerificationExportDto is a code sent to the user, as written to the account export.
+// The codes themselves are left out so the export can not be used to verify anything.
+type VerificationExportDto struct {
+	Type      string     `json:"type"`
+	SentTo    string     `json:"sent_to,omitempty"`
+	Attempts  int        `json:"attempts"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	ClosedAt  *time.Time `json:"closed_at"`
+}
+
 type UserResponseDto struct {
 	ID              string    `json:"id"`
 	FullName        string    `json:"full_name"`
@@ -45,13 +266,32 @@ type UserResponseDto struct {
 }
 
 type TokenResponse struct {
-	AccessToken string `json:"access_token"`
-	Type        string `json:"type"`
+	AccessToken  string `json:"access_token"`
+	Type         string `json:"type"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
+// LoginResponse carries the tokens of a login, or the challenge to answer
+// with a second factor when the user has two-factor authentication enabled
 type LoginResponse struct {
-	User  UserResponseDto `json:"user"`
-	Token TokenResponse   `json:"token"`
+	User      UserResponseDto             `json:"user"`
+	Token     *TokenResponse              `json:"token,omitempty"`
+	TwoFactor *TwoFactorChallengeResponse `json:"two_factor,omitempty"`
+}
+
+type TwoFactorChallengeResponse struct {
+	ChallengeToken string `json:"challenge_token"`
+	ExpiresIn      int    `json:"expires_in"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
 }
 
 type ProfileResponse struct {
@@ -68,6 +308,33 @@ type VerificationDto struct {
 	Email  string `json:"email" validate:"email,required"`
 	Code   string `json:"code" validate:"required,min=6,max=6"`
 	Type   string `json:"type" validate:"required,oneof=email-verification password-reset"`
+	// ExpiresAt is set by the service when a code is generated
+	ExpiresAt time.Time `json:"-"`
+	// SentTo is the address a generated code is emailed to
+	SentTo string `json:"-"`
+}
+
+// JWK is the public part of a token signing key, as described in RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// MailDto describes an email built from one of the mail templates
+type MailDto struct {
+	To       string
+	Template string
+	Data     map[string]interface{}
 }
 
 // PostmanCollection represents the structure of a Postman collection.
@@ -122,21 +389,24 @@ type WorkExperienceDto struct {
 	CompanyName string     `json:"company_name" validate:"required,max=255"`
 	Role        string     `json:"role" validate:"required,max=255"`
 	StartDate   time.Time  `json:"start_date" validate:"required"`
-	EndDate     *time.Time `json:"end_date" `
+	EndDate     *time.Time `json:"end_date" validate:"omitempty,gtefield=StartDate"`
 }
 
 type EducationDto struct {
 	SchoolName string     `json:"school_name" validate:"required,max=255"`
 	Course     string     `json:"course" validate:"required,max=255"`
+	StudyType  string     `json:"study_type" validate:"omitempty,max=100"`
 	StartDate  time.Time  `json:"start_date" validate:"required"`
-	EndDate    *time.Time `json:"end_date" `
+	EndDate    *time.Time `json:"end_date" validate:"omitempty,gtefield=StartDate"`
 }
 
 type ResumeDto struct {
-	ID      string   `json:"id"`
-	UserId  string   `json:"user_id"`
-	Summary string   `json:"summary"`
-	Skills  []string `json:"skills"`
+	ID         string   `json:"id"`
+	UserId     string   `json:"user_id"`
+	Score      int      `json:"score"`
+	Summary    string   `json:"summary"`
+	Skills     []string `json:"skills"`
+	TemplateId string   `json:"template_id,omitempty"`
 }
 
 type ResumeFilterDto struct {
@@ -145,8 +415,104 @@ type ResumeFilterDto struct {
 }
 
 type CreateResumeDto struct {
-	Summary     string              `json:"summary" validate:"required,max=255"`
+	Summary     string              `json:"summary" validate:"required,max=200"`
 	Skills      []string            `json:"skills" validate:"required,min=1"`
 	Experiences []WorkExperienceDto `json:"experience" validate:"required,dive"`
 	Education   []EducationDto      `json:"education" validate:"required,dive"`
+	TemplateId  string              `json:"template_id" validate:"omitempty,max=50"`
+}
+
+type WorkExperienceResponseDto struct {
+	ID          string     `json:"id"`
+	CompanyName string     `json:"company_name"`
+	Role        string     `json:"role"`
+	StartDate   time.Time  `json:"start_date"`
+	EndDate     *time.Time `json:"end_date"`
+}
+
+type EducationResponseDto struct {
+	ID         string     `json:"id"`
+	SchoolName string     `json:"school_name"`
+	Course     string     `json:"course"`
+	StudyType  string     `json:"study_type,omitempty"`
+	StartDate  time.Time  `json:"start_date"`
+	EndDate    *time.Time `json:"end_date"`
+}
+
+type ResumeDetailsDto struct {
+	ID          string                      `json:"id"`
+	UserId      string                      `json:"user_id"`
+	Score       int                         `json:"score"`
+	Summary     string                      `json:"summary"`
+	Skills      []string                    `json:"skills"`
+	TemplateId  string                      `json:"template_id,omitempty"`
+	Experiences []WorkExperienceResponseDto `json:"experience"`
+	Education   []EducationResponseDto      `json:"education"`
+}
+
+type UpdateResumeDto struct {
+	Summary    *string  `json:"summary" validate:"omitempty,max=200"`
+	Skills     []string `json:"skills" validate:"omitempty,min=1"`
+	TemplateId *string  `json:"template_id" validate:"omitempty,max=50"`
+}
+
+// UpdateWorkExperienceDto changes the fields that are set. ClearEndDate removes the end date
+// to mark the role as the current one.
+type UpdateWorkExperienceDto struct {
+	CompanyName  *string    `json:"company_name" validate:"omitempty,max=255"`
+	Role         *string    `json:"role" validate:"omitempty,max=255"`
+	StartDate    *time.Time `json:"start_date"`
+	EndDate      *time.Time `json:"end_date"`
+	ClearEndDate bool       `json:"clear_end_date" validate:"excluded_with=EndDate"`
+}
+
+// UpdateEducationDto changes the fields that are set. ClearEndDate removes the end date of
+// studies that are still ongoing.
+type UpdateEducationDto struct {
+	SchoolName   *string    `json:"school_name" validate:"omitempty,max=255"`
+	Course       *string    `json:"course" validate:"omitempty,max=255"`
+	StudyType    *string    `json:"study_type" validate:"omitempty,max=100"`
+	StartDate    *time.Time `json:"start_date"`
+	EndDate      *time.Time `json:"end_date"`
+	ClearEndDate bool       `json:"clear_end_date" validate:"excluded_with=EndDate"`
+}
+
+type ExportDto struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+type TemplateDto struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Layout      string   `json:"layout"`
+	Sections    []string `json:"sections"`
+	IsDefault   bool     `json:"is_default"`
+}
+
+type ScoreCriterionDto struct {
+	Name        string   `json:"name"`
+	Score       int      `json:"score"`
+	MaxScore    int      `json:"max_score"`
+	Suggestions []string `json:"suggestions"`
+}
+
+type ResumeScoreDto struct {
+	ResumeId string              `json:"resume_id"`
+	Score    int                 `json:"score"`
+	MaxScore int                 `json:"max_score"`
+	Criteria []ScoreCriterionDto `json:"criteria"`
+}
+
+type MatchResumeDto struct {
+	JobDescription string `json:"job_description" validate:"required,min=20,max=20000"`
+}
+
+type ResumeMatchDto struct {
+	ResumeId        string   `json:"resume_id"`
+	MatchPercentage int      `json:"match_percentage"`
+	MatchedKeywords []string `json:"matched_keywords"`
+	MissingKeywords []string `json:"missing_keywords"`
 }
@@ -6,26 +6,47 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/mail"
+	"github.com/stivo-m/vise-resume/internal/config"
 	"github.com/stivo-m/vise-resume/internal/core/services"
 )
 
 func SetupTestServer() (*fiber.App, *database.DB, error) {
+	app, db, _, err := SetupTestServerWithOutbox()
+	return app, db, err
+}
+
+// SetupTestServerWithOutbox prepares a test server whose emails are kept
+// in the returned outbox so tests can read the codes sent to users
+func SetupTestServerWithOutbox() (*fiber.App, *database.DB, *mail.Outbox, error) {
 
 	err := os.Setenv("TOKEN_SECRET_KEY", "mockValue")
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	db, err := database.SetupMockDB()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+
+	outbox := mail.NewOutbox()
+	mailer, err := mail.NewMailer("Vise Resume <no-reply@example.com>", outbox)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// The tests configure the server through the environment
+	cfg, err := config.Load(config.Options{})
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	server := services.NewServer(db)
+	server := services.NewServer(cfg, db, mailer)
 	app, err := server.PrepareServer()
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	return app, db, nil
+	return app, db, outbox, nil
 }
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+type AccountService interface {
+	ExportAccount(ctx context.Context, id string) (*dto.ExportDto, error)
+	RequestDeletionCode(ctx context.Context, id string) error
+	DeleteAccount(ctx context.Context, id string, payload dto.DeleteAccountDto) (*dto.AccountDeletionDto, error)
+	PurgeDeletedAccounts(ctx context.Context) (int, error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+type AdminService interface {
+	ListUsers(ctx context.Context, payload dto.SearchUsersDto) (*dto.UserListDto, error)
+	FindUser(ctx context.Context, id string) (*dto.AdminUserDto, error)
+	VerifyUser(ctx context.Context, adminId string, id string) error
+	DisableUser(ctx context.Context, adminId string, id string) error
+	EnableUser(ctx context.Context, adminId string, id string) error
+	ForcePasswordReset(ctx context.Context, adminId string, id string) error
+	ListRoles(ctx context.Context) ([]dto.RoleDto, error)
+	AssignRole(ctx context.Context, adminId string, id string, payload dto.AssignRoleDto) error
+	RemoveRole(ctx context.Context, adminId string, id string, role string) error
+}
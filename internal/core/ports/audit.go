@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+// AuditPort stores the audit log of authentication and account events. Events can only be
+// appended; nothing changes or removes them once they are recorded.
+type AuditPort interface {
+	// AppendEvent chains an event to the last one recorded and stores it
+	AppendEvent(ctx context.Context, event domain.AuditEvent) error
+	FindEvents(ctx context.Context, payload dto.AuditQueryDto) ([]domain.AuditEvent, int64, error)
+	// FindEventsAfter lists up to limit events following the one with the given id, oldest first
+	FindEventsAfter(ctx context.Context, id int64, limit int) ([]domain.AuditEvent, error)
+}
+
+type AuditService interface {
+	ListActivity(ctx context.Context, id string, payload dto.AuditQueryDto) (*dto.AuditEventListDto, error)
+	SearchEvents(ctx context.Context, payload dto.AuditQueryDto) (*dto.AuditEventListDto, error)
+	VerifyLog(ctx context.Context) (*dto.AuditVerificationDto, error)
+}
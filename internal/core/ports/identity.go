@@ -0,0 +1,30 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+// IdentityProvider lets users sign in with an account they have elsewhere, using the OAuth2
+// authorization code flow with PKCE
+type IdentityProvider interface {
+	// Name identifies the provider in routes and linked identities, e.g. "google"
+	Name() string
+	// AuthCodeURL returns the address of the provider the user signs in at
+	AuthCodeURL(ctx context.Context, state string, nonce string, codeChallenge string) (string, error)
+	// Exchange redeems the code the provider sent the user back with and returns the identity it
+	// vouches for. Providers issuing ID tokens verify them, along with the nonce, first.
+	Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*dto.ExternalIdentityDto, error)
+}
+
+type IdentityPort interface {
+	CreateLoginState(ctx context.Context, state domain.LoginState) error
+	// TakeLoginState finds a login state and deletes it, so every state can only be used once
+	TakeLoginState(ctx context.Context, stateHash string) (*domain.LoginState, error)
+	// FindExternalIdentity returns nil without an error when the identity is not linked to a user
+	FindExternalIdentity(ctx context.Context, provider string, subject string) (*domain.ExternalIdentity, error)
+	CreateExternalIdentity(ctx context.Context, identity domain.ExternalIdentity) error
+	UpdateExternalIdentity(ctx context.Context, id string, updates map[string]interface{}) error
+}
@@ -0,0 +1,11 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, message dto.MailDto) error
+}
@@ -3,4 +3,18 @@ package ports
 type PasswordService interface {
 	HashPassword(password string) (string, error)
 	VerifyPassword(password string, encryptedPassword string) bool
+	// NeedsRehash tells whether a hash was made with an older algorithm or weaker parameters
+	// than new hashes, so it can be replaced while the password is at hand
+	NeedsRehash(encryptedPassword string) bool
+}
+
+// PasswordPolicy decides whether a password is strong enough to be chosen by a user.
+// The personal values, like their name and email address, must not appear in it.
+type PasswordPolicy interface {
+	Check(password string, personal ...string) error
+}
+
+// BreachedPasswords tells whether a password is known to have leaked
+type BreachedPasswords interface {
+	Contains(password string) bool
 }
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+)
+
+// RateLimitStore persists rate limit state. Find returns nil without an error for unknown keys.
+type RateLimitStore interface {
+	Find(ctx context.Context, key string) (*domain.RateLimit, error)
+	Save(ctx context.Context, state domain.RateLimit) error
+	Delete(ctx context.Context, key string) error
+}
+
+type RateLimiter interface {
+	Allow(ctx context.Context, keys []string) (time.Duration, error)
+	RecordFailure(ctx context.Context, keys []string) error
+	RecordSuccess(ctx context.Context, keys []string) error
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+type TemplatePort interface {
+	ListTemplates() []dto.TemplateDto
+	HasTemplate(id string) bool
+}
+
+type ResumeRenderer interface {
+	Format() string
+	ContentType() string
+	Render(resume domain.Resume) ([]byte, error)
+}
@@ -11,6 +11,8 @@ type ResumePort interface {
 	CreateResume(ctx context.Context, resume dto.ResumeDto) (*domain.Resume, error)
 	FindResumeById(ctx context.Context, id string) (*domain.Resume, error)
 	FindResumeList(ctx context.Context, filter dto.ResumeFilterDto) ([]domain.Resume, error)
+	FindUserResumes(ctx context.Context, userId string) ([]domain.Resume, error)
+	PurgeUserResumes(ctx context.Context, userId string) error
 	UpdateResume(ctx context.Context, id string, updates map[string]interface{}) error
 	DeleteResume(ctx context.Context, id string) error
 	AddWorkExperiences(ctx context.Context, id string, experiences []dto.WorkExperienceDto) error
@@ -24,4 +26,18 @@ type ResumePort interface {
 type ResumeService interface {
 	CreateResume(ctx context.Context, payload dto.CreateResumeDto) (*dto.ResumeDto, error)
 	FindResumes(ctx context.Context, payload dto.ResumeFilterDto) ([]dto.ResumeDto, error)
+	FindResume(ctx context.Context, id string) (*dto.ResumeDetailsDto, error)
+	FindAnyResume(ctx context.Context, id string) (*dto.ResumeDetailsDto, error)
+	UpdateResume(ctx context.Context, id string, payload dto.UpdateResumeDto) error
+	DeleteResume(ctx context.Context, id string) error
+	AddWorkExperience(ctx context.Context, id string, payload dto.WorkExperienceDto) error
+	UpdateWorkExperience(ctx context.Context, id string, experienceId string, payload dto.UpdateWorkExperienceDto) error
+	DeleteWorkExperience(ctx context.Context, id string, experienceId string) error
+	AddEducation(ctx context.Context, id string, payload dto.EducationDto) error
+	UpdateEducation(ctx context.Context, id string, educationId string, payload dto.UpdateEducationDto) error
+	DeleteEducation(ctx context.Context, id string, educationId string) error
+	ExportResume(ctx context.Context, id string, format string) (*dto.ExportDto, error)
+	ListTemplates(ctx context.Context) []dto.TemplateDto
+	ScoreResume(ctx context.Context, id string) (*dto.ResumeScoreDto, error)
+	MatchResume(ctx context.Context, id string, payload dto.MatchResumeDto) (*dto.ResumeMatchDto, error)
 }
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+)
+
+type RolePort interface {
+	FindRoles(ctx context.Context) ([]domain.Role, error)
+	FindRole(ctx context.Context, name string) (*domain.Role, error)
+	FindUserRoles(ctx context.Context, userId string) ([]domain.Role, error)
+	FindUserPermissions(ctx context.Context, userId string) ([]string, error)
+	AssignRole(ctx context.Context, userId string, roleId string) error
+	RemoveRole(ctx context.Context, userId string, roleId string) error
+}
@@ -0,0 +1,10 @@
+package ports
+
+import (
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+type ResumeScorer interface {
+	Score(resume domain.Resume) dto.ResumeScoreDto
+}
@@ -1,8 +1,15 @@
 package ports
 
-import "time"
+import (
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
 
 type TokenService interface {
 	CreateToken(id string, expiryDate time.Time) (string, error)
 	VerifyToken(token string) (string, error)
+	CreateLinkToken(payload dto.LinkTokenDto) (string, error)
+	VerifyLinkToken(token string, purpose string) (*dto.LinkTokenDto, error)
+	JWKS() dto.JWKSet
 }
@@ -0,0 +1,9 @@
+package ports
+
+import "context"
+
+// UnitOfWork groups several writes so that they either all succeed or are all rolled back.
+// Ports called with the context given to fn take part in the same transaction.
+type UnitOfWork interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
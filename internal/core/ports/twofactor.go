@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+)
+
+type TwoFactorPort interface {
+	// FindTwoFactor returns nil without an error when the user never set two-factor authentication up
+	FindTwoFactor(ctx context.Context, userId string) (*domain.TwoFactor, error)
+	SaveTwoFactor(ctx context.Context, twoFactor domain.TwoFactor) error
+	UpdateTwoFactor(ctx context.Context, userId string, updates map[string]interface{}) error
+	UseTOTPStep(ctx context.Context, userId string, step int64) error
+	// ConsumeAttempt counts a guess at a second factor and reports false, without counting it,
+	// when the user already made maxAttempts of them
+	ConsumeAttempt(ctx context.Context, userId string, maxAttempts int) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userId string, hashes []string) error
+	UseRecoveryCode(ctx context.Context, userId string, codeHash string) error
+}
+
+type TOTPService interface {
+	GenerateSecret() (string, error)
+	URI(secret string, account string) string
+	// Validate checks a code against the secret and returns the time step it belongs to
+	Validate(secret string, code string) (int64, bool)
+}
@@ -2,6 +2,7 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/stivo-m/vise-resume/internal/core/domain"
 	"github.com/stivo-m/vise-resume/internal/core/dto"
@@ -10,21 +11,53 @@ import (
 type UserPort interface {
 	CreateUser(ctx context.Context, user domain.User) (*domain.User, error)
 	FindUser(ctx context.Context, payload dto.FindUserDto) (*domain.User, error)
+	SearchUsers(ctx context.Context, payload dto.SearchUsersDto) ([]domain.User, int64, error)
 	UpdateUser(ctx context.Context, id string, updates map[string]interface{}) error
 	UpdateUserPassword(ctx context.Context, id string, password domain.Password) error
 	DeleteUser(ctx context.Context, id string) error
+	FindUsersPendingDeletion(ctx context.Context, requestedBefore time.Time) ([]domain.User, error)
+	PurgeUser(ctx context.Context, id string) error
 	CreateToken(ctx context.Context, payload dto.ManageTokenDto) error
 	FindToken(ctx context.Context, payload dto.ManageTokenDto) (*domain.Token, error)
+	FindTokens(ctx context.Context, payload dto.ManageTokenDto) ([]domain.Token, error)
+	UpdateToken(ctx context.Context, id string, updates map[string]interface{}) error
 	DeleteToken(ctx context.Context, payload dto.ManageTokenDto) error
+	MarkTokenUsed(ctx context.Context, id string) error
+	ConsumeToken(ctx context.Context, id string) error
+	DeleteTokenFamily(ctx context.Context, familyId string, tokenType string) error
+	DeleteUserTokens(ctx context.Context, userId string, exceptFamilyId string) error
+	CreatePersonalAccessToken(ctx context.Context, token domain.PersonalAccessToken) (*domain.PersonalAccessToken, error)
+	FindPersonalAccessToken(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error)
+	FindPersonalAccessTokens(ctx context.Context, userId string) ([]domain.PersonalAccessToken, error)
+	UpdatePersonalAccessToken(ctx context.Context, id string, updates map[string]interface{}) error
+	DeletePersonalAccessToken(ctx context.Context, userId string, id string) error
 }
 
 type UserService interface {
 	RegisterUser(ctx context.Context, payload dto.RegisterDto) (*dto.ProfileResponse, error)
 	LoginUser(ctx context.Context, payload dto.LoginDto) (*dto.LoginResponse, error)
+	StartExternalLogin(ctx context.Context, provider string, binding string) (string, error)
+	CompleteExternalLogin(ctx context.Context, provider string, payload dto.ExternalLoginCallbackDto) (*dto.LoginResponse, error)
+	RequestMagicLink(ctx context.Context, payload dto.EmailDto, binding string) error
+	CompleteMagicLink(ctx context.Context, payload dto.MagicLinkCallbackDto) (*dto.LoginResponse, error)
 	VerifyEmailAddress(ctx context.Context, payload dto.VerificationDto) error
 	ShowProfile(ctx context.Context, id string) (*dto.UserResponseDto, error)
 	UpdateUser(ctx context.Context, id string, updates map[string]interface{}) error
+	RequestEmailChange(ctx context.Context, id string, payload dto.ChangeEmailDto) error
+	ConfirmEmailChange(ctx context.Context, id string, payload dto.ConfirmEmailChangeDto) error
 	ForgetPassword(ctx context.Context, payload dto.EmailDto) error
 	ResetPassword(ctx context.Context, payload dto.ResetPasswordDto) error
+	ChangePassword(ctx context.Context, id string, currentToken string, payload dto.ChangePasswordDto) error
+	ResendVerification(ctx context.Context, payload dto.EmailDto) error
+	RefreshToken(ctx context.Context, payload dto.RefreshTokenDto) (*dto.LoginResponse, error)
 	LogoutUser(ctx context.Context, token string) error
+	ListSessions(ctx context.Context, id string, currentToken string) ([]dto.SessionDto, error)
+	RevokeSession(ctx context.Context, id string, sessionId string) error
+	LogoutAll(ctx context.Context, id string, currentToken string, keepCurrent bool) error
+	SetupTwoFactor(ctx context.Context, id string) (*dto.TwoFactorSetupResponse, error)
+	EnableTwoFactor(ctx context.Context, id string, payload dto.TwoFactorCodeDto) (*dto.RecoveryCodesResponse, error)
+	VerifyTwoFactor(ctx context.Context, payload dto.TwoFactorVerifyDto) (*dto.LoginResponse, error)
+	CreatePersonalAccessToken(ctx context.Context, id string, payload dto.CreatePersonalAccessTokenDto) (*dto.PersonalAccessTokenDto, error)
+	ListPersonalAccessTokens(ctx context.Context, id string) ([]dto.PersonalAccessTokenDto, error)
+	RevokePersonalAccessToken(ctx context.Context, id string, tokenId string) error
 }
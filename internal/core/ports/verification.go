@@ -10,10 +10,12 @@ import (
 type VerificationPort interface {
 	CreateCode(ctx context.Context, user dto.VerificationDto) error
 	FindCode(ctx context.Context, payload dto.VerificationDto) (*domain.Verifications, error)
+	FindUserCodes(ctx context.Context, userId string) ([]domain.Verifications, error)
 	DeleteCode(ctx context.Context, id string) error
+	ConsumeAttempt(ctx context.Context, id string, maxAttempts int) (bool, error)
 }
 
 type VerificationService interface {
-	VerifyCode(ctx context.Context, payload dto.VerificationDto) error
-	GenerateCode(ctx context.Context, payload dto.VerificationDto) error
+	VerifyCode(ctx context.Context, payload dto.VerificationDto) (*domain.Verifications, error)
+	GenerateCode(ctx context.Context, payload dto.VerificationDto) (string, error)
 }
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// AccountDeletionGracePeriod is how long a deleted account can still be recovered by logging in
+const AccountDeletionGracePeriod = 30 * 24 * time.Hour
+
+// AccountService holds the usecases that let users take their data with them or erase it
+type AccountService struct {
+	unitOfWork          ports.UnitOfWork
+	userPort            ports.UserPort
+	resumePort          ports.ResumePort
+	verificationPort    ports.VerificationPort
+	verificationService ports.VerificationService
+	passwordService     ports.PasswordService
+	auditPort           ports.AuditPort
+	mailer              ports.Mailer
+	now                 func() time.Time
+}
+
+func NewAccountService(
+	unitOfWork ports.UnitOfWork,
+	userPort ports.UserPort,
+	resumePort ports.ResumePort,
+	verificationPort ports.VerificationPort,
+	verificationService ports.VerificationService,
+	passwordService ports.PasswordService,
+	auditPort ports.AuditPort,
+	mailer ports.Mailer,
+) *AccountService {
+	return NewAccountServiceWithClock(unitOfWork, userPort, resumePort, verificationPort, verificationService, passwordService, auditPort, mailer, time.Now)
+}
+
+// NewAccountServiceWithClock creates an account service reading the time from now
+func NewAccountServiceWithClock(
+	unitOfWork ports.UnitOfWork,
+	userPort ports.UserPort,
+	resumePort ports.ResumePort,
+	verificationPort ports.VerificationPort,
+	verificationService ports.VerificationService,
+	passwordService ports.PasswordService,
+	auditPort ports.AuditPort,
+	mailer ports.Mailer,
+	now func() time.Time,
+) *AccountService {
+	return &AccountService{
+		unitOfWork:          unitOfWork,
+		userPort:            userPort,
+		resumePort:          resumePort,
+		verificationPort:    verificationPort,
+		verificationService: verificationService,
+		passwordService:     passwordService,
+		auditPort:           auditPort,
+		mailer:              mailer,
+		now:                 now,
+	}
+}
+
+// The [ExportAccount] usecase gathers everything stored about a user into a ZIP archive of JSON
+// files: their profile, resumes with experiences and education, sessions and verification codes.
+func (s AccountService) ExportAccount(ctx context.Context, id string) (*dto.ExportDto, error) {
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	resumes, err := s.resumePort.FindUserResumes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.userPort.FindTokens(ctx, dto.ManageTokenDto{ID: id, Type: "access", WithRevoked: true})
+	if err != nil {
+		return nil, err
+	}
+
+	verifications, err := s.verificationPort.FindUserCodes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := dto.AccountProfileExportDto{
+		ID:                  user.ID,
+		FullName:            user.FullName,
+		Email:               user.Email,
+		EmailVerifiedAt:     user.EmailVerifiedAt,
+		DeletionRequestedAt: user.DeletionRequestedAt,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
+	}
+
+	files := []exportFile{
+		{name: "profile.json", content: profile},
+		{name: "resumes.json", content: exportedResumes(resumes)},
+		{name: "sessions.json", content: exportedSessions(tokens)},
+		{name: "verifications.json", content: exportedVerifications(verifications)},
+	}
+
+	content, err := zipJSONFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ExportDto{
+		FileName:    fmt.Sprintf("vise-resume-export-%s.zip", s.now().UTC().Format("20060102")),
+		ContentType: "application/zip",
+		Content:     content,
+	}, nil
+}
+
+// The [RequestDeletionCode] usecase emails a user a code confirming the deletion of their account,
+// for the users who can not confirm it with a password they never chose
+func (s AccountService) RequestDeletionCode(ctx context.Context, id string) error {
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: id})
+	if err != nil {
+		return err
+	}
+
+	// The email is sent last so a delivery failure discards the code, and the user can ask again
+	return s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		code, err := s.verificationService.GenerateCode(ctx, dto.VerificationDto{UserID: user.ID, Type: "account-deletion", SentTo: user.Email})
+		if err != nil {
+			return err
+		}
+
+		return s.mailer.Send(ctx, dto.MailDto{
+			To:       user.Email,
+			Template: "account-deletion",
+			Data: map[string]interface{}{
+				"Name": user.FullName,
+				"Code": code,
+			},
+		})
+	})
+}
+
+// The [DeleteAccount] usecase schedules the deletion of a user's account once they confirm their
+// password, or a code from [RequestDeletionCode], and signs them out everywhere. The account and everything it owns is erased by
+// [PurgeDeletedAccounts] after [AccountDeletionGracePeriod]; logging in before that cancels it.
+func (s AccountService) DeleteAccount(ctx context.Context, id string, payload dto.DeleteAccountDto) (_ *dto.AccountDeletionDto, err error) {
+	defer func() {
+		appendAuditEvent(ctx, s.auditPort, domain.AuditEvent{Action: domain.AuditAccountDeleted, ActorId: id, Details: "user " + id}, err)
+	}()
+
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: id, WithPassword: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var verification *domain.Verifications
+	if payload.Code != "" {
+		verification, err = s.verificationService.VerifyCode(ctx, dto.VerificationDto{UserID: user.ID, Code: payload.Code, Type: "account-deletion"})
+		if err != nil {
+			return nil, err
+		}
+
+		// A code sent before the email address changed does not prove access to the current one
+		if verification.SentTo != user.Email {
+			return nil, errInvalidCode
+		}
+	} else if !s.passwordService.VerifyPassword(payload.Password, user.Password.Value) {
+		utils.TextLogger.Error("password mismatch", "user", user.ID)
+		return nil, errors.New("password is incorrect")
+	}
+
+	personalAccessTokens, err := s.userPort.FindPersonalAccessTokens(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	requestedAt := s.now()
+	err = s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userPort.UpdateUser(ctx, id, map[string]interface{}{"deletion_requested_at": requestedAt}); err != nil {
+			return err
+		}
+
+		if verification != nil {
+			if err := s.verificationPort.DeleteCode(ctx, verification.ID); err != nil {
+				return err
+			}
+		}
+
+		for _, token := range personalAccessTokens {
+			if err := s.userPort.DeletePersonalAccessToken(ctx, id, token.ID); err != nil {
+				return err
+			}
+		}
+
+		return s.userPort.DeleteUserTokens(ctx, id, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.TextLogger.Info("account deletion requested", "user", id)
+	return &dto.AccountDeletionDto{DeletionScheduledAt: requestedAt.Add(AccountDeletionGracePeriod)}, nil
+}
+
+// The [PurgeDeletedAccounts] usecase erases the accounts whose grace period has passed, along
+// with their resumes, and returns how many were erased. It is meant to run on a schedule.
+func (s AccountService) PurgeDeletedAccounts(ctx context.Context) (int, error) {
+	users, err := s.userPort.FindUsersPendingDeletion(ctx, s.now().Add(-AccountDeletionGracePeriod))
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, user := range users {
+		err := s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.resumePort.PurgeUserResumes(ctx, user.ID); err != nil {
+				return err
+			}
+
+			return s.userPort.PurgeUser(ctx, user.ID)
+		})
+		// The purge runs on a schedule, so the events have no actor
+		appendAuditEvent(ctx, s.auditPort, domain.AuditEvent{Action: domain.AuditAccountPurged, Details: "user " + user.ID}, err)
+		if err != nil {
+			return purged, err
+		}
+
+		utils.TextLogger.Info("account purged", "user", user.ID)
+		purged++
+	}
+
+	return purged, nil
+}
+
+func exportedResumes(resumes []domain.Resume) []dto.ResumeDetailsDto {
+	result := make([]dto.ResumeDetailsDto, 0, len(resumes))
+	for _, resume := range resumes {
+		result = append(result, *resumeDetails(resume))
+	}
+
+	return result
+}
+
+func exportedSessions(tokens []domain.Token) []dto.SessionDto {
+	result := make([]dto.SessionDto, 0, len(tokens))
+	for _, token := range tokens {
+		session := dto.SessionDto{
+			ID:         token.ID,
+			IpAddress:  token.IpAddress,
+			UserAgent:  token.UserAgent,
+			CreatedAt:  token.CreatedAt,
+			LastUsedAt: token.LastUsedAt,
+			ExpiresAt:  token.ExpiresAt,
+		}
+		if token.DeletedAt.Valid {
+			session.RevokedAt = &token.DeletedAt.Time
+		}
+
+		result = append(result, session)
+	}
+
+	return result
+}
+
+func exportedVerifications(verifications []domain.Verifications) []dto.VerificationExportDto {
+	result := make([]dto.VerificationExportDto, 0, len(verifications))
+	for _, verification := range verifications {
+		exported := dto.VerificationExportDto{
+			Type:      verification.Type,
+			SentTo:    verification.SentTo,
+			Attempts:  verification.Attempts,
+			CreatedAt: verification.CreatedAt,
+			ExpiresAt: verification.ExpiresAt,
+		}
+		if verification.DeletedAt.Valid {
+			exported.ClosedAt = &verification.DeletedAt.Time
+		}
+
+		result = append(result, exported)
+	}
+
+	return result
+}
+
+type exportFile struct {
+	name    string
+	content interface{}
+}
+
+// zipJSONFiles writes every file as indented JSON into a ZIP archive
+func zipJSONFiles(files []exportFile) ([]byte, error) {
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	for _, file := range files {
+		writer, err := archive.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := json.NewEncoder(writer)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(file.content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,80 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/database/repository"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurgeDeletedAccountsAfterGracePeriod(t *testing.T) {
+	t.Setenv("TOKEN_SECRET_KEY", "mockValue")
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.NoError(t, err)
+	resume, err := test.CreateTestResume(db, user.ID)
+	assert.NoError(t, err)
+	other, _, err := test.GetAuthenticatedTestUser(db)
+	assert.NoError(t, err)
+
+	clock := &testClock{now: time.Now()}
+	userRepo := repository.NewUserRepository(db)
+	service := services.NewAccountServiceWithClock(
+		db,
+		userRepo,
+		repository.NewResumeRepository(db),
+		repository.NewVerificationRepository(db),
+		services.NewVerificationService(repository.NewVerificationRepository(db), services.DefaultVerificationPolicies()),
+		services.NewPasswordService(),
+		repository.NewAuditRepository(db),
+		nil,
+		clock.Now,
+	)
+
+	_, err = service.DeleteAccount(ctx, user.ID, dto.DeleteAccountDto{Password: "wrong"})
+	assert.Error(t, err)
+
+	scheduled, err := service.DeleteAccount(ctx, user.ID, dto.DeleteAccountDto{Password: "password"})
+	assert.NoError(t, err)
+	assert.WithinDuration(t, clock.now.Add(services.AccountDeletionGracePeriod), scheduled.DeletionScheduledAt, time.Second)
+
+	purged, err := service.PurgeDeletedAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, purged, "Expected accounts to be kept during the grace period")
+
+	clock.now = clock.now.Add(services.AccountDeletionGracePeriod + time.Minute)
+	purged, err = service.PurgeDeletedAccounts(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	var count int64
+	db.Db.Unscoped().Model(&domain.User{}).Where("id = ?", user.ID).Count(&count)
+	assert.Zero(t, count, "Expected the user to be erased")
+	db.Db.Unscoped().Model(&domain.Token{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Zero(t, count, "Expected the tokens of the user to be erased")
+	db.Db.Unscoped().Model(&domain.Password{}).Where("user_id = ?", user.ID).Count(&count)
+	assert.Zero(t, count, "Expected the password of the user to be erased")
+	db.Db.Unscoped().Model(&domain.WorkExperience{}).Where("resume_id = ?", resume.ID).Count(&count)
+	assert.Zero(t, count, "Expected the experiences of the resumes to be erased")
+
+	_, err = userRepo.FindUser(ctx, dto.FindUserDto{ID: other.ID})
+	assert.NoError(t, err, "Expected other users to be kept")
+
+	var purgedEvents []domain.AuditEvent
+	db.Db.Where("action = ?", domain.AuditAccountPurged).Find(&purgedEvents)
+	if assert.Len(t, purgedEvents, 1) {
+		assert.Equal(t, "user "+user.ID, purgedEvents[0].Details)
+		assert.Empty(t, purgedEvents[0].ActorId, "Expected the scheduled purge to have no actor")
+		assert.Equal(t, domain.AuditSuccess, purgedEvents[0].Outcome)
+	}
+}
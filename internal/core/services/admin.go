@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+// AdminService holds the usecases of the admin API, which lets the support team
+// look accounts up and fix them without touching the database
+type AdminService struct {
+	unitOfWork  ports.UnitOfWork
+	userPort    ports.UserPort
+	rolePort    ports.RolePort
+	userService ports.UserService
+	auditPort   ports.AuditPort
+}
+
+func NewAdminService(
+	unitOfWork ports.UnitOfWork,
+	userPort ports.UserPort,
+	rolePort ports.RolePort,
+	userService ports.UserService,
+	auditPort ports.AuditPort,
+) *AdminService {
+	return &AdminService{
+		unitOfWork:  unitOfWork,
+		userPort:    userPort,
+		rolePort:    rolePort,
+		userService: userService,
+		auditPort:   auditPort,
+	}
+}
+
+// The [ListUsers] usecase lists users page by page, optionally searching their name and email
+func (s AdminService) ListUsers(ctx context.Context, payload dto.SearchUsersDto) (*dto.UserListDto, error) {
+	if payload.Page < 1 {
+		payload.Page = 1
+	}
+	if payload.Limit < 1 {
+		payload.Limit = defaultUserPageSize
+	}
+	payload.Limit = min(payload.Limit, maxUserPageSize)
+
+	users, total, err := s.userPort.SearchUsers(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result := dto.UserListDto{
+		Users: make([]dto.AdminUserDto, 0, len(users)),
+		Total: total,
+		Page:  payload.Page,
+		Limit: payload.Limit,
+	}
+	for _, user := range users {
+		result.Users = append(result.Users, adminUserResponse(user, nil))
+	}
+
+	return &result, nil
+}
+
+// The [FindUser] usecase shows a single user along with their roles
+func (s AdminService) FindUser(ctx context.Context, id string) (*dto.AdminUserDto, error) {
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := s.rolePort.FindUserRoles(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := adminUserResponse(*user, roles)
+	return &response, nil
+}
+
+// The [VerifyUser] usecase marks the email address of a user as verified
+func (s AdminService) VerifyUser(ctx context.Context, adminId string, id string) (err error) {
+	defer func() { s.audit(ctx, domain.AuditUserVerified, adminId, id, "", err) }()
+
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return errors.New("email address is already verified")
+	}
+
+	return s.userPort.UpdateUser(ctx, id, map[string]interface{}{"email_verified_at": time.Now()})
+}
+
+// The [DisableUser] usecase blocks a user from logging in and signs them out everywhere.
+// Administrators can not disable their own account.
+func (s AdminService) DisableUser(ctx context.Context, adminId string, id string) (err error) {
+	defer func() { s.audit(ctx, domain.AuditUserDisabled, adminId, id, "", err) }()
+
+	if adminId == id {
+		return errors.New("you can not disable your own account")
+	}
+
+	if _, err := s.findUser(ctx, id); err != nil {
+		return err
+	}
+
+	return s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userPort.UpdateUser(ctx, id, map[string]interface{}{"disabled_at": time.Now()}); err != nil {
+			return err
+		}
+
+		return s.userPort.DeleteUserTokens(ctx, id, "")
+	})
+}
+
+// The [EnableUser] usecase lets a disabled user log in again
+func (s AdminService) EnableUser(ctx context.Context, adminId string, id string) (err error) {
+	defer func() { s.audit(ctx, domain.AuditUserEnabled, adminId, id, "", err) }()
+
+	if _, err := s.findUser(ctx, id); err != nil {
+		return err
+	}
+
+	return s.userPort.UpdateUser(ctx, id, map[string]interface{}{"disabled_at": nil})
+}
+
+// The [ForcePasswordReset] usecase signs a user out everywhere and blocks their logins
+// until they reset their password with the code emailed to them
+func (s AdminService) ForcePasswordReset(ctx context.Context, adminId string, id string) (err error) {
+	defer func() { s.audit(ctx, domain.AuditPasswordResetForced, adminId, id, "", err) }()
+
+	user, err := s.findUser(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userPort.UpdateUser(ctx, id, map[string]interface{}{"password_reset_required": true}); err != nil {
+			return err
+		}
+
+		return s.userPort.DeleteUserTokens(ctx, id, "")
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.userService.ForgetPassword(ctx, dto.EmailDto{Email: user.Email})
+}
+
+// The [ListRoles] usecase lists the roles that can be granted and their permissions
+func (s AdminService) ListRoles(ctx context.Context) ([]dto.RoleDto, error) {
+	roles, err := s.rolePort.FindRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.RoleDto, 0, len(roles))
+	for _, role := range roles {
+		result = append(result, dto.RoleDto{
+			Name:        role.Name,
+			Description: role.Description,
+			Permissions: role.PermissionList(),
+		})
+	}
+
+	return result, nil
+}
+
+// The [AssignRole] usecase grants a role to a user
+func (s AdminService) AssignRole(ctx context.Context, adminId string, id string, payload dto.AssignRoleDto) (err error) {
+	defer func() { s.audit(ctx, domain.AuditRoleAssigned, adminId, id, payload.Role, err) }()
+
+	if _, err := s.findUser(ctx, id); err != nil {
+		return err
+	}
+
+	role, err := s.rolePort.FindRole(ctx, payload.Role)
+	if err != nil {
+		return errors.New("role not found")
+	}
+
+	utils.TextLogger.Info("granting role", "user", id, "role", role.Name)
+	return s.rolePort.AssignRole(ctx, id, role.ID)
+}
+
+// The [RemoveRole] usecase takes a role away from a user
+func (s AdminService) RemoveRole(ctx context.Context, adminId string, id string, name string) (err error) {
+	defer func() { s.audit(ctx, domain.AuditRoleRemoved, adminId, id, name, err) }()
+
+	role, err := s.rolePort.FindRole(ctx, name)
+	if err != nil {
+		return errors.New("role not found")
+	}
+
+	if err := s.rolePort.RemoveRole(ctx, id, role.ID); err != nil {
+		return errors.New("the user does not have this role")
+	}
+
+	utils.TextLogger.Info("removed role", "user", id, "role", role.Name)
+	return nil
+}
+
+// audit records an action of an administrator on the account of a user and, for the role
+// usecases, the role it was about
+func (s AdminService) audit(ctx context.Context, action string, adminId string, id string, role string, err error) {
+	details := "user " + id
+	if role != "" {
+		details += ", role " + role
+	}
+
+	appendAuditEvent(ctx, s.auditPort, domain.AuditEvent{Action: action, ActorId: adminId, Details: details}, err)
+}
+
+func (s AdminService) findUser(ctx context.Context, id string) (*domain.User, error) {
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: id})
+	if err != nil {
+		utils.TextLogger.Error("user not found", "user", id, "error", err)
+		return nil, errors.New("user not found")
+	}
+
+	return user, nil
+}
+
+func adminUserResponse(user domain.User, roles []domain.Role) dto.AdminUserDto {
+	response := dto.AdminUserDto{
+		ID:                    user.ID,
+		FullName:              user.FullName,
+		Email:                 user.Email,
+		EmailVerifiedAt:       user.EmailVerifiedAt,
+		DisabledAt:            user.DisabledAt,
+		PasswordResetRequired: user.PasswordResetRequired,
+		CreatedAt:             user.CreatedAt,
+	}
+	for _, role := range roles {
+		response.Roles = append(response.Roles, role.Name)
+	}
+
+	return response
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+const (
+	defaultAuditPageSize = 20
+	maxAuditPageSize     = 100
+	// auditVerifyBatchSize is how many events are read at once when checking the hash chain
+	auditVerifyBatchSize = 500
+	maxAuditDetailsSize  = 255
+)
+
+// AuditService reads the audit log the other usecases and the authentication middleware record
+// events in
+type AuditService struct {
+	auditPort ports.AuditPort
+}
+
+func NewAuditService(auditPort ports.AuditPort) *AuditService {
+	return &AuditService{auditPort: auditPort}
+}
+
+// The [ListActivity] usecase lists the events a user took part in, so they can spot logins or
+// changes they did not make
+func (s AuditService) ListActivity(ctx context.Context, id string, payload dto.AuditQueryDto) (*dto.AuditEventListDto, error) {
+	payload.ActorId = id
+	return s.SearchEvents(ctx, payload)
+}
+
+// The [SearchEvents] usecase lists the events of the audit log page by page, newest first
+func (s AuditService) SearchEvents(ctx context.Context, payload dto.AuditQueryDto) (*dto.AuditEventListDto, error) {
+	if payload.Page < 1 {
+		payload.Page = 1
+	}
+	if payload.Limit < 1 {
+		payload.Limit = defaultAuditPageSize
+	}
+	payload.Limit = min(payload.Limit, maxAuditPageSize)
+
+	events, total, err := s.auditPort.FindEvents(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result := dto.AuditEventListDto{
+		Events: make([]dto.AuditEventDto, 0, len(events)),
+		Total:  total,
+		Page:   payload.Page,
+		Limit:  payload.Limit,
+	}
+	for _, event := range events {
+		result.Events = append(result.Events, dto.AuditEventDto{
+			ID:        event.ID,
+			ActorId:   event.ActorId,
+			Action:    event.Action,
+			Outcome:   event.Outcome,
+			IpAddress: event.IpAddress,
+			UserAgent: event.UserAgent,
+			Details:   event.Details,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+
+	return &result, nil
+}
+
+// The [VerifyLog] usecase walks the hash chain of the audit log from its first event and
+// reports the first one that was changed, removed or inserted afterwards
+func (s AuditService) VerifyLog(ctx context.Context) (*dto.AuditVerificationDto, error) {
+	result := dto.AuditVerificationDto{Valid: true}
+	previous := domain.AuditEvent{}
+	for {
+		events, err := s.auditPort.FindEventsAfter(ctx, previous.ID, auditVerifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, event := range events {
+			reason := ""
+			switch {
+			case event.ID != previous.ID+1:
+				reason = fmt.Sprintf("event %d is missing", previous.ID+1)
+			case event.PreviousHash != previous.Hash:
+				reason = "the event is not chained to the one before it"
+			case event.Hash != event.ComputeHash():
+				reason = "the event does not match its hash"
+			}
+
+			if reason != "" {
+				id := event.ID
+				result.Valid = false
+				result.BrokenAt = &id
+				result.Reason = reason
+				return &result, nil
+			}
+
+			result.Events++
+			previous = event
+		}
+
+		if len(events) < auditVerifyBatchSize {
+			return &result, nil
+		}
+	}
+}
+
+// audit records the outcome of a usecase of the user service, see [appendAuditEvent]
+func (s UserService) audit(ctx context.Context, event domain.AuditEvent, err error) {
+	appendAuditEvent(ctx, s.auditPort, event, err)
+}
+
+// appendAuditEvent records the outcome of a usecase in the audit log, along with the client found
+// in the context. Failing to record an event is logged, it does not fail the usecase.
+func appendAuditEvent(ctx context.Context, auditPort ports.AuditPort, event domain.AuditEvent, err error) {
+	event.Outcome = domain.AuditSuccess
+	if err != nil {
+		event.Outcome = domain.AuditFailure
+		if event.Details != "" {
+			event.Details += ", "
+		}
+		event.Details += err.Error()
+	}
+	if len(event.Details) > maxAuditDetailsSize {
+		event.Details = strings.ToValidUTF8(event.Details[:maxAuditDetailsSize], "")
+	}
+
+	if client, ok := ctx.Value(utils.CLIENT_KEY).(dto.ClientDto); ok {
+		event.IpAddress = client.IpAddress
+		event.UserAgent = client.UserAgent
+	}
+
+	if err := auditPort.AppendEvent(ctx, event); err != nil {
+		utils.TextLogger.Error("unable to record audit event", "action", event.Action, "error", err)
+	}
+}
+
+// auditLogin records a login, noting the ones that still wait for a second factor
+func (s UserService) auditLogin(ctx context.Context, event domain.AuditEvent, response *dto.LoginResponse, err error) {
+	if err == nil && response.TwoFactor != nil {
+		if event.Details != "" {
+			event.Details += ", "
+		}
+		event.Details += "waiting for the second factor"
+	}
+
+	s.audit(ctx, event, err)
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+var (
+	errEmailTaken        = errors.New("this email address is already in use")
+	errNoPendingEmail    = errors.New("there is no email address change to confirm")
+	errEmailUnchanged    = errors.New("this is already the email address of your account")
+	errIncorrectPassword = errors.New("password is incorrect")
+)
+
+// The [RequestEmailChange] usecase sends a code to the address a user wants to switch to and
+// stores that address once the code is on its way. The account keeps its current address until
+// [ConfirmEmailChange] is called.
+func (s UserService) RequestEmailChange(ctx context.Context, id string, payload dto.ChangeEmailDto) error {
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: id, WithPassword: true})
+	if err != nil {
+		return err
+	}
+
+	if !s.passwordService.VerifyPassword(payload.Password, user.Password.Value) {
+		utils.TextLogger.Error("password mismatch", "user", user.ID)
+		return errIncorrectPassword
+	}
+
+	email := strings.TrimSpace(payload.Email)
+	if strings.EqualFold(email, user.Email) {
+		return errEmailUnchanged
+	}
+
+	if s.emailTaken(ctx, email) {
+		return errEmailTaken
+	}
+
+	// The code goes to the new address, which is what proves the user owns it. The address is
+	// only stored once a code went to it, so one that was never sent a code can not be confirmed.
+	pending := *user
+	pending.Email = email
+	return s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.sendCode(ctx, pending, "email-change"); err != nil {
+			return err
+		}
+
+		return s.userPort.UpdateUser(ctx, id, map[string]interface{}{"pending_email": email})
+	})
+}
+
+// The [ConfirmEmailChange] usecase switches the account to its pending email address once the user
+// gives the code sent there, and lets the previous address know about the change.
+func (s UserService) ConfirmEmailChange(ctx context.Context, id string, payload dto.ConfirmEmailChangeDto) (err error) {
+	defer func() { s.audit(ctx, domain.AuditEvent{Action: domain.AuditEmailChanged, ActorId: id}, err) }()
+
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: id})
+	if err != nil {
+		return err
+	}
+
+	if user.PendingEmail == "" {
+		return errNoPendingEmail
+	}
+
+	verificationCode, err := s.verificationService.VerifyCode(ctx, dto.VerificationDto{
+		UserID: user.ID,
+		Code:   payload.Code,
+		Type:   "email-change",
+	})
+	if err != nil {
+		return err
+	}
+
+	// The code has to be the one sent to the pending address, not one sent to an address the
+	// user asked for before
+	if verificationCode.SentTo != user.PendingEmail {
+		utils.TextLogger.Error("email change code was sent to another address", "user", user.ID)
+		return errInvalidCode
+	}
+
+	// Someone else may have taken the address since the change was requested
+	if s.emailTaken(ctx, user.PendingEmail) {
+		return errEmailTaken
+	}
+
+	updates := map[string]interface{}{
+		"email":             user.PendingEmail,
+		"pending_email":     nil,
+		"email_verified_at": time.Now(),
+	}
+
+	err = s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userPort.UpdateUser(ctx, user.ID, updates); err != nil {
+			utils.TextLogger.Error("update user email failed", "user", user.ID, "error", err)
+			return errEmailTaken
+		}
+
+		return s.verificationPort.DeleteCode(ctx, verificationCode.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, dto.MailDto{
+		To:       user.Email,
+		Template: "email-changed",
+		Data: map[string]interface{}{
+			"Name":  user.FullName,
+			"Email": user.PendingEmail,
+		},
+	})
+	if err != nil {
+		utils.TextLogger.Error("unable to notify the previous email address", "user", user.ID, "error", err)
+	}
+
+	return nil
+}
+
+func (s UserService) emailTaken(ctx context.Context, email string) bool {
+	existing, err := s.userPort.FindUser(ctx, dto.FindUserDto{Email: email})
+	return err == nil && existing.ID != ""
+}
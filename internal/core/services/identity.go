@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// LoginStateTTL is how long a user has to sign in at an identity provider and come back
+const LoginStateTTL = 10 * time.Minute
+
+var (
+	errUnknownProvider      = errors.New("this identity provider is not supported")
+	errInvalidLoginState    = errors.New("the login has expired or was already completed, please start again")
+	errLoginOtherClient     = errors.New("this login was started in another browser, please start again")
+	errExternalLoginFailed  = errors.New("the identity provider could not confirm who you are")
+	errIdentityWithoutEmail = errors.New("the identity provider did not share an email address")
+	errEmailNotConfirmed    = errors.New("an account already uses this email address, log in with your password instead")
+	errAccountNotVerified   = errors.New("an account already uses this email address but it was never verified, verify it and reset its password first")
+	errEmailNotVerified     = errors.New("email address is not verified, check your email for a verification code")
+)
+
+// The [StartExternalLogin] usecase begins a login with an identity provider and returns the address
+// the user signs in at. The state, nonce and PKCE code verifier are kept until the user comes back,
+// and the login can only be completed by the client holding binding, a secret handed to it.
+func (s UserService) StartExternalLogin(ctx context.Context, providerName string, binding string) (string, error) {
+	provider, ok := s.identityProviders[providerName]
+	if !ok {
+		return "", errUnknownProvider
+	}
+
+	state := utils.GenerateSecureToken(32)
+	nonce := utils.GenerateSecureToken(32)
+	verifier := utils.GenerateSecureToken(32)
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, codeChallenge(verifier))
+	if err != nil {
+		utils.TextLogger.Error("unable to build the authorization url", "provider", providerName, "error", err)
+		return "", errExternalLoginFailed
+	}
+
+	err = s.identityPort.CreateLoginState(ctx, domain.LoginState{
+		StateHash:    utils.HashToken(state),
+		BindingHash:  utils.HashToken(binding),
+		Provider:     providerName,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(LoginStateTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return authURL, nil
+}
+
+// The [CompleteExternalLogin] usecase finishes a login once the identity provider sends the user back.
+// The identity is linked to the account with the same email address when both the provider and
+// the account verified that address, and an account is created when there is none. The user then gets our own tokens,
+// or a challenge when they have two-factor authentication enabled.
+func (s UserService) CompleteExternalLogin(ctx context.Context, providerName string, payload dto.ExternalLoginCallbackDto) (response *dto.LoginResponse, err error) {
+	event := domain.AuditEvent{Action: domain.AuditExternalLogin, Details: "provider " + providerName}
+	defer func() { s.auditLogin(ctx, event, response, err) }()
+
+	provider, ok := s.identityProviders[providerName]
+	if !ok {
+		return nil, errUnknownProvider
+	}
+
+	state, err := s.identityPort.TakeLoginState(ctx, utils.HashToken(payload.State))
+	if err != nil || state.Provider != providerName || state.ExpiresAt.Before(time.Now()) {
+		return nil, errInvalidLoginState
+	}
+
+	// Without the binding anyone could be logged into the account of an attacker, by being sent
+	// to a callback the attacker got from the provider
+	if payload.Binding == "" || subtle.ConstantTimeCompare([]byte(utils.HashToken(payload.Binding)), []byte(state.BindingHash)) != 1 {
+		return nil, errLoginOtherClient
+	}
+
+	identity, err := provider.Exchange(ctx, payload.Code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		utils.TextLogger.Error("external login failed", "provider", providerName, "error", err)
+		return nil, errExternalLoginFailed
+	}
+	identity.Provider = providerName
+
+	user, err := s.externalUser(ctx, *identity)
+	if err != nil {
+		return nil, err
+	}
+	event.ActorId = user.ID
+
+	if user.EmailVerifiedAt == nil {
+		return nil, errEmailNotVerified
+	}
+
+	return s.completeLogin(ctx, *user)
+}
+
+// externalUser returns the user an external identity belongs to, linking the identity to a
+// user first when it was never used before
+func (s UserService) externalUser(ctx context.Context, identity dto.ExternalIdentityDto) (*domain.User, error) {
+	linked, err := s.identityPort.FindExternalIdentity(ctx, identity.Provider, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if linked != nil {
+		err := s.identityPort.UpdateExternalIdentity(ctx, linked.ID, map[string]interface{}{"last_login_at": now})
+		if err != nil {
+			return nil, err
+		}
+
+		return s.userPort.FindUser(ctx, dto.FindUserDto{ID: linked.UserId})
+	}
+
+	email := strings.TrimSpace(identity.Email)
+	if email == "" {
+		return nil, errIdentityWithoutEmail
+	}
+
+	link := domain.ExternalIdentity{
+		Provider:    identity.Provider,
+		Subject:     identity.Subject,
+		Email:       email,
+		LastLoginAt: &now,
+	}
+
+	existing, err := s.userPort.FindUser(ctx, dto.FindUserDto{Email: email})
+	if err == nil && existing.ID != "" {
+		// Anyone can claim an address at a provider that does not check it, so only a verified
+		// address is proof the account is theirs
+		if !identity.EmailVerified {
+			return nil, errEmailNotConfirmed
+		}
+
+		// An unverified account may have been registered by someone else before the owner of the
+		// address showed up, and its password would keep working for them after the link
+		if existing.EmailVerifiedAt == nil {
+			utils.TextLogger.Warn("external identity matches an unverified account", "user", existing.ID, "provider", identity.Provider)
+			return nil, errAccountNotVerified
+		}
+
+		link.UserId = existing.ID
+		if err := s.identityPort.CreateExternalIdentity(ctx, link); err != nil {
+			return nil, err
+		}
+
+		utils.TextLogger.Info("external identity linked", "user", existing.ID, "provider", identity.Provider)
+		return existing, nil
+	}
+
+	return s.createExternalUser(ctx, identity, link)
+}
+
+// createExternalUser registers a user for an external identity. The account gets a random
+// password, which the user can replace by resetting it, and the email address has to be
+// verified like on a normal registration unless the provider already did.
+func (s UserService) createExternalUser(ctx context.Context, identity dto.ExternalIdentityDto, link domain.ExternalIdentity) (*domain.User, error) {
+	password, err := s.passwordService.HashPassword(utils.GenerateSecureToken(32))
+	if err != nil {
+		return nil, err
+	}
+
+	fullName := strings.TrimSpace(identity.FullName)
+	if fullName == "" {
+		fullName = strings.Split(link.Email, "@")[0]
+	}
+
+	userData := domain.User{
+		FullName: fullName,
+		Email:    link.Email,
+		Password: domain.Password{Value: password},
+	}
+	if identity.EmailVerified {
+		userData.EmailVerifiedAt = link.LastLoginAt
+	}
+
+	var user *domain.User
+	err = s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		created, err := s.userPort.CreateUser(ctx, userData)
+		if err != nil {
+			return err
+		}
+
+		link.UserId = created.ID
+		if err := s.identityPort.CreateExternalIdentity(ctx, link); err != nil {
+			return err
+		}
+
+		if created.EmailVerifiedAt == nil {
+			if err := s.sendCode(ctx, *created, "email-verification"); err != nil {
+				return err
+			}
+		}
+
+		user = created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	utils.TextLogger.Info("user registered with an external identity", "user", user.ID, "provider", identity.Provider)
+	return user, nil
+}
+
+// codeChallenge derives the S256 PKCE code challenge sent to the provider from a code verifier
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// MagicLinkTTL is how long a login link sent by email can be used, unless configured otherwise
+const MagicLinkTTL = 10 * time.Minute
+
+var (
+	errInvalidMagicLink     = errors.New("this login link is invalid, has expired or was already used")
+	errMagicLinkOtherClient = errors.New("this login link was requested from another browser, open it there or request a new one")
+)
+
+// The [RequestMagicLink] usecase emails a user a link that logs them in without their password.
+// The link is signed, can only be used once, and only by the client holding binding, a secret
+// handed to the client that asked for it. The caller is never told whether a link was sent, so
+// unknown addresses, requests during the resend cooldown and failures only get logged.
+func (s UserService) RequestMagicLink(ctx context.Context, payload dto.EmailDto, binding string) error {
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{Email: payload.Email})
+	if err != nil || user.ID == "" {
+		utils.TextLogger.Info("login link requested for an unknown email address")
+		return nil
+	}
+
+	if err := checkAccountStatus(*user); err != nil {
+		utils.TextLogger.Info("login link refused", "user", user.ID, "error", err)
+		return nil
+	}
+
+	if err := s.sendMagicLink(ctx, *user, binding); err != nil {
+		utils.TextLogger.Error("unable to send login link", "user", user.ID, "error", err)
+	}
+
+	return nil
+}
+
+// sendMagicLink emails a user a new login link bound to binding
+func (s UserService) sendMagicLink(ctx context.Context, user domain.User, binding string) error {
+	code, err := s.verificationService.GenerateCode(ctx, dto.VerificationDto{UserID: user.ID, Type: "magic-link", SentTo: user.Email})
+	if err != nil {
+		return err
+	}
+
+	// The link lasts as long as its code, whose TTL is configurable
+	verification, err := s.verificationPort.FindCode(ctx, dto.VerificationDto{UserID: user.ID, Type: "magic-link"})
+	if err != nil {
+		return err
+	}
+	expiresAt := *verification.ExpiresAt
+
+	token, err := s.tokenService.CreateLinkToken(dto.LinkTokenDto{
+		Purpose:   "magic-link",
+		UserID:    user.ID,
+		Code:      code,
+		Binding:   utils.HashToken(binding),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(ctx, dto.MailDto{
+		To:       user.Email,
+		Template: "magic-link",
+		Data: map[string]interface{}{
+			"Name":    user.FullName,
+			"Link":    s.magicLinkURL + "?token=" + url.QueryEscape(token),
+			"Minutes": int(time.Until(expiresAt).Round(time.Minute).Minutes()),
+		},
+	})
+}
+
+// The [CompleteMagicLink] usecase logs a user in with the link they were emailed. Following the
+// link proves the user owns their email address, so it is marked verified if it was not yet.
+func (s UserService) CompleteMagicLink(ctx context.Context, payload dto.MagicLinkCallbackDto) (response *dto.LoginResponse, err error) {
+	event := domain.AuditEvent{Action: domain.AuditMagicLinkLogin}
+	defer func() { s.auditLogin(ctx, event, response, err) }()
+
+	link, err := s.tokenService.VerifyLinkToken(payload.Token, "magic-link")
+	if err != nil {
+		utils.TextLogger.Error("invalid login link", "error", err)
+		return nil, errInvalidMagicLink
+	}
+	event.ActorId = link.UserID
+
+	// The binding is checked first so a link opened elsewhere does not use up its attempts
+	if payload.Binding == "" || subtle.ConstantTimeCompare([]byte(utils.HashToken(payload.Binding)), []byte(link.Binding)) != 1 {
+		return nil, errMagicLinkOtherClient
+	}
+
+	verification, err := s.verificationService.VerifyCode(ctx, dto.VerificationDto{
+		UserID: link.UserID,
+		Code:   link.Code,
+		Type:   "magic-link",
+	})
+	if err != nil {
+		return nil, errInvalidMagicLink
+	}
+
+	if err := s.verificationPort.DeleteCode(ctx, verification.ID); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: link.UserID})
+	if err != nil {
+		return nil, err
+	}
+
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		if err := s.userPort.UpdateUser(ctx, user.ID, map[string]interface{}{"email_verified_at": now}); err != nil {
+			return nil, err
+		}
+		user.EmailVerifiedAt = &now
+	}
+
+	return s.completeLogin(ctx, *user)
+}
+
+// magicLinkURL is where login links point to: the configured magic link URL, or the callback
+// route of the server
+func magicLinkURL(server config.ServerConfig) string {
+	if server.MagicLinkURL != "" {
+		return server.MagicLinkURL
+	}
+
+	return strings.TrimSuffix(server.URL, "/") + "/api/v1/auth/magic-link/callback"
+}
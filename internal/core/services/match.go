@@ -0,0 +1,261 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+const (
+	maxKeywords   = 30
+	maxPhraseSize = 3
+)
+
+// Words that carry no meaning on their own, including the filler that most job descriptions share
+var stopWords = toSet(strings.Fields(`
+	a about above across after again against all also am an and any are as at be because been before being
+	below between both but by can could did do does doing down during each either else etc every few for from
+	further had has have having he her here hers him his how i if in into is it its itself just like may me
+	might more most must my no nor not now of off on once only or other our ours out over own per same she
+	should so some such than that the their theirs them then there these they this those through to too under
+	until up upon us very via was we were what when where which while who whom why will with within without
+	would you your yours
+	ability able apply applicant applicants benefit benefits bonus candidate candidates company competitive day days
+	description environment equal excellent experience experienced expertise familiar familiarity good great
+	ideal ideally including join job key knowledge least looking minimum new offer opportunity opportunities
+	plus position preferred proven qualifications related required requirement requirements responsibilities
+	responsible role roles salary seeking skill skills strong team teams understanding using well work working year years
+	build building collaborate create creating deliver design designing develop developing drive ensure help
+	implement maintain maintaining manage own run running support write writing
+`))
+
+type MatchService struct {
+}
+
+func NewMatchService() *MatchService {
+	return &MatchService{}
+}
+
+// The [Match] usecase simulates how an applicant tracking system compares a resume against a job description.
+// Keywords are extracted from the job description and looked up in the skills, summary and roles of the resume.
+func (s MatchService) Match(resume domain.Resume, jobDescription string) dto.ResumeMatchDto {
+	keywords := extractKeywords(jobDescription)
+	terms := resumeTerms(resume)
+
+	result := dto.ResumeMatchDto{
+		ResumeId:        resume.ID,
+		MatchedKeywords: []string{},
+		MissingKeywords: []string{},
+	}
+
+	var total, matched int
+	for _, keyword := range keywords {
+		total += keyword.weight
+		if terms[termKey(strings.Fields(keyword.term))] {
+			matched += keyword.weight
+			result.MatchedKeywords = append(result.MatchedKeywords, keyword.term)
+		} else {
+			result.MissingKeywords = append(result.MissingKeywords, keyword.term)
+		}
+	}
+
+	if total > 0 {
+		result.MatchPercentage = int(math.Round(float64(matched) * 100 / float64(total)))
+	}
+
+	return result
+}
+
+type keyword struct {
+	term   string
+	weight int
+}
+
+// extractKeywords ranks the meaningful words and phrases of a text by how often they appear.
+// Phrases are only kept when they are repeated, and the words that make them up are then no
+// longer counted on their own so that "machine learning" does not also yield "machine".
+func extractKeywords(text string) []keyword {
+	counts := make(map[string]int)
+	display := make(map[string]string)
+	var phrases []string
+
+	for _, run := range contentRuns(text) {
+		for size := 1; size <= maxPhraseSize; size++ {
+			for i := 0; i+size <= len(run); i++ {
+				key := termKey(run[i : i+size])
+				if _, seen := display[key]; !seen {
+					display[key] = strings.Join(run[i:i+size], " ")
+					if size > 1 {
+						phrases = append(phrases, key)
+					}
+				}
+				counts[key]++
+			}
+		}
+	}
+
+	// Longest phrases claim their words first
+	sort.SliceStable(phrases, func(i, j int) bool {
+		return len(strings.Fields(phrases[i])) > len(strings.Fields(phrases[j]))
+	})
+
+	for _, phrase := range phrases {
+		count := counts[phrase]
+		if count < 2 {
+			delete(counts, phrase)
+			continue
+		}
+
+		words := strings.Fields(phrase)
+		for size := 1; size < len(words); size++ {
+			for i := 0; i+size <= len(words); i++ {
+				key := strings.Join(words[i:i+size], " ")
+				counts[key] -= count
+				if counts[key] <= 0 {
+					delete(counts, key)
+				}
+			}
+		}
+	}
+
+	keywords := make([]keyword, 0, len(counts))
+	for key, count := range counts {
+		keywords = append(keywords, keyword{term: display[key], weight: count})
+	}
+
+	sort.Slice(keywords, func(i, j int) bool {
+		if keywords[i].weight != keywords[j].weight {
+			return keywords[i].weight > keywords[j].weight
+		}
+		return keywords[i].term < keywords[j].term
+	})
+
+	if len(keywords) > maxKeywords {
+		keywords = keywords[:maxKeywords]
+	}
+
+	return keywords
+}
+
+// resumeTerms collects the key of every word and phrase that appears in the parts of the resume an ATS would scan
+func resumeTerms(resume domain.Resume) map[string]bool {
+	texts := []string{resume.Summary}
+	texts = append(texts, resume.Skills...)
+	for _, experience := range resume.Experiences {
+		texts = append(texts, experience.Role)
+	}
+
+	terms := make(map[string]bool)
+	for _, text := range texts {
+		for _, run := range contentRuns(text) {
+			for size := 1; size <= maxPhraseSize; size++ {
+				for i := 0; i+size <= len(run); i++ {
+					terms[termKey(run[i:i+size])] = true
+				}
+			}
+		}
+	}
+
+	// Skills are matched as a whole too, even when they are longer than a phrase
+	for _, skill := range resume.Skills {
+		if words := tokenize(skill); len(words) > 0 {
+			terms[termKey(words)] = true
+		}
+	}
+
+	return terms
+}
+
+// termKey normalises a phrase so that simple plurals match, e.g. "databases" and "database"
+func termKey(words []string) string {
+	stems := make([]string, len(words))
+	for i, word := range words {
+		stems[i] = stem(word)
+	}
+	return strings.Join(stems, " ")
+}
+
+// contentRuns splits the text into runs of consecutive meaningful tokens.
+// Stop words and sentence punctuation end a run so phrases never span them.
+func contentRuns(text string) [][]string {
+	var runs [][]string
+	var current []string
+
+	flush := func() {
+		if len(current) > 0 {
+			runs = append(runs, current)
+			current = nil
+		}
+	}
+
+	for _, clause := range strings.FieldsFunc(text, isClauseBreak) {
+		for _, token := range tokenize(clause) {
+			if stopWords[token] || isNumeric(token) {
+				flush()
+				continue
+			}
+			current = append(current, token)
+		}
+		flush()
+	}
+
+	return runs
+}
+
+// tokenize lower cases the text and splits it into words, keeping the symbols
+// used by technology names such as "c++", "c#" or "node.js"
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#' && r != '.'
+	})
+
+	var tokens []string
+	for _, field := range fields {
+		field = strings.Trim(field, ".")
+		if len(field) < 2 && field != "c" && field != "r" {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+
+	return tokens
+}
+
+func stem(token string) string {
+	if len(token) <= 4 || strings.ContainsAny(token, "+#.") || !strings.HasSuffix(token, "s") {
+		return token
+	}
+
+	for _, suffix := range []string{"ss", "us", "is"} {
+		if strings.HasSuffix(token, suffix) {
+			return token
+		}
+	}
+
+	return strings.TrimSuffix(token, "s")
+}
+
+func isClauseBreak(r rune) bool {
+	return strings.ContainsRune(",;:!?()[]{}\"\n\r•|/", r)
+}
+
+func isNumeric(token string) bool {
+	for _, r := range token {
+		if !unicode.IsDigit(r) && r != '+' && r != '.' {
+			return false
+		}
+	}
+	return true
+}
+
+func toSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
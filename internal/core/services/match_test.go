@@ -0,0 +1,61 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stretchr/testify/assert"
+)
+
+const jobDescription = `We are looking for a Backend Engineer with strong experience in Go and PostgreSQL.
+You will design distributed systems, build REST APIs and run services on Kubernetes.
+Experience with distributed systems and machine learning pipelines is a plus.
+Requirements: 5+ years of experience, Go, Kubernetes, machine learning.`
+
+func TestMatchReportsMatchedAndMissingKeywords(t *testing.T) {
+	matcher := services.NewMatchService()
+
+	result := matcher.Match(domain.Resume{
+		Summary: "Backend engineer building REST APIs",
+		Skills:  []string{"Go", "PostgreSQL", "Distributed Systems"},
+		Experiences: []domain.WorkExperience{
+			{Role: "Senior Backend Engineer"},
+		},
+	}, jobDescription)
+
+	assert.Contains(t, result.MatchedKeywords, "go")
+	assert.Contains(t, result.MatchedKeywords, "postgresql")
+	assert.Contains(t, result.MatchedKeywords, "distributed systems")
+	assert.Contains(t, result.MatchedKeywords, "backend")
+	assert.Contains(t, result.MissingKeywords, "kubernetes")
+	assert.Contains(t, result.MissingKeywords, "machine learning")
+
+	assert.NotContains(t, result.MatchedKeywords, "experience")
+	assert.NotContains(t, result.MissingKeywords, "machine", "words of a repeated phrase are not counted twice")
+	assert.NotContains(t, result.MissingKeywords, "5+")
+
+	assert.Greater(t, result.MatchPercentage, 0)
+	assert.Less(t, result.MatchPercentage, 100)
+}
+
+func TestMatchNormalisesPlurals(t *testing.T) {
+	matcher := services.NewMatchService()
+
+	result := matcher.Match(domain.Resume{
+		Skills: []string{"Relational Database", "Microservice"},
+	}, "Design relational databases and maintain microservices.")
+
+	assert.Equal(t, 100, result.MatchPercentage)
+	assert.Empty(t, result.MissingKeywords)
+}
+
+func TestMatchWithoutKeywords(t *testing.T) {
+	matcher := services.NewMatchService()
+
+	result := matcher.Match(domain.Resume{Skills: []string{"Go"}}, "We are looking for a candidate with experience.")
+
+	assert.Equal(t, 0, result.MatchPercentage)
+	assert.Empty(t, result.MatchedKeywords)
+	assert.Empty(t, result.MissingKeywords)
+}
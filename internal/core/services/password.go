@@ -1,7 +1,24 @@
 package services
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/stivo-m/vise-resume/internal/config"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordService hashes passwords with bcrypt. It is kept to verify the hashes made before
+// passwords were hashed with Argon2id.
 type PasswordService struct {
 }
 
@@ -21,3 +38,235 @@ func (s PasswordService) VerifyPassword(password string, encryptedPassword strin
 	err := bcrypt.CompareHashAndPassword([]byte(encryptedPassword), []byte(password))
 	return err == nil
 }
+
+// NeedsRehash tells whether a hash was made with a lower cost than the current one
+func (s PasswordService) NeedsRehash(encryptedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(encryptedPassword))
+	return err != nil || cost < bcrypt.DefaultCost
+}
+
+// Argon2Params are the cost parameters of Argon2id. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params follows the OWASP recommendation of 19 MiB of memory and two iterations
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{Memory: 19 * 1024, Iterations: 2, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+}
+
+// Pepper is a secret mixed into every password before it is hashed. It is kept out of the
+// database, so hashes stolen from it can not be cracked without it as well.
+type Pepper struct {
+	ID     string
+	Secret []byte
+}
+
+var pepperID = regexp.MustCompile(`^[A-Za-z0-9]{1,8}$`)
+
+// Argon2PasswordService hashes passwords with Argon2id into PHC strings, e.g.
+// $argon2id$v=19$m=19456,t=2,p=1$<salt>$<hash>. Peppered hashes name their pepper with a keyid
+// parameter. Legacy bcrypt hashes are still verified, and reported by [NeedsRehash] along with
+// hashes made with other parameters or pepper, so they can be upgraded when the user logs in.
+type Argon2PasswordService struct {
+	params  Argon2Params
+	peppers map[string][]byte
+	pepper  Pepper
+	legacy  PasswordService
+}
+
+// NewArgon2PasswordService creates a password service hashing with params and the last of the
+// peppers, if any. The other peppers only verify the hashes made with them.
+func NewArgon2PasswordService(params Argon2Params, peppers ...Pepper) (*Argon2PasswordService, error) {
+	if params.Memory < 8*uint32(params.Parallelism) || params.Iterations == 0 || params.Parallelism == 0 {
+		return nil, errors.New("argon2 needs at least one iteration, one thread and 8 KiB of memory per thread")
+	}
+	if params.SaltLength < 8 || params.KeyLength < 16 {
+		return nil, errors.New("argon2 salts need at least 8 bytes and keys at least 16 bytes")
+	}
+
+	service := &Argon2PasswordService{params: params, peppers: map[string][]byte{}}
+	for _, pepper := range peppers {
+		if !pepperID.MatchString(pepper.ID) {
+			return nil, fmt.Errorf("pepper id %q may only use up to 8 letters and digits", pepper.ID)
+		}
+		if len(pepper.Secret) < 16 {
+			return nil, fmt.Errorf("pepper %s needs a secret of at least 16 bytes", pepper.ID)
+		}
+		if _, ok := service.peppers[pepper.ID]; ok {
+			return nil, fmt.Errorf("pepper %s is defined twice", pepper.ID)
+		}
+
+		service.peppers[pepper.ID] = pepper.Secret
+		service.pepper = pepper
+	}
+
+	return service, nil
+}
+
+// NewPasswordServiceFromConfig creates an Argon2id password service with the configured cost,
+// the settings left at zero keeping their default, and peppers given as id:secret pairs. New
+// hashes use the last pepper.
+func NewPasswordServiceFromConfig(settings config.PasswordConfig) (*Argon2PasswordService, error) {
+	params := DefaultArgon2Params()
+	if settings.Argon2Memory != 0 {
+		params.Memory = settings.Argon2Memory
+	}
+	if settings.Argon2Iterations != 0 {
+		params.Iterations = settings.Argon2Iterations
+	}
+	if settings.Argon2Parallelism != 0 {
+		params.Parallelism = settings.Argon2Parallelism
+	}
+
+	var peppers []Pepper
+	for _, entry := range settings.Peppers {
+		id, secret, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.New("peppers have to be given as id:secret pairs")
+		}
+		peppers = append(peppers, Pepper{ID: id, Secret: []byte(secret)})
+	}
+
+	return NewArgon2PasswordService(params, peppers...)
+}
+
+func (s Argon2PasswordService) HashPassword(password string) (string, error) {
+	salt := make([]byte, s.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	hash := argon2.IDKey(s.peppered(password, s.pepper.Secret), salt, s.params.Iterations, s.params.Memory, s.params.Parallelism, s.params.KeyLength)
+
+	settings := fmt.Sprintf("m=%d,t=%d,p=%d", s.params.Memory, s.params.Iterations, s.params.Parallelism)
+	if s.pepper.ID != "" {
+		settings += ",keyid=" + s.pepper.ID
+	}
+
+	return fmt.Sprintf("$argon2id$v=%d$%s$%s$%s",
+		argon2.Version,
+		settings,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(hash),
+	), nil
+}
+
+func (s Argon2PasswordService) VerifyPassword(password string, encryptedPassword string) bool {
+	if !strings.HasPrefix(encryptedPassword, "$argon2id$") {
+		return s.legacy.VerifyPassword(password, encryptedPassword)
+	}
+
+	hash, err := parseArgon2Hash(encryptedPassword)
+	if err != nil {
+		return false
+	}
+
+	var secret []byte
+	if hash.keyID != "" {
+		known, ok := s.peppers[hash.keyID]
+		if !ok {
+			return false
+		}
+		secret = known
+	}
+
+	derived := argon2.IDKey(s.peppered(password, secret), hash.salt, hash.params.Iterations, hash.params.Memory, hash.params.Parallelism, uint32(len(hash.key)))
+	return subtle.ConstantTimeCompare(derived, hash.key) == 1
+}
+
+// NeedsRehash tells whether a hash was made with bcrypt, or with other parameters or another
+// pepper than the ones new hashes are made with
+func (s Argon2PasswordService) NeedsRehash(encryptedPassword string) bool {
+	hash, err := parseArgon2Hash(encryptedPassword)
+	if err != nil {
+		return true
+	}
+
+	current := s.params
+	return hash.params.Memory != current.Memory ||
+		hash.params.Iterations != current.Iterations ||
+		hash.params.Parallelism != current.Parallelism ||
+		uint32(len(hash.salt)) != current.SaltLength ||
+		uint32(len(hash.key)) != current.KeyLength ||
+		hash.keyID != s.pepper.ID
+}
+
+// peppered mixes the pepper into a password with HMAC-SHA256, or leaves it alone without one
+func (s Argon2PasswordService) peppered(password string, secret []byte) []byte {
+	if len(secret) == 0 {
+		return []byte(password)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(password))
+	return mac.Sum(nil)
+}
+
+type argon2Hash struct {
+	params Argon2Params
+	keyID  string
+	salt   []byte
+	key    []byte
+}
+
+// parseArgon2Hash reads an Argon2id hash in the PHC string format
+func parseArgon2Hash(encoded string) (*argon2Hash, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return nil, errors.New("not an argon2id hash")
+	}
+
+	if parts[2] != fmt.Sprintf("v=%d", argon2.Version) {
+		return nil, fmt.Errorf("unsupported argon2 version %s", parts[2])
+	}
+
+	hash := &argon2Hash{}
+	for _, setting := range strings.Split(parts[3], ",") {
+		name, value, _ := strings.Cut(setting, "=")
+		if name == "keyid" {
+			hash.keyID = value
+			continue
+		}
+
+		number, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid argon2 parameter %s", setting)
+		}
+
+		switch name {
+		case "m":
+			hash.params.Memory = uint32(number)
+		case "t":
+			hash.params.Iterations = uint32(number)
+		case "p":
+			if number > 255 {
+				return nil, fmt.Errorf("invalid argon2 parameter %s", setting)
+			}
+			hash.params.Parallelism = uint8(number)
+		default:
+			return nil, fmt.Errorf("unknown argon2 parameter %s", name)
+		}
+	}
+
+	if hash.params.Memory == 0 || hash.params.Iterations == 0 || hash.params.Parallelism == 0 {
+		return nil, errors.New("argon2 hash is missing a parameter")
+	}
+
+	var err error
+	if hash.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return nil, err
+	}
+	if hash.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return nil, err
+	}
+	if len(hash.salt) == 0 || len(hash.key) == 0 {
+		return nil, errors.New("argon2 hash is missing its salt or key")
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,108 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgon2PasswordHashes(t *testing.T) {
+	service, err := services.NewArgon2PasswordService(services.DefaultArgon2Params())
+	assert.NoError(t, err)
+
+	hash, err := service.HashPassword("plum-Quartz-41-river")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=2,p=1$"), hash)
+	assert.Len(t, strings.Split(hash, "$"), 6)
+
+	other, err := service.HashPassword("plum-Quartz-41-river")
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, other, "Expected every hash to have its own salt")
+
+	assert.True(t, service.VerifyPassword("plum-Quartz-41-river", hash))
+	assert.False(t, service.VerifyPassword("plum-quartz-41-river", hash))
+	assert.False(t, service.NeedsRehash(hash))
+
+	assert.False(t, service.VerifyPassword("plum-Quartz-41-river", "$argon2id$v=19$m=19456,t=2,p=1$broken"))
+	assert.False(t, service.VerifyPassword("plum-Quartz-41-river", ""))
+}
+
+func TestArgon2VerifiesLegacyBcryptHashes(t *testing.T) {
+	service, err := services.NewArgon2PasswordService(services.DefaultArgon2Params())
+	assert.NoError(t, err)
+
+	legacy, err := services.NewPasswordService().HashPassword("password")
+	assert.NoError(t, err)
+
+	assert.True(t, service.VerifyPassword("password", legacy))
+	assert.False(t, service.VerifyPassword("wrong", legacy))
+	assert.True(t, service.NeedsRehash(legacy))
+}
+
+func TestArgon2RehashWhenParametersChange(t *testing.T) {
+	weak := services.DefaultArgon2Params()
+	weak.Memory = 8 * 1024
+	before, err := services.NewArgon2PasswordService(weak)
+	assert.NoError(t, err)
+	hash, err := before.HashPassword("password")
+	assert.NoError(t, err)
+
+	after, err := services.NewArgon2PasswordService(services.DefaultArgon2Params())
+	assert.NoError(t, err)
+	assert.True(t, after.VerifyPassword("password", hash), "Expected hashes to be verified with their own parameters")
+	assert.True(t, after.NeedsRehash(hash))
+
+	_, err = services.NewArgon2PasswordService(services.Argon2Params{})
+	assert.Error(t, err)
+}
+
+func TestArgon2Peppers(t *testing.T) {
+	unpeppered, err := services.NewArgon2PasswordService(services.DefaultArgon2Params())
+	assert.NoError(t, err)
+	plain, err := unpeppered.HashPassword("password")
+	assert.NoError(t, err)
+
+	first := services.Pepper{ID: "2024", Secret: []byte("first-pepper-secret")}
+	second := services.Pepper{ID: "2025", Secret: []byte("second-pepper-secret")}
+	service, err := services.NewArgon2PasswordService(services.DefaultArgon2Params(), first)
+	assert.NoError(t, err)
+
+	peppered, err := service.HashPassword("password")
+	assert.NoError(t, err)
+	assert.Contains(t, peppered, ",keyid=2024$")
+	assert.True(t, service.VerifyPassword("password", peppered))
+	assert.False(t, unpeppered.VerifyPassword("password", peppered), "Expected the pepper to be needed")
+
+	// Hashes made before the pepper was added keep working until they are rehashed
+	assert.True(t, service.VerifyPassword("password", plain))
+	assert.True(t, service.NeedsRehash(plain))
+
+	rotated, err := services.NewArgon2PasswordService(services.DefaultArgon2Params(), first, second)
+	assert.NoError(t, err)
+	assert.True(t, rotated.VerifyPassword("password", peppered))
+	assert.True(t, rotated.NeedsRehash(peppered))
+
+	forged := strings.Replace(peppered, "keyid=2024", "keyid=2023", 1)
+	assert.False(t, rotated.VerifyPassword("password", forged))
+
+	_, err = services.NewArgon2PasswordService(services.DefaultArgon2Params(), services.Pepper{ID: "2024", Secret: []byte("short")})
+	assert.Error(t, err)
+}
+
+func TestPasswordServiceFromConfig(t *testing.T) {
+	service, err := services.NewPasswordServiceFromConfig(config.PasswordConfig{
+		Argon2Memory:     12288,
+		Argon2Iterations: 3,
+		Peppers:          []string{"old:an-old-pepper-secret", "new:a-new-pepper-secret"},
+	})
+	assert.NoError(t, err)
+	hash, err := service.HashPassword("password")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=12288,t=3,p=1,keyid=new$"), hash)
+
+	_, err = services.NewPasswordServiceFromConfig(config.PasswordConfig{Peppers: []string{"missing-secret"}})
+	assert.Error(t, err)
+}
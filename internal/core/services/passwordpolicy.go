@@ -0,0 +1,127 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+)
+
+// PasswordPolicy checks the passwords users choose when they register, reset or change it
+type PasswordPolicy struct {
+	// MinLength is the number of characters a password needs at least
+	MinLength int
+	// MinEntropy is the estimated number of bits it takes to guess a password
+	MinEntropy float64
+	breached   ports.BreachedPasswords
+}
+
+func NewPasswordPolicy(minLength int, minEntropy float64, breached ports.BreachedPasswords) *PasswordPolicy {
+	return &PasswordPolicy{MinLength: minLength, MinEntropy: minEntropy, breached: breached}
+}
+
+// DefaultPasswordPolicy asks for 8 characters and 40 bits of entropy, which nine random lower
+// case letters or a mix of eight letters, digits and symbols give
+func DefaultPasswordPolicy(breached ports.BreachedPasswords) *PasswordPolicy {
+	return NewPasswordPolicy(8, 40, breached)
+}
+
+// Check returns a [domain.PasswordPolicyError] listing every rule the password breaks
+func (p PasswordPolicy) Check(password string, personal ...string) error {
+	var problems []string
+
+	if len([]rune(password)) < p.MinLength {
+		problems = append(problems, fmt.Sprintf("it must be at least %d characters long", p.MinLength))
+	} else if PasswordEntropy(password) < p.MinEntropy {
+		problems = append(problems, "it is too easy to guess, use a longer mix of words, numbers and symbols")
+	}
+
+	if containsPersonalValue(password, personal) {
+		problems = append(problems, "it must not contain your name or email address")
+	}
+
+	if p.breached != nil && p.breached.Contains(password) {
+		problems = append(problems, "it appears in a list of leaked passwords")
+	}
+
+	if len(problems) > 0 {
+		return &domain.PasswordPolicyError{Problems: problems}
+	}
+
+	return nil
+}
+
+// PasswordEntropy estimates the bits of entropy of a password from the kinds of characters it uses.
+// Characters repeating or following on from the previous one, like "aaa" or "abc", are not counted.
+func PasswordEntropy(password string) float64 {
+	var lower, upper, digit, symbol, other bool
+	length := 0
+	previous := rune(-1)
+
+	for _, char := range password {
+		switch {
+		case char < unicode.MaxASCII && unicode.IsLower(char):
+			lower = true
+		case char < unicode.MaxASCII && unicode.IsUpper(char):
+			upper = true
+		case unicode.IsDigit(char):
+			digit = true
+		case char < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+
+		if previous < 0 || (char != previous && char != previous+1 && char != previous-1) {
+			length++
+		}
+		previous = char
+	}
+
+	pool := 0
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.used {
+			pool += class.size
+		}
+	}
+
+	if pool == 0 {
+		return 0
+	}
+
+	return float64(length) * math.Log2(float64(pool))
+}
+
+// containsPersonalValue tells whether the password contains one of the values, or one of their
+// words like a first name. Only the local part of email addresses is considered.
+func containsPersonalValue(password string, values []string) bool {
+	password = strings.ToLower(password)
+
+	for _, value := range values {
+		value = strings.ToLower(value)
+		if at := strings.LastIndex(value, "@"); at >= 0 {
+			value = value[:at]
+		}
+
+		if len([]rune(value)) >= 3 && strings.Contains(password, value) {
+			return true
+		}
+
+		words := strings.FieldsFunc(value, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			if len([]rune(word)) >= 4 && strings.Contains(password, word) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
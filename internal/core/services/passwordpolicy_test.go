@@ -0,0 +1,48 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/breached"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordEntropy(t *testing.T) {
+	assert.Zero(t, services.PasswordEntropy(""))
+	assert.Less(t, services.PasswordEntropy("aaaaaaaaaaaa"), 10.0, "Expected repeated characters to count once")
+	assert.Less(t, services.PasswordEntropy("abcdefghijkl"), 10.0, "Expected sequences to count once")
+	assert.Less(t, services.PasswordEntropy("qwertyui"), 40.0)
+	assert.Greater(t, services.PasswordEntropy("qwertyuio"), 40.0)
+	assert.Greater(t, services.PasswordEntropy("Tr0ub4dor&3"), services.PasswordEntropy("troubadour"))
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	policy := services.DefaultPasswordPolicy(breached.NewBundledList())
+
+	assert.NoError(t, policy.Check("plum-Quartz-41-river", "jane.doe@example.com", "Jane Doe"))
+
+	tests := map[string]struct {
+		password string
+		problems int
+	}{
+		"too short":          {password: "x7#Lp", problems: 1},
+		"too easy to guess":  {password: "aaaabbbbcccc", problems: 1},
+		"breached":           {password: "iloveyou123", problems: 1},
+		"contains the name":  {password: "Janet-2024-rocks!", problems: 1},
+		"contains the email": {password: "jane.doe#2024", problems: 1},
+		"breached and short": {password: "123456", problems: 2},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := policy.Check(test.password, "jane.doe@example.com", "Jane Doe")
+
+			var policyErr *domain.PasswordPolicyError
+			assert.True(t, errors.As(err, &policyErr))
+			assert.Len(t, policyErr.Problems, test.problems, "%v", err)
+		})
+	}
+}
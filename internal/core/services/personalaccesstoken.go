@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// The [CreatePersonalAccessToken] usecase creates a token for scripts and integrations.
+// The token is only returned here; it is stored hashed and can not be shown again.
+func (s UserService) CreatePersonalAccessToken(ctx context.Context, id string, payload dto.CreatePersonalAccessTokenDto) (_ *dto.PersonalAccessTokenDto, err error) {
+	event := domain.AuditEvent{Action: domain.AuditTokenCreated, ActorId: id, Details: "token " + payload.Name}
+	defer func() { s.audit(ctx, event, err) }()
+
+	scopes := uniqueScopes(payload.Scopes)
+	if len(scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+
+	token := utils.PERSONAL_ACCESS_TOKEN_PREFIX + utils.GenerateSecureToken(32)
+	record, err := s.userPort.CreatePersonalAccessToken(ctx, domain.PersonalAccessToken{
+		UserId:    id,
+		Name:      payload.Name,
+		TokenHash: utils.HashToken(token),
+		Scopes:    strings.Join(scopes, " "),
+		ExpiresAt: time.Now().AddDate(0, 0, payload.ExpiresInDays),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := personalAccessTokenResponse(*record)
+	response.Token = token
+	return &response, nil
+}
+
+// The [ListPersonalAccessTokens] usecase lists the personal access tokens of a user, without their values
+func (s UserService) ListPersonalAccessTokens(ctx context.Context, id string) ([]dto.PersonalAccessTokenDto, error) {
+	tokens, err := s.userPort.FindPersonalAccessTokens(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.PersonalAccessTokenDto, 0, len(tokens))
+	for _, token := range tokens {
+		response = append(response, personalAccessTokenResponse(token))
+	}
+
+	return response, nil
+}
+
+// The [RevokePersonalAccessToken] usecase deletes one of the user's personal access tokens
+func (s UserService) RevokePersonalAccessToken(ctx context.Context, id string, tokenId string) (err error) {
+	event := domain.AuditEvent{Action: domain.AuditTokenRevoked, ActorId: id, Details: "token " + tokenId}
+	defer func() { s.audit(ctx, event, err) }()
+
+	if err := s.userPort.DeletePersonalAccessToken(ctx, id, tokenId); err != nil {
+		utils.TextLogger.Error("unable to revoke personal access token", "user", id, "error", err)
+		return errors.New("personal access token not found")
+	}
+
+	return nil
+}
+
+func personalAccessTokenResponse(token domain.PersonalAccessToken) dto.PersonalAccessTokenDto {
+	return dto.PersonalAccessTokenDto{
+		ID:         token.ID,
+		Name:       token.Name,
+		Scopes:     token.ScopeList(),
+		CreatedAt:  token.CreatedAt,
+		ExpiresAt:  token.ExpiresAt,
+		LastUsedAt: token.LastUsedAt,
+	}
+}
+
+func uniqueScopes(scopes []string) []string {
+	seen := map[string]bool{}
+	unique := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if scope != "" && !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+
+	sort.Strings(unique)
+	return unique
+}
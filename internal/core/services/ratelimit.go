@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+)
+
+// RateLimitPolicy describes how failed attempts are throttled. The first FreeAttempts failures
+// are not delayed, every further failure doubles the wait starting at BaseDelay, and reaching
+// MaxFailures locks the key for LockoutDuration. Failures are forgotten after Window without one.
+type RateLimitPolicy struct {
+	FreeAttempts    int
+	BaseDelay       time.Duration
+	MaxFailures     int
+	LockoutDuration time.Duration
+	Window          time.Duration
+}
+
+func DefaultRateLimitPolicy() RateLimitPolicy {
+	return RateLimitPolicy{
+		FreeAttempts:    5,
+		BaseDelay:       time.Second,
+		MaxFailures:     10,
+		LockoutDuration: 15 * time.Minute,
+		Window:          15 * time.Minute,
+	}
+}
+
+type RateLimitService struct {
+	store  ports.RateLimitStore
+	policy RateLimitPolicy
+	now    func() time.Time
+}
+
+func NewRateLimitService(store ports.RateLimitStore, policy RateLimitPolicy) *RateLimitService {
+	return NewRateLimitServiceWithClock(store, policy, time.Now)
+}
+
+// NewRateLimitServiceWithClock creates a rate limiter reading the time from now
+func NewRateLimitServiceWithClock(store ports.RateLimitStore, policy RateLimitPolicy, now func() time.Time) *RateLimitService {
+	return &RateLimitService{store: store, policy: policy, now: now}
+}
+
+// The [Allow] usecase returns how long the caller has to wait before trying again,
+// which is zero when none of the keys is currently locked
+func (s RateLimitService) Allow(ctx context.Context, keys []string) (time.Duration, error) {
+	now := s.now()
+	var wait time.Duration
+
+	for _, key := range keys {
+		state, err := s.store.Find(ctx, key)
+		if err != nil {
+			return 0, err
+		}
+
+		if state != nil && state.LockedUntil != nil && state.LockedUntil.After(now) {
+			wait = max(wait, state.LockedUntil.Sub(now))
+		}
+	}
+
+	return wait, nil
+}
+
+// The [RecordFailure] usecase counts a failed attempt against every key and locks
+// the keys that went past the free attempts
+func (s RateLimitService) RecordFailure(ctx context.Context, keys []string) error {
+	now := s.now()
+
+	for _, key := range keys {
+		state, err := s.store.Find(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		if state == nil || now.Sub(state.LastFailureAt) > s.policy.Window {
+			state = &domain.RateLimit{Key: key}
+		}
+
+		state.Failures++
+		state.LastFailureAt = now
+		state.LockedUntil = nil
+
+		if delay := s.delay(state.Failures); delay > 0 {
+			lockedUntil := now.Add(delay)
+			state.LockedUntil = &lockedUntil
+		}
+
+		if err := s.store.Save(ctx, *state); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// The [RecordSuccess] usecase clears the failures recorded against the keys
+func (s RateLimitService) RecordSuccess(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		if err := s.store.Delete(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s RateLimitService) delay(failures int) time.Duration {
+	if failures >= s.policy.MaxFailures {
+		return s.policy.LockoutDuration
+	}
+
+	if failures < s.policy.FreeAttempts {
+		return 0
+	}
+
+	delay := s.policy.BaseDelay
+	for i := s.policy.FreeAttempts; i < failures && delay < s.policy.LockoutDuration; i++ {
+		delay *= 2
+	}
+
+	return min(delay, s.policy.LockoutDuration)
+}
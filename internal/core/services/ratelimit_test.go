@@ -0,0 +1,114 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/database/repository"
+	"github.com/stivo-m/vise-resume/internal/adapters/ratelimit"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRateLimitPolicy = services.RateLimitPolicy{
+	FreeAttempts:    2,
+	BaseDelay:       time.Second,
+	MaxFailures:     5,
+	LockoutDuration: time.Hour,
+	Window:          10 * time.Minute,
+}
+
+// rateLimitStores returns every store implementation so each test runs against all of them
+func rateLimitStores(t *testing.T) map[string]ports.RateLimitStore {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err)
+
+	return map[string]ports.RateLimitStore{
+		"memory":   ratelimit.NewMemoryStore(),
+		"database": repository.NewRateLimitRepository(db),
+	}
+}
+
+func TestRateLimitBackoff(t *testing.T) {
+	for name, store := range rateLimitStores(t) {
+		t.Run(name, func(t *testing.T) {
+			clock := &testClock{now: time.Now().Truncate(time.Second)}
+			limiter := services.NewRateLimitServiceWithClock(store, testRateLimitPolicy, clock.Now)
+			ctx := context.Background()
+			keys := []string{"login:ip:127.0.0.1", "login:email:jane@example.com"}
+
+			assert.NoError(t, limiter.RecordFailure(ctx, keys))
+			wait, err := limiter.Allow(ctx, keys)
+			assert.NoError(t, err)
+			assert.Zero(t, wait, "Expected the free attempts not to be delayed")
+
+			for _, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+				assert.NoError(t, limiter.RecordFailure(ctx, keys))
+				wait, err = limiter.Allow(ctx, keys)
+				assert.NoError(t, err)
+				assert.Equal(t, expected, wait)
+			}
+
+			clock.now = clock.now.Add(4 * time.Second)
+			wait, err = limiter.Allow(ctx, keys)
+			assert.NoError(t, err)
+			assert.Zero(t, wait, "Expected the key to be released once the delay passed")
+		})
+	}
+}
+
+func TestRateLimitLockout(t *testing.T) {
+	for name, store := range rateLimitStores(t) {
+		t.Run(name, func(t *testing.T) {
+			clock := &testClock{now: time.Now().Truncate(time.Second)}
+			limiter := services.NewRateLimitServiceWithClock(store, testRateLimitPolicy, clock.Now)
+			ctx := context.Background()
+			keys := []string{"login:email:john@example.com"}
+
+			for i := 0; i < testRateLimitPolicy.MaxFailures; i++ {
+				assert.NoError(t, limiter.RecordFailure(ctx, keys))
+			}
+
+			wait, err := limiter.Allow(ctx, keys)
+			assert.NoError(t, err)
+			assert.Equal(t, time.Hour, wait)
+
+			// Only the locked key is throttled
+			wait, err = limiter.Allow(ctx, []string{"login:email:jane@example.com"})
+			assert.NoError(t, err)
+			assert.Zero(t, wait)
+		})
+	}
+}
+
+func TestRateLimitFailuresAreForgotten(t *testing.T) {
+	for name, store := range rateLimitStores(t) {
+		t.Run(name, func(t *testing.T) {
+			clock := &testClock{now: time.Now().Truncate(time.Second)}
+			limiter := services.NewRateLimitServiceWithClock(store, testRateLimitPolicy, clock.Now)
+			ctx := context.Background()
+			keys := []string{"verify:email:window@example.com"}
+
+			assert.NoError(t, limiter.RecordFailure(ctx, keys))
+			assert.NoError(t, limiter.RecordFailure(ctx, keys))
+
+			// Failures outside the window start a new count
+			clock.now = clock.now.Add(testRateLimitPolicy.Window + time.Second)
+			assert.NoError(t, limiter.RecordFailure(ctx, keys))
+			wait, err := limiter.Allow(ctx, keys)
+			assert.NoError(t, err)
+			assert.Zero(t, wait)
+
+			// A success clears the failures straight away
+			assert.NoError(t, limiter.RecordFailure(ctx, keys))
+			assert.NoError(t, limiter.RecordSuccess(ctx, keys))
+			assert.NoError(t, limiter.RecordFailure(ctx, keys))
+			wait, err = limiter.Allow(ctx, keys)
+			assert.NoError(t, err)
+			assert.Zero(t, wait)
+		})
+	}
+}
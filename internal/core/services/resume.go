@@ -2,22 +2,49 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/lib/pq"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
 	"github.com/stivo-m/vise-resume/internal/core/dto"
 	"github.com/stivo-m/vise-resume/internal/core/ports"
 	"github.com/stivo-m/vise-resume/internal/core/utils"
 )
 
+var errEndDateBeforeStartDate = errors.New("the end date cannot be before the start date")
+
 type ResumeService struct {
-	resumePort ports.ResumePort
+	unitOfWork   ports.UnitOfWork
+	resumePort   ports.ResumePort
+	templatePort ports.TemplatePort
+	scorer       ports.ResumeScorer
+	matcher      ports.ResumeMatcher
+	renderers    map[string]ports.ResumeRenderer
 }
 
 func NewResumeService(
+	unitOfWork ports.UnitOfWork,
 	resumePort ports.ResumePort,
+	templatePort ports.TemplatePort,
+	scorer ports.ResumeScorer,
+	matcher ports.ResumeMatcher,
+	renderers []ports.ResumeRenderer,
 
 ) *ResumeService {
+	formats := make(map[string]ports.ResumeRenderer)
+	for _, renderer := range renderers {
+		formats[renderer.Format()] = renderer
+	}
+
 	return &ResumeService{
-		resumePort: resumePort,
+		unitOfWork:   unitOfWork,
+		resumePort:   resumePort,
+		templatePort: templatePort,
+		scorer:       scorer,
+		matcher:      matcher,
+		renderers:    formats,
 	}
 }
 
@@ -27,31 +54,49 @@ func (s ResumeService) CreateResume(ctx context.Context, payload dto.CreateResum
 		return nil, err
 	}
 
-	resume, err := s.resumePort.CreateResume(ctx, dto.ResumeDto{
-		UserId:  userId.String(),
-		Summary: payload.Summary,
-		Skills:  payload.Skills,
-	})
-
-	if err != nil {
-		return nil, err
+	if payload.TemplateId != "" && !s.templatePort.HasTemplate(payload.TemplateId) {
+		return nil, fmt.Errorf("template %s does not exist", payload.TemplateId)
 	}
 
-	err = s.resumePort.AddEducation(ctx, resume.ID, payload.Education)
-	if err != nil {
-		return nil, err
-	}
+	var resume *domain.Resume
+	var score int
+	err = s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		created, err := s.resumePort.CreateResume(ctx, dto.ResumeDto{
+			UserId:     userId.String(),
+			Summary:    payload.Summary,
+			Skills:     payload.Skills,
+			TemplateId: payload.TemplateId,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = s.resumePort.AddEducation(ctx, created.ID, payload.Education)
+		if err != nil {
+			return err
+		}
+
+		err = s.resumePort.AddWorkExperiences(ctx, created.ID, payload.Experiences)
+		if err != nil {
+			return err
+		}
+
+		resume = created
+		score, err = s.refreshScore(ctx, created.ID)
+		return err
+	})
 
-	err = s.resumePort.AddWorkExperiences(ctx, resume.ID, payload.Experiences)
 	if err != nil {
 		return nil, err
 	}
 
 	return &dto.ResumeDto{
-		ID:      resume.ID,
-		UserId:  userId.String(),
-		Summary: resume.Summary,
-		Skills:  resume.Skills,
+		ID:         resume.ID,
+		UserId:     userId.String(),
+		Score:      score,
+		Summary:    resume.Summary,
+		Skills:     resume.Skills,
+		TemplateId: resume.TemplateId,
 	}, nil
 }
 
@@ -64,12 +109,370 @@ func (s ResumeService) FindResumes(ctx context.Context, payload dto.ResumeFilter
 	var result []dto.ResumeDto
 	for _, resume := range resumes {
 		result = append(result, dto.ResumeDto{
-			ID:      resume.ID,
-			UserId:  payload.UserId,
-			Summary: resume.Summary,
-			Skills:  resume.Skills,
+			ID:         resume.ID,
+			UserId:     payload.UserId,
+			Score:      resume.Score,
+			Summary:    resume.Summary,
+			Skills:     resume.Skills,
+			TemplateId: resume.TemplateId,
 		})
 	}
 
 	return result, nil
 }
+
+// The [FindResume] usecase returns a single resume, with its experiences and education,
+// as long as it belongs to the authenticated user
+func (s ResumeService) FindResume(ctx context.Context, id string) (*dto.ResumeDetailsDto, error) {
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return resumeDetails(*resume), nil
+}
+
+// The [FindAnyResume] usecase returns any resume, whoever it belongs to. It is meant for
+// administrators and must only be reachable behind a permission check.
+func (s ResumeService) FindAnyResume(ctx context.Context, id string) (*dto.ResumeDetailsDto, error) {
+	resume, err := s.resumePort.FindResumeById(ctx, id)
+	if err != nil {
+		utils.TextLogger.Error("resume not found", "error", err)
+		return nil, errors.New("resume not found")
+	}
+
+	return resumeDetails(*resume), nil
+}
+
+// resumeDetails maps a resume, with its experiences and education, to its response
+func resumeDetails(resume domain.Resume) *dto.ResumeDetailsDto {
+	result := dto.ResumeDetailsDto{
+		ID:          resume.ID,
+		UserId:      resume.UserId,
+		Score:       resume.Score,
+		Summary:     resume.Summary,
+		Skills:      resume.Skills,
+		TemplateId:  resume.TemplateId,
+		Experiences: []dto.WorkExperienceResponseDto{},
+		Education:   []dto.EducationResponseDto{},
+	}
+
+	for _, experience := range resume.Experiences {
+		result.Experiences = append(result.Experiences, dto.WorkExperienceResponseDto{
+			ID:          experience.ID,
+			CompanyName: experience.CompanyName,
+			Role:        experience.Role,
+			StartDate:   experience.StartDate,
+			EndDate:     experience.EndDate,
+		})
+	}
+
+	for _, education := range resume.Education {
+		result.Education = append(result.Education, dto.EducationResponseDto{
+			ID:         education.ID,
+			SchoolName: education.SchoolName,
+			Course:     education.Course,
+			StudyType:  education.StudyType,
+			StartDate:  education.StartDate,
+			EndDate:    education.EndDate,
+		})
+	}
+
+	return &result
+}
+
+// The [UpdateResume] usecase allows a user to edit the summary and skills of their resume
+func (s ResumeService) UpdateResume(ctx context.Context, id string, payload dto.UpdateResumeDto) error {
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	if payload.Summary != nil {
+		updates["summary"] = *payload.Summary
+	}
+	if payload.Skills != nil {
+		updates["skills"] = pq.StringArray(payload.Skills)
+	}
+	if payload.TemplateId != nil {
+		if *payload.TemplateId != "" && !s.templatePort.HasTemplate(*payload.TemplateId) {
+			return fmt.Errorf("template %s does not exist", *payload.TemplateId)
+		}
+		updates["template_id"] = *payload.TemplateId
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return s.applyChange(ctx, resume.ID, func(ctx context.Context) error {
+		return s.resumePort.UpdateResume(ctx, resume.ID, updates)
+	})
+}
+
+// The [DeleteResume] usecase allows a user to remove one of their resumes
+func (s ResumeService) DeleteResume(ctx context.Context, id string) error {
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.resumePort.DeleteResume(ctx, resume.ID)
+}
+
+// The [AddWorkExperience] usecase appends a work experience to a user's resume
+func (s ResumeService) AddWorkExperience(ctx context.Context, id string, payload dto.WorkExperienceDto) error {
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.applyChange(ctx, resume.ID, func(ctx context.Context) error {
+		return s.resumePort.AddWorkExperiences(ctx, resume.ID, []dto.WorkExperienceDto{payload})
+	})
+}
+
+// The [UpdateWorkExperience] usecase edits a work experience that belongs to a user's resume
+func (s ResumeService) UpdateWorkExperience(ctx context.Context, id string, experienceId string, payload dto.UpdateWorkExperienceDto) error {
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	experience := resume.FindExperience(experienceId)
+	if experience == nil {
+		return errors.New("work experience not found")
+	}
+
+	updates := map[string]interface{}{}
+	if payload.CompanyName != nil {
+		updates["company_name"] = *payload.CompanyName
+	}
+	if payload.Role != nil {
+		updates["role"] = *payload.Role
+	}
+
+	err = updatePeriod(updates, experience.StartDate, experience.EndDate, payload.StartDate, payload.EndDate, payload.ClearEndDate)
+	if err != nil {
+		return err
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return s.applyChange(ctx, resume.ID, func(ctx context.Context) error {
+		return s.resumePort.UpdateWorkExperiences(ctx, experienceId, updates)
+	})
+}
+
+// The [DeleteWorkExperience] usecase removes a work experience from a user's resume
+func (s ResumeService) DeleteWorkExperience(ctx context.Context, id string, experienceId string) error {
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !resume.HasExperience(experienceId) {
+		return errors.New("work experience not found")
+	}
+
+	return s.applyChange(ctx, resume.ID, func(ctx context.Context) error {
+		return s.resumePort.DeleteWorkExperience(ctx, experienceId)
+	})
+}
+
+// The [AddEducation] usecase appends an education record to a user's resume
+func (s ResumeService) AddEducation(ctx context.Context, id string, payload dto.EducationDto) error {
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.applyChange(ctx, resume.ID, func(ctx context.Context) error {
+		return s.resumePort.AddEducation(ctx, resume.ID, []dto.EducationDto{payload})
+	})
+}
+
+// The [UpdateEducation] usecase edits an education record that belongs to a user's resume
+func (s ResumeService) UpdateEducation(ctx context.Context, id string, educationId string, payload dto.UpdateEducationDto) error {
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	education := resume.FindEducation(educationId)
+	if education == nil {
+		return errors.New("education not found")
+	}
+
+	updates := map[string]interface{}{}
+	if payload.SchoolName != nil {
+		updates["school_name"] = *payload.SchoolName
+	}
+	if payload.Course != nil {
+		updates["course"] = *payload.Course
+	}
+	if payload.StudyType != nil {
+		updates["study_type"] = *payload.StudyType
+	}
+
+	err = updatePeriod(updates, education.StartDate, education.EndDate, payload.StartDate, payload.EndDate, payload.ClearEndDate)
+	if err != nil {
+		return err
+	}
+
+	if len(updates) == 0 {
+		return nil
+	}
+
+	return s.applyChange(ctx, resume.ID, func(ctx context.Context) error {
+		return s.resumePort.UpdateEducation(ctx, educationId, updates)
+	})
+}
+
+// updatePeriod adds the changed start and end dates to updates, clearing the end date when asked
+// to, and rejects a period that would end before it starts once merged with the stored dates
+func updatePeriod(updates map[string]interface{}, startDate time.Time, endDate *time.Time, newStartDate *time.Time, newEndDate *time.Time, clearEndDate bool) error {
+	if newStartDate != nil {
+		startDate = *newStartDate
+		updates["start_date"] = *newStartDate
+	}
+	if newEndDate != nil {
+		endDate = newEndDate
+		updates["end_date"] = *newEndDate
+	}
+	if clearEndDate {
+		endDate = nil
+		updates["end_date"] = nil
+	}
+
+	if endDate != nil && endDate.Before(startDate) {
+		return errEndDateBeforeStartDate
+	}
+	return nil
+}
+
+// The [DeleteEducation] usecase removes an education record from a user's resume
+func (s ResumeService) DeleteEducation(ctx context.Context, id string, educationId string) error {
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !resume.HasEducation(educationId) {
+		return errors.New("education not found")
+	}
+
+	return s.applyChange(ctx, resume.ID, func(ctx context.Context) error {
+		return s.resumePort.DeleteEducation(ctx, educationId)
+	})
+}
+
+// The [ExportResume] usecase renders a user's resume into a downloadable document of the given format
+func (s ResumeService) ExportResume(ctx context.Context, id string, format string) (*dto.ExportDto, error) {
+	renderer, ok := s.renderers[format]
+	if !ok {
+		return nil, fmt.Errorf("exporting resumes as %s is not supported", format)
+	}
+
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := renderer.Render(*resume)
+	if err != nil {
+		utils.TextLogger.Error("unable to render resume", "error", err)
+		return nil, err
+	}
+
+	return &dto.ExportDto{
+		FileName:    fmt.Sprintf("resume-%s.%s", resume.ID, renderer.Format()),
+		ContentType: renderer.ContentType(),
+		Content:     content,
+	}, nil
+}
+
+// The [ListTemplates] usecase lists the templates a resume can be rendered with
+func (s ResumeService) ListTemplates(ctx context.Context) []dto.TemplateDto {
+	return s.templatePort.ListTemplates()
+}
+
+// The [ScoreResume] usecase explains how a user's resume was scored and what can be improved
+func (s ResumeService) ScoreResume(ctx context.Context, id string) (*dto.ResumeScoreDto, error) {
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	score := s.scorer.Score(*resume)
+	return &score, nil
+}
+
+// The [MatchResume] usecase compares a user's resume against a job description
+// and reports the keywords of the job description that the resume is missing
+func (s ResumeService) MatchResume(ctx context.Context, id string, payload dto.MatchResumeDto) (*dto.ResumeMatchDto, error) {
+	resume, err := s.findOwnedResume(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	match := s.matcher.Match(*resume, payload.JobDescription)
+	return &match, nil
+}
+
+// applyChange runs a change to a resume and refreshes its score within a single transaction
+func (s ResumeService) applyChange(ctx context.Context, id string, change func(ctx context.Context) error) error {
+	return s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := change(ctx); err != nil {
+			return err
+		}
+
+		_, err := s.refreshScore(ctx, id)
+		return err
+	})
+}
+
+// refreshScore recomputes the score of a resume after it has changed and stores it
+func (s ResumeService) refreshScore(ctx context.Context, id string) (int, error) {
+	resume, err := s.resumePort.FindResumeById(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	score := s.scorer.Score(*resume)
+	if score.Score == resume.Score {
+		return score.Score, nil
+	}
+
+	err = s.resumePort.UpdateResume(ctx, id, map[string]interface{}{"score": score.Score})
+	if err != nil {
+		utils.TextLogger.Error("unable to store resume score", "error", err)
+		return 0, err
+	}
+
+	return score.Score, nil
+}
+
+// findOwnedResume loads a resume and ensures it belongs to the user in the context.
+// Resumes owned by other users are reported as not found to avoid leaking their existence.
+func (s ResumeService) findOwnedResume(ctx context.Context, id string) (*domain.Resume, error) {
+	userId, err := utils.ExtractUuidFromContext(ctx, utils.USER_ID_KEY)
+	if err != nil {
+		return nil, err
+	}
+
+	resume, err := s.resumePort.FindResumeById(ctx, id)
+	if err != nil {
+		utils.TextLogger.Error("resume not found", "error", err)
+		return nil, errors.New("resume not found")
+	}
+
+	if resume.UserId != userId.String() {
+		return nil, errors.New("resume not found")
+	}
+
+	return resume, nil
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+const (
+	idealSummaryLength = 120
+	idealSkillCount    = 8
+)
+
+// Matches figures that usually describe an achievement, e.g. "40%", "$2M", "3x", "10k users" or "15 engineers"
+var quantifiedPattern = regexp.MustCompile(`(?i)([$€£]\s?\d[\d,.]*\s?[kmb]?\b|\d[\d,.]*\s?(%|percent|x\b|k\b|m\b|\+|million|billion|thousand|users|customers|clients|people|engineers|projects|hours|days|weeks|months))`)
+
+type ScoreService struct {
+	now func() time.Time
+}
+
+func NewScoreService() *ScoreService {
+	return &ScoreService{now: time.Now}
+}
+
+// NewScoreServiceWithClock creates a score service that evaluates dates against the given clock
+func NewScoreServiceWithClock(now func() time.Time) *ScoreService {
+	return &ScoreService{now: now}
+}
+
+// The [Score] usecase grades how complete and consistent a resume is, out of 100,
+// and explains what can be improved for every criterion
+func (s ScoreService) Score(resume domain.Resume) dto.ResumeScoreDto {
+	criteria := []dto.ScoreCriterionDto{
+		s.scoreSummary(resume),
+		s.scoreSkills(resume),
+		s.scoreExperience(resume),
+		s.scoreEducation(resume),
+		s.scoreAchievements(resume),
+		s.scoreDates(resume),
+	}
+
+	result := dto.ResumeScoreDto{ResumeId: resume.ID, Criteria: criteria}
+	for _, criterion := range criteria {
+		result.Score += criterion.Score
+		result.MaxScore += criterion.MaxScore
+	}
+
+	return result
+}
+
+func (s ScoreService) scoreSummary(resume domain.Resume) dto.ScoreCriterionDto {
+	criterion := newCriterion("summary", 15)
+	length := len(strings.TrimSpace(resume.Summary))
+
+	switch {
+	case length == 0:
+		criterion.Suggestions = append(criterion.Suggestions, "Add a summary that introduces who you are and what you are looking for")
+	case length < idealSummaryLength:
+		criterion.Score = criterion.MaxScore * length / idealSummaryLength
+		criterion.Suggestions = append(criterion.Suggestions, fmt.Sprintf("Expand your summary to at least %d characters", idealSummaryLength))
+	default:
+		criterion.Score = criterion.MaxScore
+	}
+
+	return criterion
+}
+
+func (s ScoreService) scoreSkills(resume domain.Resume) dto.ScoreCriterionDto {
+	criterion := newCriterion("skills", 15)
+	count := len(resume.Skills)
+
+	if count >= idealSkillCount {
+		criterion.Score = criterion.MaxScore
+		return criterion
+	}
+
+	criterion.Score = criterion.MaxScore * count / idealSkillCount
+	criterion.Suggestions = append(criterion.Suggestions, fmt.Sprintf("List at least %d skills, you currently have %d", idealSkillCount, count))
+	return criterion
+}
+
+func (s ScoreService) scoreExperience(resume domain.Resume) dto.ScoreCriterionDto {
+	criterion := newCriterion("experience", 20)
+	if len(resume.Experiences) == 0 {
+		criterion.Suggestions = append(criterion.Suggestions, "Add your work experience")
+		return criterion
+	}
+	criterion.Score += 10
+
+	// Only the current role is expected to be missing an end date
+	openEnded := 0
+	for _, experience := range resume.Experiences {
+		if experience.EndDate == nil {
+			openEnded++
+		}
+	}
+
+	if openEnded <= 1 {
+		criterion.Score += 10
+	} else {
+		criterion.Suggestions = append(criterion.Suggestions, fmt.Sprintf("%d roles have no end date, add end dates to the roles you have left", openEnded))
+	}
+
+	return criterion
+}
+
+func (s ScoreService) scoreEducation(resume domain.Resume) dto.ScoreCriterionDto {
+	criterion := newCriterion("education", 10)
+	if len(resume.Education) == 0 {
+		criterion.Suggestions = append(criterion.Suggestions, "Add your education")
+		return criterion
+	}
+
+	criterion.Score = criterion.MaxScore
+	return criterion
+}
+
+func (s ScoreService) scoreAchievements(resume domain.Resume) dto.ScoreCriterionDto {
+	criterion := newCriterion("quantified_achievements", 20)
+
+	texts := []string{resume.Summary}
+	for _, experience := range resume.Experiences {
+		texts = append(texts, experience.Role)
+	}
+
+	count := len(quantifiedPattern.FindAllString(strings.Join(texts, "\n"), -1))
+	switch {
+	case count == 0:
+		criterion.Suggestions = append(criterion.Suggestions, "Quantify your achievements with figures such as percentages, revenue or team size")
+	case count == 1:
+		criterion.Score = criterion.MaxScore / 2
+		criterion.Suggestions = append(criterion.Suggestions, "Add more quantified achievements to back up your experience")
+	default:
+		criterion.Score = criterion.MaxScore
+	}
+
+	return criterion
+}
+
+func (s ScoreService) scoreDates(resume domain.Resume) dto.ScoreCriterionDto {
+	criterion := newCriterion("date_consistency", 20)
+	now := s.now()
+
+	var issues []string
+	check := func(label string, start time.Time, end *time.Time) {
+		if start.After(now) {
+			issues = append(issues, fmt.Sprintf("%s starts in the future", label))
+		}
+		if end != nil && end.Before(start) {
+			issues = append(issues, fmt.Sprintf("%s ends before it starts", label))
+		}
+	}
+
+	for _, experience := range resume.Experiences {
+		check(fmt.Sprintf("Your role as %s at %s", experience.Role, experience.CompanyName), experience.StartDate, experience.EndDate)
+	}
+
+	for _, education := range resume.Education {
+		check(fmt.Sprintf("Your %s course at %s", education.Course, education.SchoolName), education.StartDate, education.EndDate)
+	}
+
+	criterion.Score = max(criterion.MaxScore-len(issues)*5, 0)
+	criterion.Suggestions = append(criterion.Suggestions, issues...)
+	return criterion
+}
+
+func newCriterion(name string, maxScore int) dto.ScoreCriterionDto {
+	return dto.ScoreCriterionDto{Name: name, MaxScore: maxScore, Suggestions: []string{}}
+}
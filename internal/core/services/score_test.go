@@ -0,0 +1,78 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func findCriterion(score dto.ResumeScoreDto, name string) dto.ScoreCriterionDto {
+	for _, criterion := range score.Criteria {
+		if criterion.Name == name {
+			return criterion
+		}
+	}
+	return dto.ScoreCriterionDto{}
+}
+
+func date(year int, month time.Month) time.Time {
+	return time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestScoreEmptyResume(t *testing.T) {
+	scorer := services.NewScoreServiceWithClock(func() time.Time { return date(2024, time.June) })
+
+	score := scorer.Score(domain.Resume{})
+
+	assert.Equal(t, 100, score.MaxScore)
+	assert.Equal(t, 20, score.Score, "only date consistency should be awarded")
+	assert.NotEmpty(t, findCriterion(score, "summary").Suggestions)
+	assert.NotEmpty(t, findCriterion(score, "experience").Suggestions)
+}
+
+func TestScoreCompleteResume(t *testing.T) {
+	scorer := services.NewScoreServiceWithClock(func() time.Time { return date(2024, time.June) })
+	end := date(2021, time.December)
+
+	score := scorer.Score(domain.Resume{
+		Summary: "Backend engineer who grew checkout conversion by 25% and led a team of 6 engineers through a migration serving 2 million users.",
+		Skills:  []string{"Go", "SQL", "Kubernetes", "gRPC", "Kafka", "Terraform", "AWS", "Linux"},
+		Experiences: []domain.WorkExperience{
+			{CompanyName: "Acme", Role: "Engineer", StartDate: date(2018, time.January), EndDate: &end},
+			{CompanyName: "Initech", Role: "Senior Engineer", StartDate: date(2022, time.January)},
+		},
+		Education: []domain.Education{
+			{SchoolName: "State University", Course: "Computer Science", StartDate: date(2012, time.September)},
+		},
+	})
+
+	assert.Equal(t, 100, score.Score)
+	for _, criterion := range score.Criteria {
+		assert.Empty(t, criterion.Suggestions, criterion.Name)
+	}
+}
+
+func TestScoreInconsistentDates(t *testing.T) {
+	scorer := services.NewScoreServiceWithClock(func() time.Time { return date(2024, time.June) })
+	end := date(2019, time.January)
+
+	score := scorer.Score(domain.Resume{
+		Experiences: []domain.WorkExperience{
+			{CompanyName: "Acme", Role: "Engineer", StartDate: date(2020, time.January), EndDate: &end},
+			{CompanyName: "Initech", Role: "Engineer", StartDate: date(2025, time.January)},
+			{CompanyName: "Globex", Role: "Engineer", StartDate: date(2023, time.January)},
+		},
+	})
+
+	dates := findCriterion(score, "date_consistency")
+	assert.Equal(t, 10, dates.Score)
+	assert.Contains(t, dates.Suggestions, "Your role as Engineer at Acme ends before it starts")
+	assert.Contains(t, dates.Suggestions, "Your role as Engineer at Initech starts in the future")
+
+	experience := findCriterion(score, "experience")
+	assert.Equal(t, 10, experience.Score)
+}
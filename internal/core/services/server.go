@@ -7,17 +7,26 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	slogfiber "github.com/samber/slog-fiber"
+	"github.com/stivo-m/vise-resume/internal/adapters/breached"
 	"github.com/stivo-m/vise-resume/internal/adapters/database"
 	"github.com/stivo-m/vise-resume/internal/adapters/database/repository"
 	"github.com/stivo-m/vise-resume/internal/adapters/http/handlers"
+	"github.com/stivo-m/vise-resume/internal/adapters/identity"
+	"github.com/stivo-m/vise-resume/internal/adapters/jsonresume"
+	"github.com/stivo-m/vise-resume/internal/adapters/ratelimit"
+	"github.com/stivo-m/vise-resume/internal/adapters/render"
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
 )
 
 type Server struct {
-	db *database.DB
+	config *config.Config
+	db     *database.DB
+	mailer ports.Mailer
 }
 
-func NewServer(db *database.DB) *Server {
-	return &Server{db}
+func NewServer(config *config.Config, db *database.DB, mailer ports.Mailer) *Server {
+	return &Server{config: config, db: db, mailer: mailer}
 }
 
 func (s *Server) PrepareServer() (*fiber.App, error) {
@@ -31,25 +40,77 @@ func (s *Server) PrepareServer() (*fiber.App, error) {
 	userRepo := repository.NewUserRepository(s.db)
 	verificationRepo := repository.NewVerificationRepository(s.db)
 	resumeRepo := repository.NewResumeRepository(s.db)
+	twoFactorRepo := repository.NewTwoFactorRepository(s.db)
+	roleRepo := repository.NewRoleRepository(s.db)
+	identityRepo := repository.NewIdentityRepository(s.db)
+	auditRepo := repository.NewAuditRepository(s.db)
 
 	// Services
-	tokenService := NewTokenService()
-	passwordService := NewPasswordService()
+	tokenService, err := NewTokenServiceFromConfig(s.config.Token)
+	if err != nil {
+		return nil, err
+	}
+	passwordService, err := NewPasswordServiceFromConfig(s.config.Password)
+	if err != nil {
+		return nil, err
+	}
+	verificationService := NewVerificationService(verificationRepo, NewVerificationPoliciesFromConfig(s.config.Verification))
 	userService := NewUserService(
+		s.db,
 		userRepo,
 		tokenService,
 		passwordService,
+		DefaultPasswordPolicy(breached.NewBundledList()),
 		verificationRepo,
+		verificationService,
+		twoFactorRepo,
+		NewTOTPService("Vise Resume"),
+		identityRepo,
+		identity.NewProviders(s.config.Identity),
+		auditRepo,
+		s.mailer,
+		magicLinkURL(s.config.Server),
 	)
-	resumeService := NewResumeService(resumeRepo)
+	templateEngine, err := render.NewEngine(render.DefaultThemes()...)
+	if err != nil {
+		return nil, err
+	}
+	resumeService := NewResumeService(s.db, resumeRepo, templateEngine, NewScoreService(), NewMatchService(), []ports.ResumeRenderer{
+		render.NewPDFRenderer(templateEngine),
+		render.NewHTMLRenderer(templateEngine),
+		render.NewMarkdownRenderer(templateEngine),
+		jsonresume.NewRenderer(),
+	})
+
+	adminService := NewAdminService(s.db, userRepo, roleRepo, userService, auditRepo)
+	accountService := NewAccountService(s.db, userRepo, resumeRepo, verificationRepo, verificationService, passwordService, auditRepo, s.mailer)
+	auditService := NewAuditService(auditRepo)
+
+	var rateLimitStore ports.RateLimitStore = repository.NewRateLimitRepository(s.db)
+	if s.config.RateLimit.Store == "memory" {
+		rateLimitStore = ratelimit.NewMemoryStore()
+	}
+	rateLimiter := NewRateLimitService(rateLimitStore, DefaultRateLimitPolicy())
 
 	// handlers
 	api := app.Group("/api/v1")
-	authHandlers := handlers.NewAuthHandler(userService, userRepo, tokenService)
+	authHandlers := handlers.NewAuthHandler(userService, auditService, userRepo, tokenService, auditRepo, rateLimiter)
 	authHandlers.RegisterAuthRoutes(api)
 
-	resumeHandler := handlers.NewResumeHandler(resumeService, userRepo, tokenService)
+	accountHandler := handlers.NewAccountHandler(accountService, userRepo, tokenService, auditRepo)
+	accountHandler.RegisterAccountRoutes(api)
+
+	resumeHandler := handlers.NewResumeHandler(resumeService, userRepo, tokenService, auditRepo)
 	resumeHandler.RegisterResumeRoutes(api)
 
+	adminHandler := handlers.NewAdminHandler(adminService, resumeService, auditService, userRepo, tokenService, roleRepo, auditRepo)
+	adminHandler.RegisterAdminRoutes(api)
+
+	wellKnownHandler := handlers.NewWellKnownHandler(tokenService)
+	wellKnownHandler.RegisterWellKnownRoutes(app)
+
+	templateHandler := handlers.NewTemplateHandler(resumeService)
+	templateHandler.RegisterTemplateRoutes(api)
+
 	return app, nil
 }
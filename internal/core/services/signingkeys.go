@@ -0,0 +1,178 @@
+package services
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+)
+
+// SigningKey is a key tokens are signed or verified with, identified by the kid header of the tokens.
+// Keys without a private part are only used to verify tokens issued before a rotation.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.PrivateKey
+	Public  crypto.PublicKey
+}
+
+// NewHMACSigningKey creates a key for the legacy HS256 tokens signed with a shared secret
+func NewHMACSigningKey(id string, secret string) SigningKey {
+	return SigningKey{ID: id, Method: jwt.SigningMethodHS256, Private: []byte(secret), Public: []byte(secret)}
+}
+
+// NewHMACVerificationKey creates a key that only verifies the HS256 tokens signed with a shared
+// secret, to keep them working after switching to asymmetric keys
+func NewHMACVerificationKey(id string, secret string) SigningKey {
+	return SigningKey{ID: id, Method: jwt.SigningMethodHS256, Public: []byte(secret)}
+}
+
+// NewSigningKey creates a key from an RSA or Ed25519 private key, choosing RS256 or EdDSA to match
+func NewSigningKey(id string, private crypto.PrivateKey) (SigningKey, error) {
+	switch key := private.(type) {
+	case *rsa.PrivateKey:
+		return SigningKey{ID: id, Method: jwt.SigningMethodRS256, Private: key, Public: &key.PublicKey}, nil
+	case ed25519.PrivateKey:
+		return SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Private: key, Public: key.Public()}, nil
+	default:
+		return SigningKey{}, fmt.Errorf("signing key %s has an unsupported type %T", id, private)
+	}
+}
+
+// NewVerificationKey creates a key that only verifies tokens from an RSA or Ed25519 public key
+func NewVerificationKey(id string, public crypto.PublicKey) (SigningKey, error) {
+	switch public.(type) {
+	case *rsa.PublicKey:
+		return SigningKey{ID: id, Method: jwt.SigningMethodRS256, Public: public}, nil
+	case ed25519.PublicKey:
+		return SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Public: public}, nil
+	default:
+		return SigningKey{}, fmt.Errorf("verification key %s has an unsupported type %T", id, public)
+	}
+}
+
+// LoadSigningKeys reads every <kid>.pem file of a directory. Files holding a private key
+// (PKCS#8, or PKCS#1 for RSA) can sign tokens; files holding a public key only verify them.
+// Keys are returned sorted by kid.
+func LoadSigningKeys(dir string) ([]SigningKey, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	keys := make([]SigningKey, 0, len(files))
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		id := strings.TrimSuffix(filepath.Base(file), ".pem")
+		key, err := ParseSigningKey(id, content)
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no signing keys found in %s", dir)
+	}
+
+	return keys, nil
+}
+
+// ParseSigningKey reads a PEM encoded private or public key
+func ParseSigningKey(id string, content []byte) (SigningKey, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return SigningKey{}, fmt.Errorf("signing key %s is not PEM encoded", id)
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		private, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return SigningKey{}, err
+		}
+		return NewSigningKey(id, private)
+
+	case "RSA PRIVATE KEY":
+		private, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return SigningKey{}, err
+		}
+		return NewSigningKey(id, private)
+
+	case "PUBLIC KEY":
+		public, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return SigningKey{}, err
+		}
+		return NewVerificationKey(id, public)
+
+	default:
+		return SigningKey{}, fmt.Errorf("signing key %s has an unsupported PEM block %q", id, block.Type)
+	}
+}
+
+// GenerateSigningKey creates a new PEM encoded private key for the rs256 or eddsa algorithm
+func GenerateSigningKey(algorithm string) ([]byte, error) {
+	var private crypto.PrivateKey
+	var err error
+
+	switch strings.ToLower(algorithm) {
+	case "rs256", "rsa":
+		private, err = rsa.GenerateKey(rand.Reader, 2048)
+	case "eddsa", "ed25519":
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q, use rs256 or eddsa", algorithm)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// JWK returns the public part of the key as a JSON Web Key. Shared secrets can not be published.
+func (k SigningKey) JWK() (dto.JWK, error) {
+	jwk := dto.JWK{KeyID: k.ID, Use: "sig", Algorithm: k.Method.Alg()}
+	encode := base64.RawURLEncoding.EncodeToString
+
+	switch public := k.Public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encode(public.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encode(public)
+	default:
+		return dto.JWK{}, errors.New("only asymmetric keys can be published")
+	}
+
+	return jwk, nil
+}
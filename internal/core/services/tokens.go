@@ -3,34 +3,125 @@ package services
 import (
 	"errors"
 	"fmt"
-	"os"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
 )
 
+const (
+	defaultTokenIssuer = "vise-resume"
+	// hmacKeyId is the kid of the tokens signed with the secret key
+	hmacKeyId = "default"
+)
+
+// TokenConfig lists the keys tokens are signed and verified with. New tokens are signed with the
+// key SigningKeyId, or the last key with a private part when it is empty. The other keys keep
+// verifying the tokens they signed, so a key can be rotated without logging everyone out.
+type TokenConfig struct {
+	Keys         []SigningKey
+	SigningKeyId string
+	Issuer       string
+	Audience     string
+}
+
 type TokenService struct {
+	keys       map[string]SigningKey
+	signingKey SigningKey
+	issuer     string
+	audience   string
+	now        func() time.Time
 }
 
-func NewTokenService() *TokenService {
-	return &TokenService{}
+func NewTokenService(config TokenConfig) (*TokenService, error) {
+	return NewTokenServiceWithClock(config, time.Now)
+}
+
+// NewTokenServiceWithClock creates a token service reading the time from now
+func NewTokenServiceWithClock(config TokenConfig, now func() time.Time) (*TokenService, error) {
+	service := &TokenService{
+		keys:     map[string]SigningKey{},
+		issuer:   config.Issuer,
+		audience: config.Audience,
+		now:      now,
+	}
+	if service.issuer == "" {
+		service.issuer = defaultTokenIssuer
+	}
+	if service.audience == "" {
+		service.audience = service.issuer
+	}
+
+	for _, key := range config.Keys {
+		if _, ok := service.keys[key.ID]; ok {
+			return nil, fmt.Errorf("signing key %s is defined twice", key.ID)
+		}
+		service.keys[key.ID] = key
+
+		if key.Private != nil && (config.SigningKeyId == "" || config.SigningKeyId == key.ID) {
+			service.signingKey = key
+		}
+	}
+
+	if service.signingKey.Private == nil {
+		if config.SigningKeyId != "" {
+			return nil, fmt.Errorf("signing key %s was not found or has no private key", config.SigningKeyId)
+		}
+		return nil, errors.New("no private key available to sign tokens")
+	}
+
+	return service, nil
+}
+
+// NewTokenServiceFromConfig loads the keys from the PEM files in the signing keys directory and
+// signs with the configured key. Without a key directory tokens are signed with HS256 and the
+// secret key. When both are set the secret key only verifies the HS256 tokens issued before the
+// switch to the key directory, so they keep working until they expire.
+func NewTokenServiceFromConfig(settings config.TokenConfig) (*TokenService, error) {
+	tokenConfig := TokenConfig{
+		SigningKeyId: settings.SigningKeyId,
+		Issuer:       settings.Issuer,
+		Audience:     settings.Audience,
+	}
+
+	if settings.SigningKeysDir != "" {
+		keys, err := LoadSigningKeys(settings.SigningKeysDir)
+		if err != nil {
+			return nil, err
+		}
+		tokenConfig.Keys = keys
+
+		if settings.SecretKey != "" {
+			tokenConfig.Keys = append(tokenConfig.Keys, NewHMACVerificationKey(hmacKeyId, settings.SecretKey))
+		}
+	} else {
+		if settings.SecretKey == "" {
+			return nil, errors.New("either a signing keys directory or a secret key has to be configured")
+		}
+		tokenConfig.Keys = []SigningKey{NewHMACSigningKey(hmacKeyId, settings.SecretKey)}
+		tokenConfig.SigningKeyId = ""
+	}
+
+	return NewTokenService(tokenConfig)
 }
 
 func (s TokenService) CreateToken(id string, expiryDate time.Time) (string, error) {
-	secretKey := os.Getenv("TOKEN_SECRET_KEY")
-	if secretKey == "" {
-		return "", errors.New("unable to get the token secret from env variables")
+	claims := jwt.RegisteredClaims{
+		Issuer:    s.issuer,
+		Subject:   id,
+		Audience:  jwt.ClaimStrings{s.audience},
+		ExpiresAt: jwt.NewNumericDate(expiryDate),
+		IssuedAt:  jwt.NewNumericDate(s.now()),
+		ID:        uuid.NewString(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256,
-		jwt.MapClaims{
-			"id":  id,
-			"exp": expiryDate.Unix(),
-		},
-	)
+	token := jwt.NewWithClaims(s.signingKey.Method, claims)
+	token.Header["kid"] = s.signingKey.ID
 
-	key := []byte(secretKey)
-	tokenString, err := token.SignedString(key)
+	tokenString, err := token.SignedString(s.signingKey.Private)
 	if err != nil {
 		return "", err
 	}
@@ -39,29 +130,111 @@ func (s TokenService) CreateToken(id string, expiryDate time.Time) (string, erro
 }
 
 func (s TokenService) VerifyToken(tokenString string) (string, error) {
-	secretKey := os.Getenv("TOKEN_SECRET_KEY")
-	if secretKey == "" {
-		return "", errors.New("unable to get the token secret from env variables")
-	}
-
-	key := []byte(secretKey)
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return key, nil
-	})
+	var claims jwt.RegisteredClaims
+	token, err := jwt.ParseWithClaims(tokenString, &claims, s.verificationKey,
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(s.now),
+	)
 
 	if err != nil {
 		return "", err
 	}
 
-	if !token.Valid {
+	if !token.Valid || claims.Subject == "" || claims.ID == "" {
 		return "", errors.New("either token is invalid or has expired")
 	}
 
-	// Extract the claims from the token if needed
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userId := fmt.Sprintf("%v", claims["id"])
-		return userId, nil
+	return claims.Subject, nil
+}
+
+// linkClaims are the claims of a token put in a link sent by email
+type linkClaims struct {
+	jwt.RegisteredClaims
+	Binding string `json:"bnd,omitempty"`
+}
+
+// CreateLinkToken signs a token to put in a link sent by email. The audience of the token names
+// the purpose of the link, so it is never accepted as an access token nor for another purpose.
+func (s TokenService) CreateLinkToken(payload dto.LinkTokenDto) (string, error) {
+	claims := linkClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   payload.UserID,
+			Audience:  jwt.ClaimStrings{s.linkAudience(payload.Purpose)},
+			ExpiresAt: jwt.NewNumericDate(payload.ExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(s.now()),
+			ID:        payload.Code,
+		},
+		Binding: payload.Binding,
+	}
+
+	token := jwt.NewWithClaims(s.signingKey.Method, claims)
+	token.Header["kid"] = s.signingKey.ID
+
+	return token.SignedString(s.signingKey.Private)
+}
+
+// VerifyLinkToken checks a token created by [CreateLinkToken] for the given purpose and returns
+// what it vouches for
+func (s TokenService) VerifyLinkToken(tokenString string, purpose string) (*dto.LinkTokenDto, error) {
+	var claims linkClaims
+	token, err := jwt.ParseWithClaims(tokenString, &claims, s.verificationKey,
+		jwt.WithIssuer(s.issuer),
+		jwt.WithAudience(s.linkAudience(purpose)),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithTimeFunc(s.now),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid || claims.Subject == "" || claims.ID == "" {
+		return nil, errors.New("either token is invalid or has expired")
+	}
+
+	return &dto.LinkTokenDto{
+		Purpose:   purpose,
+		UserID:    claims.Subject,
+		Code:      claims.ID,
+		Binding:   claims.Binding,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}, nil
+}
+
+func (s TokenService) linkAudience(purpose string) string {
+	return s.audience + "/" + purpose
+}
+
+// verificationKey picks the key named by the kid header, refusing tokens signed with any other
+// algorithm than the one of that key so a public key can never be used as an HMAC secret
+func (s TokenService) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+
+	return key.Public, nil
+}
+
+// JWKS lists the public keys tokens can be verified with, in the JSON Web Key Set format
+func (s TokenService) JWKS() dto.JWKSet {
+	set := dto.JWKSet{Keys: []dto.JWK{}}
+	for _, key := range s.keys {
+		if jwk, err := key.JWK(); err == nil {
+			set.Keys = append(set.Keys, jwk)
+		}
 	}
 
-	return "", errors.New("unknown error occurred")
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
 }
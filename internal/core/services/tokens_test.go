@@ -0,0 +1,271 @@
+package services_test
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stretchr/testify/assert"
+)
+
+func generateTestKey(t *testing.T, id string, algorithm string) services.SigningKey {
+	content, err := services.GenerateSigningKey(algorithm)
+	assert.NoError(t, err)
+
+	key, err := services.ParseSigningKey(id, content)
+	assert.NoError(t, err)
+	return key
+}
+
+func TestTokensSignedWithAsymmetricKeys(t *testing.T) {
+	for _, algorithm := range []string{"rs256", "eddsa"} {
+		t.Run(algorithm, func(t *testing.T) {
+			key := generateTestKey(t, "key-1", algorithm)
+			service, err := services.NewTokenService(services.TokenConfig{Keys: []services.SigningKey{key}})
+			assert.NoError(t, err)
+
+			token, err := service.CreateToken("user-id", time.Now().Add(time.Minute))
+			assert.NoError(t, err)
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+			assert.NoError(t, err)
+			assert.Equal(t, key.Method.Alg(), parsed.Method.Alg())
+			assert.Equal(t, "key-1", parsed.Header["kid"])
+
+			claims := parsed.Claims.(*jwt.RegisteredClaims)
+			assert.Equal(t, "vise-resume", claims.Issuer)
+			assert.Equal(t, jwt.ClaimStrings{"vise-resume"}, claims.Audience)
+			assert.NotNil(t, claims.IssuedAt)
+			assert.NotEmpty(t, claims.ID)
+
+			userId, err := service.VerifyToken(token)
+			assert.NoError(t, err)
+			assert.Equal(t, "user-id", userId)
+		})
+	}
+}
+
+func TestRS256AccessTokensFitTheirColumn(t *testing.T) {
+	service, err := services.NewTokenService(services.TokenConfig{Keys: []services.SigningKey{generateTestKey(t, "key-1", "rs256")}})
+	assert.NoError(t, err)
+	token, err := service.CreateToken(uuid.NewString(), time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err)
+	columns, err := db.Conn(context.Background()).Migrator().ColumnTypes(&domain.Token{})
+	assert.NoError(t, err)
+
+	found := false
+	for _, column := range columns {
+		if column.Name() != "access_token" {
+			continue
+		}
+		found = true
+		if length, limited := column.Length(); limited {
+			assert.GreaterOrEqual(t, length, int64(len(token)), "Expected a %d characters token to fit in %s", len(token), column.DatabaseTypeName())
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestTokensSurviveKeyRotation(t *testing.T) {
+	oldKey := generateTestKey(t, "2024", "eddsa")
+	newKey := generateTestKey(t, "2025", "rs256")
+
+	before, err := services.NewTokenService(services.TokenConfig{Keys: []services.SigningKey{oldKey}})
+	assert.NoError(t, err)
+	oldToken, err := before.CreateToken("user-id", time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+
+	// The old key is kept with only its public part to verify the tokens it signed
+	retired, err := services.NewVerificationKey(oldKey.ID, oldKey.Public)
+	assert.NoError(t, err)
+	after, err := services.NewTokenService(services.TokenConfig{Keys: []services.SigningKey{retired, newKey}})
+	assert.NoError(t, err)
+
+	userId, err := after.VerifyToken(oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-id", userId)
+
+	newToken, err := after.CreateToken("user-id", time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2025", parsed.Header["kid"])
+
+	_, err = before.VerifyToken(newToken)
+	assert.Error(t, err, "Expected tokens of unknown keys to be rejected")
+
+	jwks := after.JWKS()
+	assert.Len(t, jwks.Keys, 2)
+	assert.Equal(t, "OKP", jwks.Keys[0].KeyType)
+	assert.Equal(t, "EdDSA", jwks.Keys[0].Algorithm)
+	assert.Equal(t, "RSA", jwks.Keys[1].KeyType)
+	assert.Equal(t, "RS256", jwks.Keys[1].Algorithm)
+	assert.NotEmpty(t, jwks.Keys[1].N)
+}
+
+func TestSecretKeyTokensSurviveTheSwitchToSigningKeys(t *testing.T) {
+	dir := t.TempDir()
+	content, err := services.GenerateSigningKey("eddsa")
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2025.pem"), content, 0o600))
+
+	before, err := services.NewTokenServiceFromConfig(config.TokenConfig{SecretKey: "secret"})
+	assert.NoError(t, err)
+	oldToken, err := before.CreateToken("user-id", time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+
+	after, err := services.NewTokenServiceFromConfig(config.TokenConfig{SigningKeysDir: dir, SecretKey: "secret"})
+	assert.NoError(t, err)
+
+	userId, err := after.VerifyToken(oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-id", userId)
+
+	newToken, err := after.CreateToken("user-id", time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &jwt.RegisteredClaims{})
+	assert.NoError(t, err)
+	assert.Equal(t, "EdDSA", parsed.Method.Alg())
+	assert.Equal(t, "2025", parsed.Header["kid"])
+
+	jwks := after.JWKS()
+	assert.Len(t, jwks.Keys, 1, "Expected the secret key not to be published")
+	assert.Equal(t, "2025", jwks.Keys[0].KeyID)
+
+	withoutSecret, err := services.NewTokenServiceFromConfig(config.TokenConfig{SigningKeysDir: dir})
+	assert.NoError(t, err)
+	_, err = withoutSecret.VerifyToken(oldToken)
+	assert.Error(t, err, "Expected HS256 tokens to be rejected once the secret key is removed")
+}
+
+func TestTokenVerificationIsStrict(t *testing.T) {
+	key := generateTestKey(t, "rsa", "rs256")
+	clock := &testClock{now: time.Now()}
+	service, err := services.NewTokenServiceWithClock(services.TokenConfig{Keys: []services.SigningKey{key}}, clock.Now)
+	assert.NoError(t, err)
+
+	sign := func(method jwt.SigningMethod, signingKey interface{}, claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = "rsa"
+		signed, err := token.SignedString(signingKey)
+		assert.NoError(t, err)
+		return signed
+	}
+
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub": "user-id",
+			"iss": "vise-resume",
+			"aud": "vise-resume",
+			"exp": clock.now.Add(time.Minute).Unix(),
+			"iat": clock.now.Unix(),
+			"jti": "token-id",
+		}
+	}
+
+	_, err = service.VerifyToken(sign(jwt.SigningMethodRS256, key.Private, claims()))
+	assert.NoError(t, err)
+
+	// A token signed with HS256 using the public key as the secret must not be accepted
+	publicKey, err := x509.MarshalPKIXPublicKey(key.Public.(*rsa.PublicKey))
+	assert.NoError(t, err)
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKey})
+	_, err = service.VerifyToken(sign(jwt.SigningMethodHS256, publicPEM, claims()))
+	assert.Error(t, err)
+
+	_, err = service.VerifyToken(sign(jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims()))
+	assert.Error(t, err)
+
+	for claim, value := range map[string]interface{}{
+		"iss": "someone-else",
+		"aud": "another-service",
+		"iat": clock.now.Add(time.Hour).Unix(),
+		"exp": clock.now.Add(-time.Minute).Unix(),
+		"jti": "",
+	} {
+		tampered := claims()
+		tampered[claim] = value
+		_, err = service.VerifyToken(sign(jwt.SigningMethodRS256, key.Private, tampered))
+		assert.Error(t, err, claim)
+	}
+
+	withoutExpiry := claims()
+	delete(withoutExpiry, "exp")
+	_, err = service.VerifyToken(sign(jwt.SigningMethodRS256, key.Private, withoutExpiry))
+	assert.Error(t, err)
+}
+
+func TestLinkTokensAreKeptApartFromAccessTokens(t *testing.T) {
+	key := generateTestKey(t, "key-1", "eddsa")
+	service, err := services.NewTokenService(services.TokenConfig{Keys: []services.SigningKey{key}})
+	assert.NoError(t, err)
+
+	expiresAt := time.Now().Add(time.Minute)
+	link, err := service.CreateLinkToken(dto.LinkTokenDto{
+		Purpose:   "magic-link",
+		UserID:    "user-id",
+		Code:      "123456",
+		Binding:   "binding-hash",
+		ExpiresAt: expiresAt,
+	})
+	assert.NoError(t, err)
+
+	payload, err := service.VerifyLinkToken(link, "magic-link")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-id", payload.UserID)
+	assert.Equal(t, "123456", payload.Code)
+	assert.Equal(t, "binding-hash", payload.Binding)
+	assert.Equal(t, expiresAt.Unix(), payload.ExpiresAt.Unix())
+
+	_, err = service.VerifyLinkToken(link, "password-reset")
+	assert.Error(t, err, "Expected a link token to only be accepted for its purpose")
+	_, err = service.VerifyToken(link)
+	assert.Error(t, err, "Expected a link token not to be accepted as an access token")
+
+	access, err := service.CreateToken("user-id", expiresAt)
+	assert.NoError(t, err)
+	_, err = service.VerifyLinkToken(access, "magic-link")
+	assert.Error(t, err, "Expected an access token not to be accepted as a link token")
+}
+
+func TestLoadSigningKeys(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a-rsa", "b-eddsa"} {
+		algorithm := "rs256"
+		if name == "b-eddsa" {
+			algorithm = "eddsa"
+		}
+		content, err := services.GenerateSigningKey(algorithm)
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name+".pem"), content, 0o600))
+	}
+
+	keys, err := services.LoadSigningKeys(dir)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 2)
+	assert.Equal(t, "a-rsa", keys[0].ID)
+	assert.Equal(t, "RS256", keys[0].Method.Alg())
+	assert.Equal(t, "b-eddsa", keys[1].ID)
+	assert.Equal(t, "EdDSA", keys[1].Method.Alg())
+
+	_, err = services.NewTokenService(services.TokenConfig{Keys: keys, SigningKeyId: "missing"})
+	assert.Error(t, err)
+
+	_, err = services.LoadSigningKeys(t.TempDir())
+	assert.Error(t, err, "Expected an empty key directory to be rejected")
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// totpPeriod is how long a code stays the current one
+	totpPeriod = 30
+	totpDigits = 6
+	// totpSkew is how many periods before and after the current one are accepted,
+	// which covers clocks that drift apart a little
+	totpSkew = 1
+)
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTPService implements time based one time passwords as described in RFC 6238,
+// using the defaults authenticator apps expect: HMAC-SHA1, 6 digits and a 30 second period
+type TOTPService struct {
+	issuer string
+	now    func() time.Time
+}
+
+func NewTOTPService(issuer string) *TOTPService {
+	return NewTOTPServiceWithClock(issuer, time.Now)
+}
+
+// NewTOTPServiceWithClock creates a TOTP service reading the time from now
+func NewTOTPServiceWithClock(issuer string, now func() time.Time) *TOTPService {
+	return &TOTPService{issuer: issuer, now: now}
+}
+
+// GenerateSecret returns a new random 160 bit secret encoded in base32
+func (s TOTPService) GenerateSecret() (string, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return totpEncoding.EncodeToString(secret), nil
+}
+
+// URI returns the otpauth URI authenticator apps read from a QR code
+func (s TOTPService) URI(secret string, account string) string {
+	label := url.PathEscape(s.issuer + ":" + account)
+	query := url.Values{}
+	query.Set("secret", secret)
+	query.Set("issuer", s.issuer)
+	query.Set("algorithm", "SHA1")
+	query.Set("digits", fmt.Sprint(totpDigits))
+	query.Set("period", fmt.Sprint(totpPeriod))
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// GenerateCode returns the code of the secret at the given time
+func (s TOTPService) GenerateCode(secret string, at time.Time) (string, error) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil {
+		return "", err
+	}
+
+	return totpCode(key, at.Unix()/totpPeriod), nil
+}
+
+// Validate checks a code against the secret and returns the time step it was generated for
+func (s TOTPService) Validate(secret string, code string) (int64, bool) {
+	key, err := decodeTOTPSecret(secret)
+	if err != nil || len(code) != totpDigits {
+		return 0, false
+	}
+
+	current := s.now().Unix() / totpPeriod
+	for step := current - totpSkew; step <= current+totpSkew; step++ {
+		if subtle.ConstantTimeCompare([]byte(totpCode(key, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+func decodeTOTPSecret(secret string) ([]byte, error) {
+	return totpEncoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+}
+
+// totpCode computes the HOTP value (RFC 4226) of a key for a counter
+func totpCode(key []byte, counter int64) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%modulo)
+}
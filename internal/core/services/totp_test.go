@@ -0,0 +1,81 @@
+package services_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stretchr/testify/assert"
+)
+
+// rfcSecret is the SHA1 key from the RFC 6238 test vectors, "12345678901234567890", in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPMatchesRFCTestVectors(t *testing.T) {
+	service := services.NewTOTPService("Vise Resume")
+
+	// The RFC lists 8 digit codes, these are their last 6 digits
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := service.GenerateCode(rfcSecret, time.Unix(unix, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, unix)
+	}
+}
+
+func TestTOTPValidate(t *testing.T) {
+	clock := &testClock{now: time.Unix(1111111111, 0)}
+	service := services.NewTOTPServiceWithClock("Vise Resume", clock.Now)
+
+	step, ok := service.Validate(rfcSecret, "050471")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1111111111/30), step)
+
+	// Codes from the neighbouring periods are accepted to allow for clock drift
+	previous, err := service.GenerateCode(rfcSecret, clock.now.Add(-30*time.Second))
+	assert.NoError(t, err)
+	step, ok = service.Validate(rfcSecret, previous)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1111111111/30-1), step)
+
+	stale, err := service.GenerateCode(rfcSecret, clock.now.Add(-2*time.Minute))
+	assert.NoError(t, err)
+	_, ok = service.Validate(rfcSecret, stale)
+	assert.False(t, ok, "Expected codes from older periods to be rejected")
+
+	_, ok = service.Validate(rfcSecret, "12345")
+	assert.False(t, ok)
+	_, ok = service.Validate("not base32!", "050471")
+	assert.False(t, ok)
+}
+
+func TestTOTPSecretAndURI(t *testing.T) {
+	service := services.NewTOTPService("Vise Resume")
+
+	secret, err := service.GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 32)
+
+	other, err := service.GenerateSecret()
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	uri, err := url.Parse(service.URI(secret, "jane@example.com"))
+	assert.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.True(t, strings.HasPrefix(uri.Path, "/Vise Resume:jane@example.com"))
+	assert.Equal(t, secret, uri.Query().Get("secret"))
+	assert.Equal(t, "Vise Resume", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+const (
+	// TwoFactorChallengeTTL is how long a user has to give their second factor after their password
+	TwoFactorChallengeTTL = 5 * time.Minute
+	// twoFactorMaxAttempts is how many wrong codes discard a login challenge
+	twoFactorMaxAttempts = 5
+	recoveryCodeCount    = 10
+)
+
+var (
+	errTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	errTwoFactorNotSetUp    = errors.New("two-factor authentication has not been set up")
+	errInvalidTwoFactorCode = errors.New("two-factor code is invalid")
+	errInvalidChallenge     = errors.New("two-factor challenge is invalid or has expired")
+)
+
+// The [SetupTwoFactor] usecase generates a new TOTP secret for the user. The secret stays
+// pending, and replaceable, until the user confirms it with [EnableTwoFactor].
+func (s UserService) SetupTwoFactor(ctx context.Context, id string) (*dto.TwoFactorSetupResponse, error) {
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: id})
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.twoFactorPort.FindTwoFactor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if current != nil && current.EnabledAt != nil {
+		return nil, errTwoFactorEnabled
+	}
+
+	secret, err := s.totpService.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.twoFactorPort.SaveTwoFactor(ctx, domain.TwoFactor{UserId: id, Secret: secret})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorSetupResponse{
+		Secret: secret,
+		URI:    s.totpService.URI(secret, user.Email),
+	}, nil
+}
+
+// The [EnableTwoFactor] usecase turns two-factor authentication on once the user proves their
+// authenticator works with a first code. The recovery codes it returns are only shown this once.
+func (s UserService) EnableTwoFactor(ctx context.Context, id string, payload dto.TwoFactorCodeDto) (_ *dto.RecoveryCodesResponse, err error) {
+	defer func() { s.audit(ctx, domain.AuditEvent{Action: domain.AuditTwoFactorEnabled, ActorId: id}, err) }()
+
+	twoFactor, err := s.twoFactorPort.FindTwoFactor(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if twoFactor == nil {
+		return nil, errTwoFactorNotSetUp
+	}
+
+	if twoFactor.EnabledAt != nil {
+		return nil, errTwoFactorEnabled
+	}
+
+	step, ok := s.totpService.Validate(twoFactor.Secret, payload.Code)
+	if !ok {
+		return nil, errInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes(twoFactor.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.twoFactorPort.UpdateTwoFactor(ctx, id, map[string]interface{}{
+			"enabled_at":      time.Now(),
+			"last_used_step":  step,
+			"failed_attempts": 0,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.twoFactorPort.ReplaceRecoveryCodes(ctx, id, hashes)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// The [VerifyTwoFactor] usecase completes a login started with [LoginUser] by answering its
+// challenge with a TOTP code or one of the recovery codes. Too many wrong codes discard the
+// challenge and the user has to log in again.
+func (s UserService) VerifyTwoFactor(ctx context.Context, payload dto.TwoFactorVerifyDto) (_ *dto.LoginResponse, err error) {
+	event := domain.AuditEvent{Action: domain.AuditTwoFactorLogin}
+	defer func() { s.audit(ctx, event, err) }()
+
+	challenge, err := s.userPort.FindToken(ctx, dto.ManageTokenDto{
+		AccessToken: utils.HashToken(payload.ChallengeToken),
+		Type:        "2fa-challenge",
+	})
+	if err != nil {
+		utils.TextLogger.Error("two-factor challenge not found", "error", err)
+		return nil, errInvalidChallenge
+	}
+	event.ActorId = challenge.UserId
+
+	if challenge.ExpiresAt == nil || challenge.ExpiresAt.Before(time.Now()) {
+		s.discardChallenge(ctx, *challenge)
+		return nil, errInvalidChallenge
+	}
+
+	twoFactor, err := s.twoFactorPort.FindTwoFactor(ctx, challenge.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if twoFactor == nil || twoFactor.EnabledAt == nil {
+		s.discardChallenge(ctx, *challenge)
+		return nil, errInvalidChallenge
+	}
+
+	// The attempt is counted before the code is checked, so concurrent guesses can not go past the limit
+	ok, err := s.twoFactorPort.ConsumeAttempt(ctx, challenge.UserId, twoFactorMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.discardExhaustedChallenge(ctx, *challenge)
+		return nil, errInvalidChallenge
+	}
+
+	if err := s.checkSecondFactor(ctx, *twoFactor, payload.Code); err != nil {
+		utils.TextLogger.Error("two-factor code mismatch", "user", twoFactor.UserId)
+		if twoFactor.FailedAttempts+1 >= twoFactorMaxAttempts {
+			s.discardExhaustedChallenge(ctx, *challenge)
+		}
+		return nil, err
+	}
+
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: challenge.UserId})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkAccountStatus(*user); err != nil {
+		return nil, err
+	}
+
+	var tokens *dto.TokenResponse
+	err = s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		// A challenge answered by concurrent requests only logs one of them in
+		if err := s.userPort.ConsumeToken(ctx, challenge.ID); err != nil {
+			return errInvalidChallenge
+		}
+
+		issued, err := s.issueTokens(ctx, user.ID, uuid.NewString())
+		if err != nil {
+			return err
+		}
+
+		tokens = issued
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.cancelAccountDeletion(ctx, *user)
+
+	response := dto.LoginResponse{
+		User: dto.UserResponseDto{
+			ID:       user.ID,
+			FullName: user.FullName,
+			Email:    user.Email,
+		},
+		Token: tokens,
+	}
+	if user.EmailVerifiedAt != nil {
+		response.User.EmailVerifiedAt = *user.EmailVerifiedAt
+	}
+
+	return &response, nil
+}
+
+// createTwoFactorChallenge issues the short lived token a user trades for their tokens with a second factor
+func (s UserService) createTwoFactorChallenge(ctx context.Context, userId string) (*dto.TwoFactorChallengeResponse, error) {
+	token := utils.GenerateSecureToken(32)
+	expiresAt := time.Now().Add(TwoFactorChallengeTTL)
+
+	err := s.userPort.CreateToken(ctx, dto.ManageTokenDto{
+		ID:          userId,
+		AccessToken: utils.HashToken(token),
+		Type:        "2fa-challenge",
+		ExpiresAt:   &expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TwoFactorChallengeResponse{
+		ChallengeToken: token,
+		ExpiresIn:      int(TwoFactorChallengeTTL.Seconds()),
+	}, nil
+}
+
+// checkSecondFactor accepts a TOTP code that was not used before, or an unused recovery code
+func (s UserService) checkSecondFactor(ctx context.Context, twoFactor domain.TwoFactor, code string) error {
+	if len(code) == totpDigits {
+		step, ok := s.totpService.Validate(twoFactor.Secret, code)
+		if !ok || step <= twoFactor.LastUsedStep {
+			return errInvalidTwoFactorCode
+		}
+
+		// The step read above can be stale, the update only succeeds if no one used it since
+		if err := s.twoFactorPort.UseTOTPStep(ctx, twoFactor.UserId, step); err != nil {
+			return errInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	// Recovery codes are looked up by their hash, which is keyed so it is quick to compute but
+	// can not be reversed without the secret
+	hash := recoveryCodeHash(twoFactor.Secret, normalizeRecoveryCode(code))
+	if err := s.twoFactorPort.UseRecoveryCode(ctx, twoFactor.UserId, hash); err != nil {
+		return errInvalidTwoFactorCode
+	}
+
+	utils.TextLogger.Info("recovery code used", "user", twoFactor.UserId)
+	return s.twoFactorPort.UpdateTwoFactor(ctx, twoFactor.UserId, map[string]interface{}{"failed_attempts": 0})
+}
+
+// discardExhaustedChallenge discards a challenge that got too many wrong codes and clears the
+// failed attempts, so the user gets a fresh set of them once they log in again
+func (s UserService) discardExhaustedChallenge(ctx context.Context, challenge domain.Token) {
+	s.discardChallenge(ctx, challenge)
+
+	err := s.twoFactorPort.UpdateTwoFactor(ctx, challenge.UserId, map[string]interface{}{"failed_attempts": 0})
+	if err != nil {
+		utils.TextLogger.Error("unable to reset two-factor attempts", "error", err)
+	}
+}
+
+func (s UserService) discardChallenge(ctx context.Context, challenge domain.Token) {
+	if err := s.userPort.DeleteTokenFamily(ctx, challenge.FamilyId, "2fa-challenge"); err != nil {
+		utils.TextLogger.Error("unable to discard two-factor challenge", "error", err)
+	}
+}
+
+// generateRecoveryCodes returns new recovery codes formatted for the user, along with their
+// hashes keyed with the TOTP secret
+func generateRecoveryCodes(secret string) ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		random := make([]byte, 7)
+		if _, err := rand.Read(random); err != nil {
+			return nil, nil, err
+		}
+
+		code := strings.ToLower(totpEncoding.EncodeToString(random))[:10]
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, recoveryCodeHash(secret, code))
+	}
+
+	return codes, hashes, nil
+}
+
+// recoveryCodeHash is the HMAC-SHA256 of a normalized recovery code keyed with the TOTP secret
+func recoveryCodeHash(secret string, code string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// normalizeRecoveryCode lets users type recovery codes without the dash or in upper case
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
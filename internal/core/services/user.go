@@ -3,39 +3,94 @@ package services
 import (
 	"context"
 	"errors"
-	"fmt"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/stivo-m/vise-resume/internal/core/domain"
 	"github.com/stivo-m/vise-resume/internal/core/dto"
 	"github.com/stivo-m/vise-resume/internal/core/ports"
 	"github.com/stivo-m/vise-resume/internal/core/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	// AccessTokenTTL is how long an access token can be used before it has to be refreshed
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL is how long a refresh token can be exchanged for a new token pair
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+var (
+	errInvalidRefreshToken = errors.New("refresh token is invalid or has expired")
+	errRefreshTokenReused  = errors.New("refresh token has already been used, the session was revoked")
+	errAccountDisabled     = errors.New("this account has been disabled")
+	errResetRequired       = errors.New("a password reset is required, check your email for a reset code")
 )
 
 type UserService struct {
-	userPort         ports.UserPort
-	tokenService     ports.TokenService
-	passwordService  ports.PasswordService
-	verificationPort ports.VerificationPort
+	unitOfWork          ports.UnitOfWork
+	userPort            ports.UserPort
+	tokenService        ports.TokenService
+	passwordService     ports.PasswordService
+	passwordPolicy      ports.PasswordPolicy
+	verificationPort    ports.VerificationPort
+	verificationService ports.VerificationService
+	twoFactorPort       ports.TwoFactorPort
+	totpService         ports.TOTPService
+	identityPort        ports.IdentityPort
+	identityProviders   map[string]ports.IdentityProvider
+	auditPort           ports.AuditPort
+	mailer              ports.Mailer
+	magicLinkURL        string
 }
 
 func NewUserService(
+	unitOfWork ports.UnitOfWork,
 	userPort ports.UserPort,
 	tokenService ports.TokenService,
 	passwordService ports.PasswordService,
+	passwordPolicy ports.PasswordPolicy,
 	verificationPort ports.VerificationPort,
-
+	verificationService ports.VerificationService,
+	twoFactorPort ports.TwoFactorPort,
+	totpService ports.TOTPService,
+	identityPort ports.IdentityPort,
+	identityProviders []ports.IdentityProvider,
+	auditPort ports.AuditPort,
+	mailer ports.Mailer,
+	magicLinkURL string,
 ) *UserService {
+	providers := make(map[string]ports.IdentityProvider, len(identityProviders))
+	for _, provider := range identityProviders {
+		providers[provider.Name()] = provider
+	}
+
 	return &UserService{
-		userPort:         userPort,
-		tokenService:     tokenService,
-		passwordService:  passwordService,
-		verificationPort: verificationPort,
+		unitOfWork:          unitOfWork,
+		userPort:            userPort,
+		tokenService:        tokenService,
+		passwordService:     passwordService,
+		passwordPolicy:      passwordPolicy,
+		verificationPort:    verificationPort,
+		verificationService: verificationService,
+		twoFactorPort:       twoFactorPort,
+		totpService:         totpService,
+		identityPort:        identityPort,
+		identityProviders:   providers,
+		auditPort:           auditPort,
+		mailer:              mailer,
+		magicLinkURL:        magicLinkURL,
 	}
 }
 
 // The [RegisterUser] usecase is primarily to have a user created for the system
-func (s UserService) RegisterUser(ctx context.Context, payload dto.RegisterDto) (*dto.ProfileResponse, error) {
+func (s UserService) RegisterUser(ctx context.Context, payload dto.RegisterDto) (_ *dto.ProfileResponse, err error) {
+	event := domain.AuditEvent{Action: domain.AuditUserRegistered}
+	defer func() { s.audit(ctx, event, err) }()
+
+	if err := s.passwordPolicy.Check(payload.Password, payload.Email, payload.FullName); err != nil {
+		return nil, err
+	}
 
 	password, err := s.passwordService.HashPassword(payload.Password)
 	if err != nil {
@@ -48,22 +103,28 @@ func (s UserService) RegisterUser(ctx context.Context, payload dto.RegisterDto)
 		Password: domain.Password{Value: password},
 	}
 
-	user, err := s.userPort.CreateUser(ctx, userData)
-	if err != nil {
-		return nil, err
-	}
-
-	// TODO: Send verification email to the user
-	code := utils.EncodeToString(6)
-	err = s.verificationPort.CreateCode(ctx, dto.VerificationDto{
-		UserID: user.ID,
-		Code:   code,
-		Type:   "email-verification",
+	var user *domain.User
+	err = s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		created, err := s.userPort.CreateUser(ctx, userData)
+		if err != nil {
+			return err
+		}
+
+		// The email is sent last so a delivery failure rolls the registration back
+		// and the user can register again with the same address
+		err = s.sendCode(ctx, *created, "email-verification")
+		if err != nil {
+			return err
+		}
+
+		user = created
+		return nil
 	})
 
 	if err != nil {
 		return nil, err
 	}
+	event.ActorId = user.ID
 
 	response := dto.ProfileResponse{
 		User: dto.UserResponseDto{
@@ -78,7 +139,10 @@ func (s UserService) RegisterUser(ctx context.Context, payload dto.RegisterDto)
 
 // The [LoginUser] usecase is primarily used to ensure a user can be authenticated by the system
 // This function should verify credentials used and provide the user with a token for their identity.
-func (s UserService) LoginUser(ctx context.Context, payload dto.LoginDto) (*dto.LoginResponse, error) {
+func (s UserService) LoginUser(ctx context.Context, payload dto.LoginDto) (response *dto.LoginResponse, err error) {
+	event := domain.AuditEvent{Action: domain.AuditLogin}
+	defer func() { s.auditLogin(ctx, event, response, err) }()
+
 	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{
 		Email:        payload.Email,
 		WithPassword: true,
@@ -89,9 +153,10 @@ func (s UserService) LoginUser(ctx context.Context, payload dto.LoginDto) (*dto.
 	}
 
 	if user.ID == "" {
-		utils.TextLogger.Error("User not found", fmt.Errorf("user: %v", user))
+		utils.TextLogger.Error("User not found", "user", user.ID)
 		return nil, errors.New("either user was not found or password is incorrect")
 	}
+	event.ActorId = user.ID
 
 	if user.EmailVerifiedAt == nil {
 		return nil, errors.New("email address is not verified")
@@ -99,18 +164,38 @@ func (s UserService) LoginUser(ctx context.Context, payload dto.LoginDto) (*dto.
 
 	match := s.passwordService.VerifyPassword(payload.Password, user.Password.Value)
 	if !match {
-		utils.TextLogger.Error("password mismatch", fmt.Errorf("user: %v", user))
+		utils.TextLogger.Error("password mismatch", "user", user.ID)
 		return nil, errors.New("either user was not found or password is incorrect")
 	}
 
-	expiry := time.Now().Add(time.Hour * 24 * 7)
-	token, err := s.tokenService.CreateToken(user.ID, expiry)
-	if err != nil {
-		return nil, err
+	if s.passwordService.NeedsRehash(user.Password.Value) {
+		s.rehashPassword(ctx, user.ID, payload.Password)
+	}
+
+	return s.completeLogin(ctx, *user)
+}
+
+// rehashPassword replaces the hash of a password that was just verified with one made the way
+// new passwords are hashed. A failure is only logged, the old hash keeps working.
+func (s UserService) rehashPassword(ctx context.Context, userId string, password string) {
+	hash, err := s.passwordService.HashPassword(password)
+	if err == nil {
+		err = s.userPort.UpdateUserPassword(ctx, userId, domain.Password{Value: hash})
 	}
 
-	err = s.userPort.CreateToken(ctx, dto.ManageTokenDto{ID: user.ID, AccessToken: token})
 	if err != nil {
+		utils.TextLogger.Error("unable to rehash password", "user", userId, "error", err)
+		return
+	}
+
+	utils.TextLogger.Info("password rehashed", "user", userId)
+}
+
+// completeLogin issues tokens to a user with a verified email address once they proved who they
+// are, or a challenge when they have two-factor authentication enabled. Logging in cancels a
+// pending account deletion.
+func (s UserService) completeLogin(ctx context.Context, user domain.User) (*dto.LoginResponse, error) {
+	if err := checkAccountStatus(user); err != nil {
 		return nil, err
 	}
 
@@ -121,15 +206,167 @@ func (s UserService) LoginUser(ctx context.Context, payload dto.LoginDto) (*dto.
 			Email:           user.Email,
 			EmailVerifiedAt: *user.EmailVerifiedAt,
 		},
-		Token: dto.TokenResponse{
-			Type:        "Bearer",
-			AccessToken: token,
+	}
+
+	twoFactor, err := s.twoFactorPort.FindTwoFactor(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users with two-factor authentication only get a challenge until they give a code
+	if twoFactor != nil && twoFactor.EnabledAt != nil {
+		challenge, err := s.createTwoFactorChallenge(ctx, user.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		response.TwoFactor = challenge
+		return &response, nil
+	}
+
+	tokens, err := s.issueTokens(ctx, user.ID, uuid.NewString())
+	if err != nil {
+		return nil, err
+	}
+	response.Token = tokens
+	s.cancelAccountDeletion(ctx, user)
+
+	return &response, nil
+}
+
+// The [RefreshToken] usecase exchanges a refresh token for a new access and refresh token pair.
+// Every refresh token can only be used once; presenting a used one means it has leaked, so the
+// whole family of tokens issued by that login is revoked.
+func (s UserService) RefreshToken(ctx context.Context, payload dto.RefreshTokenDto) (*dto.LoginResponse, error) {
+	record, err := s.userPort.FindToken(ctx, dto.ManageTokenDto{
+		AccessToken: utils.HashToken(payload.RefreshToken),
+		Type:        "refresh",
+	})
+	if err != nil {
+		utils.TextLogger.Error("refresh token not found", "error", err)
+		return nil, errInvalidRefreshToken
+	}
+
+	if record.UsedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, *record)
+	}
+
+	if record.ExpiresAt == nil || record.ExpiresAt.Before(time.Now()) {
+		return nil, errInvalidRefreshToken
+	}
+
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: record.UserId})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkAccountStatus(*user); err != nil {
+		return nil, err
+	}
+
+	var tokens *dto.TokenResponse
+	err = s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Only a token someone else used in the meantime means reuse, other errors are passed on
+		// so a failing database does not log the user out everywhere
+		if err := s.userPort.MarkTokenUsed(ctx, record.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errRefreshTokenReused
+			}
+			return err
+		}
+
+		// Access tokens issued earlier in the family are replaced by the new one
+		if err := s.userPort.DeleteTokenFamily(ctx, record.FamilyId, "access"); err != nil {
+			return err
+		}
+
+		issued, err := s.issueTokens(ctx, user.ID, record.FamilyId)
+		if err != nil {
+			return err
+		}
+
+		tokens = issued
+		return nil
+	})
+
+	if errors.Is(err, errRefreshTokenReused) {
+		return nil, s.revokeReusedFamily(ctx, *record)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := dto.LoginResponse{
+		User: dto.UserResponseDto{
+			ID:       user.ID,
+			FullName: user.FullName,
+			Email:    user.Email,
 		},
+		Token: tokens,
+	}
+	if user.EmailVerifiedAt != nil {
+		response.User.EmailVerifiedAt = *user.EmailVerifiedAt
 	}
 
 	return &response, nil
 }
 
+// issueTokens creates a short lived access token and a long lived refresh token in the given family
+func (s UserService) issueTokens(ctx context.Context, userId string, familyId string) (*dto.TokenResponse, error) {
+	now := time.Now()
+	accessExpiry := now.Add(AccessTokenTTL)
+	refreshExpiry := now.Add(RefreshTokenTTL)
+
+	accessToken, err := s.tokenService.CreateToken(userId, accessExpiry)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken := utils.GenerateSecureToken(32)
+
+	err = s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.userPort.CreateToken(ctx, dto.ManageTokenDto{
+			ID:          userId,
+			AccessToken: accessToken,
+			Type:        "access",
+			FamilyId:    familyId,
+			ExpiresAt:   &accessExpiry,
+		})
+		if err != nil {
+			return err
+		}
+
+		return s.userPort.CreateToken(ctx, dto.ManageTokenDto{
+			ID:          userId,
+			AccessToken: utils.HashToken(refreshToken),
+			Type:        "refresh",
+			FamilyId:    familyId,
+			ExpiresAt:   &refreshExpiry,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TokenResponse{
+		Type:         "Bearer",
+		AccessToken:  accessToken,
+		ExpiresIn:    int(AccessTokenTTL.Seconds()),
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// revokeReusedFamily revokes every token of a family after one of its refresh tokens was reused
+func (s UserService) revokeReusedFamily(ctx context.Context, token domain.Token) error {
+	utils.TextLogger.Warn("refresh token reuse detected, revoking token family", "user", token.UserId, "family", token.FamilyId)
+
+	if err := s.userPort.DeleteTokenFamily(ctx, token.FamilyId, ""); err != nil {
+		utils.TextLogger.Error("unable to revoke token family", "error", err)
+		return err
+	}
+
+	return errRefreshTokenReused
+}
+
 // The [UpdateUser] usecase allows for users to update their bio-data when needed
 func (s UserService) UpdateUser(ctx context.Context, id string, updates map[string]interface{}) error {
 	err := s.userPort.UpdateUser(ctx, id, updates)
@@ -142,78 +379,225 @@ func (s UserService) UpdateUser(ctx context.Context, id string, updates map[stri
 }
 
 // The [ForgetPassword] usecase allows for a user to forget a password and
-// get a verification code sent to their email to reset the password
+// get a verification code sent to their email to reset the password. The caller is never told
+// whether a code was sent, so unknown addresses, requests during the resend cooldown and
+// failures only get logged.
 func (s UserService) ForgetPassword(ctx context.Context, payload dto.EmailDto) error {
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{Email: payload.Email})
+	if err != nil || user.ID == "" {
+		utils.TextLogger.Info("password reset requested for an unknown email address")
+		return nil
+	}
+
+	err = s.sendCode(ctx, *user, "password-reset")
+	s.audit(ctx, domain.AuditEvent{Action: domain.AuditPasswordResetRequested, ActorId: user.ID}, err)
+	if err != nil {
+		utils.TextLogger.Error("unable to send password reset code", "user", user.ID, "error", err)
+	}
+
+	return nil
+}
+
+// The [ResendVerification] usecase sends a new email verification code to a user
+// who has not verified their email address yet. Like [ForgetPassword] it answers the same way
+// whether a code was sent or not.
+func (s UserService) ResendVerification(ctx context.Context, payload dto.EmailDto) error {
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{Email: payload.Email})
+	if err != nil || user.ID == "" {
+		utils.TextLogger.Info("verification code requested for an unknown email address")
+		return nil
+	}
+
+	if user.EmailVerifiedAt != nil {
+		utils.TextLogger.Info("verification code requested for a verified email address", "user", user.ID)
+		return nil
+	}
+
+	if err := s.sendCode(ctx, *user, "email-verification"); err != nil {
+		utils.TextLogger.Error("unable to resend verification code", "user", user.ID, "error", err)
+	}
+
+	return nil
+}
+
+// The [ResetPassword] usecase allows for a user to reset their password
+func (s UserService) ResetPassword(ctx context.Context, payload dto.ResetPasswordDto) (err error) {
+	event := domain.AuditEvent{Action: domain.AuditPasswordReset}
+	defer func() { s.audit(ctx, event, err) }()
+
 	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{Email: payload.Email})
 	if err != nil {
+		utils.TextLogger.Error("user not found", "error", err)
+		return errInvalidCode
+	}
+	event.ActorId = user.ID
+
+	if err := s.passwordPolicy.Check(payload.Password, user.Email, user.FullName); err != nil {
 		return err
 	}
 
-	// TODO: Send reset password to the user
-	code := utils.EncodeToString(6)
-	err = s.verificationPort.CreateCode(ctx, dto.VerificationDto{
+	verificationCode, err := s.verificationService.VerifyCode(ctx, dto.VerificationDto{
 		UserID: user.ID,
-		Code:   code,
+		Code:   payload.Code,
 		Type:   "password-reset",
 	})
+	if err != nil {
+		return err
+	}
 
+	password, err := s.passwordService.HashPassword(payload.Password)
 	if err != nil {
+		utils.TextLogger.Error("password mismatch", "error", err)
 		return err
 	}
 
-	return nil
+	updates := domain.Password{Value: password}
+	return s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.userPort.UpdateUserPassword(ctx, user.ID, updates)
+		if err != nil {
+			utils.TextLogger.Error("update user password failed", "error", err)
+			return err
+		}
+
+		if user.PasswordResetRequired {
+			err = s.userPort.UpdateUser(ctx, user.ID, map[string]interface{}{"password_reset_required": false})
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.verificationPort.DeleteCode(ctx, verificationCode.ID)
+	})
 }
 
-// The [ResetPassword] usecase allows for a user to reset their password
-func (s UserService) ResetPassword(ctx context.Context, payload dto.ResetPasswordDto) error {
+// The [ChangePassword] usecase lets a logged in user choose a new password by confirming their current
+// one. Their other sessions can be signed out at the same time, keeping the one making the request.
+func (s UserService) ChangePassword(ctx context.Context, id string, currentToken string, payload dto.ChangePasswordDto) (err error) {
+	defer func() { s.audit(ctx, domain.AuditEvent{Action: domain.AuditPasswordChanged, ActorId: id}, err) }()
 
-	verificationCode, err := s.verificationPort.FindCode(ctx, dto.VerificationDto{Code: payload.Code, Type: "password-reset"})
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: id, WithPassword: true})
 	if err != nil {
-		utils.TextLogger.Error("verification code not found", err)
 		return err
 	}
 
-	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: verificationCode.UserId})
+	if !s.passwordService.VerifyPassword(payload.CurrentPassword, user.Password.Value) {
+		utils.TextLogger.Error("password mismatch", "user", user.ID)
+		return errors.New("current password is incorrect")
+	}
+
+	if payload.Password == payload.CurrentPassword {
+		return errors.New("the new password must be different from the current one")
+	}
+
+	if err := s.passwordPolicy.Check(payload.Password, user.Email, user.FullName); err != nil {
+		return err
+	}
+
+	password, err := s.passwordService.HashPassword(payload.Password)
 	if err != nil {
-		utils.TextLogger.Error("user not found", err)
 		return err
 	}
 
-	if user == nil {
-		utils.TextLogger.Error("user not found, i.e returning nil with no error")
-		return errors.New("either code is invalid or user does not exist")
+	return s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userPort.UpdateUserPassword(ctx, user.ID, domain.Password{Value: password}); err != nil {
+			utils.TextLogger.Error("update user password failed", "error", err)
+			return err
+		}
+
+		if !payload.LogoutOtherSessions {
+			return nil
+		}
+
+		return s.logoutAll(ctx, user.ID, currentToken, true)
+	})
+}
+
+// The [LogoutUser] usecase should delete a users token and de-authenticate them immediately.
+// The refresh token issued with the access token is revoked as well.
+func (s UserService) LogoutUser(ctx context.Context, token string) (err error) {
+	event := domain.AuditEvent{Action: domain.AuditLogout}
+	defer func() { s.audit(ctx, event, err) }()
+
+	record, err := s.userPort.FindToken(ctx, dto.ManageTokenDto{AccessToken: token, Type: "access"})
+	if err == nil {
+		event.ActorId = record.UserId
 	}
 
-	if payload.Code != verificationCode.Code {
-		utils.TextLogger.Error(fmt.Sprintf(
-			"verification code mismatch. Given %v, expecting %v", payload.Code, verificationCode.Code,
-		))
-		return errors.New("either code is invalid or user does not exist")
+	if err == nil && record.FamilyId != "" {
+		err = s.userPort.DeleteTokenFamily(ctx, record.FamilyId, "")
+	} else {
+		err = s.userPort.DeleteToken(ctx, dto.ManageTokenDto{AccessToken: token})
 	}
 
-	password, err := s.passwordService.HashPassword(payload.Password)
 	if err != nil {
-		utils.TextLogger.Error("password mismatch", err)
+		utils.TextLogger.Error("unable to delete user token", "error", err)
 		return err
 	}
 
-	updates := domain.Password{Value: password}
-	err = s.userPort.UpdateUserPassword(ctx, user.ID, updates)
+	return nil
+}
+
+// The [ListSessions] usecase lists the logins of a user, marking the one making the request
+func (s UserService) ListSessions(ctx context.Context, id string, currentToken string) ([]dto.SessionDto, error) {
+	tokens, err := s.userPort.FindTokens(ctx, dto.ManageTokenDto{ID: id, Type: "access"})
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]dto.SessionDto, 0, len(tokens))
+	for _, token := range tokens {
+		sessions = append(sessions, dto.SessionDto{
+			ID:         token.ID,
+			IpAddress:  token.IpAddress,
+			UserAgent:  token.UserAgent,
+			CreatedAt:  token.CreatedAt,
+			LastUsedAt: token.LastUsedAt,
+			ExpiresAt:  token.ExpiresAt,
+			Current:    token.AccessToken == currentToken,
+		})
+	}
+
+	return sessions, nil
+}
+
+// The [RevokeSession] usecase signs one of the user's logins out, including its refresh token
+func (s UserService) RevokeSession(ctx context.Context, id string, sessionId string) (err error) {
+	defer func() { s.audit(ctx, domain.AuditEvent{Action: domain.AuditSessionRevoked, ActorId: id}, err) }()
+
+	tokens, err := s.userPort.FindTokens(ctx, dto.ManageTokenDto{ID: id, Type: "access"})
 	if err != nil {
-		utils.TextLogger.Error("update user password failed", err)
 		return err
 	}
 
-	_ = s.verificationPort.DeleteCode(ctx, verificationCode.ID)
+	for _, token := range tokens {
+		if token.ID == sessionId {
+			return s.userPort.DeleteTokenFamily(ctx, token.FamilyId, "")
+		}
+	}
 
-	return nil
+	return errors.New("session not found")
 }
 
-// The [LogoutUser] usecase should delete a users token and de-authenticate them immediately
-func (s UserService) LogoutUser(ctx context.Context, token string) error {
-	err := s.userPort.DeleteToken(ctx, dto.ManageTokenDto{AccessToken: token})
+// The [LogoutAll] usecase signs the user out everywhere, optionally keeping the current login
+func (s UserService) LogoutAll(ctx context.Context, id string, currentToken string, keepCurrent bool) (err error) {
+	defer func() { s.audit(ctx, domain.AuditEvent{Action: domain.AuditLogoutAll, ActorId: id}, err) }()
+
+	return s.logoutAll(ctx, id, currentToken, keepCurrent)
+}
+
+func (s UserService) logoutAll(ctx context.Context, id string, currentToken string, keepCurrent bool) error {
+	exceptFamilyId := ""
+	if keepCurrent {
+		current, err := s.userPort.FindToken(ctx, dto.ManageTokenDto{ID: id, AccessToken: currentToken, Type: "access"})
+		if err != nil {
+			return err
+		}
+		exceptFamilyId = current.FamilyId
+	}
+
+	err := s.userPort.DeleteUserTokens(ctx, id, exceptFamilyId)
 	if err != nil {
-		utils.TextLogger.Error("unable to delete user token", err)
+		utils.TextLogger.Error("unable to delete user tokens", "error", err)
 		return err
 	}
 
@@ -233,41 +617,88 @@ func (s UserService) ShowProfile(ctx context.Context, id string) (*dto.UserRespo
 	}, nil
 }
 
-func (s UserService) VerifyEmailAddress(ctx context.Context, payload dto.VerificationDto) error {
-	verificationCode, err := s.verificationPort.FindCode(ctx, payload)
+func (s UserService) VerifyEmailAddress(ctx context.Context, payload dto.VerificationDto) (err error) {
+	event := domain.AuditEvent{Action: domain.AuditEmailVerified}
+	defer func() { s.audit(ctx, event, err) }()
+
+	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{Email: payload.Email})
 	if err != nil {
-		utils.TextLogger.Error("verification code not found", err)
-		return err
+		utils.TextLogger.Error("unable to find the user", "error", err)
+		return errInvalidCode
 	}
+	event.ActorId = user.ID
 
-	user, err := s.userPort.FindUser(ctx, dto.FindUserDto{ID: verificationCode.UserId})
+	verificationCode, err := s.verificationService.VerifyCode(ctx, dto.VerificationDto{
+		UserID: user.ID,
+		Code:   payload.Code,
+		Type:   "email-verification",
+	})
 	if err != nil {
-		utils.TextLogger.Error("unable to find the user", err)
 		return err
 	}
 
-	if user == nil {
-		utils.TextLogger.Error("user not found, i.e returning nil with no error")
-		return errors.New("either code is invalid or user does not exist")
+	updates := map[string]interface{}{
+		"email_verified_at": time.Now(),
 	}
 
-	if payload.Code != verificationCode.Code {
-		utils.TextLogger.Error(fmt.Sprintf(
-			"verification code mismatch. Given %v, expecting %v", payload.Code, verificationCode.Code,
-		))
-		return errors.New("either code is invalid or user does not exist")
+	return s.unitOfWork.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := s.userPort.UpdateUser(ctx, user.ID, updates)
+		if err != nil {
+			utils.TextLogger.Error("update user failed", "error", err)
+			return err
+		}
+
+		return s.verificationPort.DeleteCode(ctx, verificationCode.ID)
+	})
+}
+
+// checkAccountStatus refuses logins to accounts an administrator disabled or asked to reset their password
+func checkAccountStatus(user domain.User) error {
+	if user.DisabledAt != nil {
+		return errAccountDisabled
 	}
 
-	updates := map[string]interface{}{
-		"email_verified_at": time.Now(),
+	if user.PasswordResetRequired {
+		return errResetRequired
 	}
 
-	err = s.userPort.UpdateUser(ctx, user.ID, updates)
+	return nil
+}
+
+// cancelAccountDeletion keeps the account of a user who logs in during the deletion grace period
+func (s UserService) cancelAccountDeletion(ctx context.Context, user domain.User) {
+	if user.DeletionRequestedAt == nil {
+		return
+	}
+
+	if err := s.userPort.UpdateUser(ctx, user.ID, map[string]interface{}{"deletion_requested_at": nil}); err != nil {
+		utils.TextLogger.Error("unable to cancel account deletion", "user", user.ID, "error", err)
+		return
+	}
+
+	utils.TextLogger.Info("account deletion cancelled", "user", user.ID)
+}
+
+// sendCode generates a verification code and emails it to the user using
+// the mail template named after the code type
+func (s UserService) sendCode(ctx context.Context, user domain.User, codeType string) error {
+	code, err := s.verificationService.GenerateCode(ctx, dto.VerificationDto{UserID: user.ID, Type: codeType, SentTo: user.Email})
+	if err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, dto.MailDto{
+		To:       user.Email,
+		Template: codeType,
+		Data: map[string]interface{}{
+			"Name": user.FullName,
+			"Code": code,
+		},
+	})
 	if err != nil {
-		utils.TextLogger.Error("update user failed", err)
+		utils.TextLogger.Error("unable to send verification code", "user", user.ID, "type", codeType, "error", err)
 		return err
 	}
-	_ = s.verificationPort.DeleteCode(ctx, verificationCode.ID)
 
 	return nil
 }
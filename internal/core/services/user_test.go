@@ -0,0 +1,79 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/breached"
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/database/repository"
+	"github.com/stivo-m/vise-resume/internal/adapters/mail"
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// setupUserService creates a user service on db that reaches the users through userPort
+func setupUserService(t *testing.T, db *database.DB, userPort ports.UserPort) *services.UserService {
+	tokenService, err := services.NewTokenServiceFromConfig(config.TokenConfig{SecretKey: "mockValue"})
+	assert.NoError(t, err)
+	mailer, err := mail.NewMailer("Vise Resume <no-reply@example.com>", mail.NewOutbox())
+	assert.NoError(t, err)
+
+	verificationRepo := repository.NewVerificationRepository(db)
+	return services.NewUserService(
+		db,
+		userPort,
+		tokenService,
+		services.NewPasswordService(),
+		services.DefaultPasswordPolicy(breached.NewBundledList()),
+		verificationRepo,
+		services.NewVerificationService(verificationRepo, services.DefaultVerificationPolicies()),
+		repository.NewTwoFactorRepository(db),
+		services.NewTOTPService("Vise Resume"),
+		repository.NewIdentityRepository(db),
+		nil,
+		repository.NewAuditRepository(db),
+		mailer,
+		"http://localhost/magic-link",
+	)
+}
+
+// unreachableTokenPort fails to mark tokens used the way a database that went away does
+type unreachableTokenPort struct {
+	ports.UserPort
+}
+
+func (p unreachableTokenPort) MarkTokenUsed(ctx context.Context, id string) error {
+	return errors.New("connection reset by peer")
+}
+
+func TestRefreshTokenKeepsTheSessionWhenTheDatabaseFails(t *testing.T) {
+	t.Setenv("TOKEN_SECRET_KEY", "mockValue")
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	user, _, err := test.GetAuthenticatedTestUser(db)
+	assert.NoError(t, err)
+	userRepo := repository.NewUserRepository(db)
+	login, err := setupUserService(t, db, userRepo).LoginUser(ctx, dto.LoginDto{Email: user.Email, Password: "password"})
+	assert.NoError(t, err)
+
+	failing := setupUserService(t, db, unreachableTokenPort{UserPort: userRepo})
+	_, err = failing.RefreshToken(ctx, dto.RefreshTokenDto{RefreshToken: login.Token.RefreshToken})
+	assert.ErrorContains(t, err, "connection reset by peer")
+
+	var count int64
+	db.Db.Model(&domain.Token{}).Where("user_id = ? AND type = ?", user.ID, "refresh").Count(&count)
+	assert.Equal(t, int64(1), count, "Expected the token family to be kept")
+
+	refreshed, err := setupUserService(t, db, userRepo).RefreshToken(ctx, dto.RefreshTokenDto{RefreshToken: login.Token.RefreshToken})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, refreshed.Token.AccessToken)
+}
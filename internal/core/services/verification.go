@@ -1,16 +1,178 @@
 package services
 
-import "github.com/stivo-m/vise-resume/internal/core/ports"
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+var errInvalidCode = errors.New("either code is invalid or has expired")
+
+// VerificationPolicy controls how long the codes of one type stay valid,
+// how many wrong guesses they tolerate and how often they can be resent
+type VerificationPolicy struct {
+	TTL            time.Duration
+	MaxAttempts    int
+	ResendCooldown time.Duration
+}
+
+// DefaultVerificationPolicies returns the policies used for every verification type
+func DefaultVerificationPolicies() map[string]VerificationPolicy {
+	return map[string]VerificationPolicy{
+		"email-verification": {TTL: 24 * time.Hour, MaxAttempts: 5, ResendCooldown: time.Minute},
+		"password-reset":     {TTL: 15 * time.Minute, MaxAttempts: 5, ResendCooldown: time.Minute},
+		"email-change":       {TTL: time.Hour, MaxAttempts: 5, ResendCooldown: time.Minute},
+		"magic-link":         {TTL: MagicLinkTTL, MaxAttempts: 3, ResendCooldown: time.Minute},
+		"account-deletion":   {TTL: 15 * time.Minute, MaxAttempts: 5, ResendCooldown: time.Minute},
+	}
+}
+
+// NewVerificationPoliciesFromConfig returns the default policies with the configured TTLs, the
+// ones left at zero keeping their default
+func NewVerificationPoliciesFromConfig(settings config.VerificationConfig) map[string]VerificationPolicy {
+	policies := DefaultVerificationPolicies()
+	ttls := map[string]time.Duration{
+		"email-verification": settings.EmailVerificationTTL,
+		"password-reset":     settings.PasswordResetTTL,
+		"email-change":       settings.EmailChangeTTL,
+		"magic-link":         settings.MagicLinkTTL,
+		"account-deletion":   settings.AccountDeletionTTL,
+	}
+	for codeType, ttl := range ttls {
+		if ttl != 0 {
+			policy := policies[codeType]
+			policy.TTL = ttl
+			policies[codeType] = policy
+		}
+	}
+
+	return policies
+}
 
 type VerificationService struct {
 	verificationPort ports.VerificationPort
+	policies         map[string]VerificationPolicy
+	now              func() time.Time
 }
 
 func NewVerificationService(
 	verificationPort ports.VerificationPort,
+	policies map[string]VerificationPolicy,
+) *VerificationService {
+	return NewVerificationServiceWithClock(verificationPort, policies, time.Now)
+}
 
+// NewVerificationServiceWithClock creates a verification service reading the time from now
+func NewVerificationServiceWithClock(
+	verificationPort ports.VerificationPort,
+	policies map[string]VerificationPolicy,
+	now func() time.Time,
 ) *VerificationService {
 	return &VerificationService{
 		verificationPort: verificationPort,
+		policies:         policies,
+		now:              now,
+	}
+}
+
+func (s VerificationService) policy(codeType string) (VerificationPolicy, error) {
+	policy, ok := s.policies[codeType]
+	if !ok {
+		return VerificationPolicy{}, errors.New("unknown verification type " + codeType)
+	}
+	return policy, nil
+}
+
+// The [GenerateCode] usecase replaces the user's code of the given type with a new one and returns it.
+// A new code can only be requested once the resend cooldown of the previous one has passed.
+func (s VerificationService) GenerateCode(ctx context.Context, payload dto.VerificationDto) (string, error) {
+	policy, err := s.policy(payload.Type)
+	if err != nil {
+		return "", err
+	}
+
+	now := s.now()
+	current, err := s.verificationPort.FindCode(ctx, dto.VerificationDto{UserID: payload.UserID, Type: payload.Type})
+	if err == nil && current != nil {
+		if retryAt := current.CreatedAt.Add(policy.ResendCooldown); retryAt.After(now) {
+			return "", &domain.RetryError{
+				Message:    "a code was sent recently, please wait before requesting a new one",
+				RetryAfter: retryAt.Sub(now),
+			}
+		}
+	}
+
+	code := utils.EncodeToString(6)
+	err = s.verificationPort.CreateCode(ctx, dto.VerificationDto{
+		UserID:    payload.UserID,
+		Code:      code,
+		Type:      payload.Type,
+		ExpiresAt: now.Add(policy.TTL),
+		SentTo:    payload.SentTo,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// The [VerifyCode] usecase checks a code given by the user. Every guess is counted and the code
+// is discarded once it has expired or the maximum number of attempts is reached.
+func (s VerificationService) VerifyCode(ctx context.Context, payload dto.VerificationDto) (*domain.Verifications, error) {
+	policy, err := s.policy(payload.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.UserID == "" {
+		return nil, errInvalidCode
+	}
+
+	verification, err := s.verificationPort.FindCode(ctx, dto.VerificationDto{UserID: payload.UserID, Type: payload.Type})
+	if err != nil {
+		utils.TextLogger.Error("verification code not found", "error", err)
+		return nil, errInvalidCode
+	}
+
+	if verification.ExpiresAt == nil || !verification.ExpiresAt.After(s.now()) {
+		s.discard(ctx, *verification)
+		return nil, errInvalidCode
+	}
+
+	// The attempt is used up before the code is compared, so guesses made at the same time
+	// cannot all pass the check on the count they read
+	allowed, err := s.verificationPort.ConsumeAttempt(ctx, verification.ID, policy.MaxAttempts)
+	if err != nil {
+		utils.TextLogger.Error("unable to record verification attempt", "error", err)
+		return nil, errInvalidCode
+	}
+	if !allowed {
+		s.discard(ctx, *verification)
+		return nil, errInvalidCode
+	}
+
+	if subtle.ConstantTimeCompare([]byte(payload.Code), []byte(verification.Code)) != 1 {
+		utils.TextLogger.Error("verification code mismatch", "user", payload.UserID, "type", payload.Type)
+
+		if verification.Attempts+1 >= policy.MaxAttempts {
+			s.discard(ctx, *verification)
+		}
+		return nil, errInvalidCode
+	}
+
+	return verification, nil
+}
+
+func (s VerificationService) discard(ctx context.Context, verification domain.Verifications) {
+	if err := s.verificationPort.DeleteCode(ctx, verification.ID); err != nil {
+		utils.TextLogger.Error("unable to discard verification code", "error", err)
 	}
 }
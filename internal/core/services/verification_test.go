@@ -0,0 +1,189 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/adapters/database/repository"
+	"github.com/stivo-m/vise-resume/internal/config"
+	"github.com/stivo-m/vise-resume/internal/core/domain"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/ports"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stivo-m/vise-resume/internal/core/test"
+	"github.com/stretchr/testify/assert"
+)
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func setupVerificationService(t *testing.T, policy services.VerificationPolicy) (*services.VerificationService, *testClock, string) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err)
+
+	user, err := repository.NewUserRepository(db).CreateUser(context.Background(), test.GenerateFakeUser())
+	assert.NoError(t, err)
+
+	clock := &testClock{now: time.Now()}
+	service := services.NewVerificationServiceWithClock(
+		repository.NewVerificationRepository(db),
+		map[string]services.VerificationPolicy{"email-verification": policy},
+		clock.Now,
+	)
+
+	return service, clock, user.ID
+}
+
+func TestVerifyCode(t *testing.T) {
+	service, _, userId := setupVerificationService(t, services.VerificationPolicy{TTL: time.Hour, MaxAttempts: 3})
+	ctx := context.Background()
+
+	code, err := service.GenerateCode(ctx, dto.VerificationDto{UserID: userId, Type: "email-verification"})
+	assert.NoError(t, err)
+	assert.Len(t, code, 6)
+
+	verification, err := service.VerifyCode(ctx, dto.VerificationDto{UserID: userId, Code: code, Type: "email-verification"})
+	assert.NoError(t, err)
+	assert.Equal(t, userId, verification.UserId)
+}
+
+func TestVerifyCodeRequiresAUser(t *testing.T) {
+	service, _, userId := setupVerificationService(t, services.VerificationPolicy{TTL: time.Hour, MaxAttempts: 3})
+	ctx := context.Background()
+
+	code, err := service.GenerateCode(ctx, dto.VerificationDto{UserID: userId, Type: "email-verification"})
+	assert.NoError(t, err)
+
+	_, err = service.VerifyCode(ctx, dto.VerificationDto{Code: code, Type: "email-verification"})
+	assert.Error(t, err, "Expected a code to only be checked against its owner")
+}
+
+func TestVerifyCodeExpires(t *testing.T) {
+	service, clock, userId := setupVerificationService(t, services.VerificationPolicy{TTL: time.Hour, MaxAttempts: 3})
+	ctx := context.Background()
+
+	code, err := service.GenerateCode(ctx, dto.VerificationDto{UserID: userId, Type: "email-verification"})
+	assert.NoError(t, err)
+
+	clock.now = clock.now.Add(time.Hour + time.Second)
+	_, err = service.VerifyCode(ctx, dto.VerificationDto{UserID: userId, Code: code, Type: "email-verification"})
+	assert.Error(t, err)
+
+	clock.now = clock.now.Add(-time.Hour)
+	_, err = service.VerifyCode(ctx, dto.VerificationDto{UserID: userId, Code: code, Type: "email-verification"})
+	assert.Error(t, err, "Expected an expired code to be discarded")
+}
+
+func TestVerifyCodeIsDiscardedAfterTooManyAttempts(t *testing.T) {
+	service, _, userId := setupVerificationService(t, services.VerificationPolicy{TTL: time.Hour, MaxAttempts: 3})
+	ctx := context.Background()
+
+	code, err := service.GenerateCode(ctx, dto.VerificationDto{UserID: userId, Type: "email-verification"})
+	assert.NoError(t, err)
+
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err = service.VerifyCode(ctx, dto.VerificationDto{UserID: userId, Code: wrong, Type: "email-verification"})
+		assert.Error(t, err)
+	}
+
+	// The correct code still works before the limit is reached
+	_, err = service.VerifyCode(ctx, dto.VerificationDto{UserID: userId, Code: code, Type: "email-verification"})
+	assert.NoError(t, err)
+
+	_, err = service.VerifyCode(ctx, dto.VerificationDto{UserID: userId, Code: wrong, Type: "email-verification"})
+	assert.Error(t, err)
+
+	_, err = service.VerifyCode(ctx, dto.VerificationDto{UserID: userId, Code: code, Type: "email-verification"})
+	assert.Error(t, err, "Expected the code to be discarded after the last allowed attempt")
+}
+
+// staleVerificationPort keeps returning the code as it was first read, like guesses made at the
+// same time that all read the attempts before any of them is counted
+type staleVerificationPort struct {
+	ports.VerificationPort
+	read *domain.Verifications
+}
+
+func (p *staleVerificationPort) FindCode(ctx context.Context, payload dto.VerificationDto) (*domain.Verifications, error) {
+	if p.read == nil {
+		read, err := p.VerificationPort.FindCode(ctx, payload)
+		if err != nil {
+			return nil, err
+		}
+		p.read = read
+	}
+	verification := *p.read
+	return &verification, nil
+}
+
+func TestVerifyCodeLimitsAttemptsReadAtTheSameTime(t *testing.T) {
+	db, err := database.SetupMockDB()
+	assert.NoError(t, err)
+	user, err := repository.NewUserRepository(db).CreateUser(context.Background(), test.GenerateFakeUser())
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	policies := map[string]services.VerificationPolicy{"email-verification": {TTL: time.Hour, MaxAttempts: 3}}
+	code, err := services.NewVerificationService(repository.NewVerificationRepository(db), policies).
+		GenerateCode(ctx, dto.VerificationDto{UserID: user.ID, Type: "email-verification"})
+	assert.NoError(t, err)
+
+	port := &staleVerificationPort{VerificationPort: repository.NewVerificationRepository(db)}
+	service := services.NewVerificationService(port, policies)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	for i := 0; i < 5; i++ {
+		_, err = service.VerifyCode(ctx, dto.VerificationDto{UserID: user.ID, Code: wrong, Type: "email-verification"})
+		assert.Error(t, err)
+	}
+
+	_, err = service.VerifyCode(ctx, dto.VerificationDto{UserID: user.ID, Code: code, Type: "email-verification"})
+	assert.Error(t, err, "Expected the attempts to be limited even when they all read a count of zero")
+}
+
+func TestVerificationPoliciesFromConfig(t *testing.T) {
+	policies := services.NewVerificationPoliciesFromConfig(config.VerificationConfig{PasswordResetTTL: 30 * time.Minute})
+	defaults := services.DefaultVerificationPolicies()
+
+	assert.Equal(t, 30*time.Minute, policies["password-reset"].TTL)
+	assert.Equal(t, defaults["password-reset"].MaxAttempts, policies["password-reset"].MaxAttempts)
+	assert.Equal(t, defaults["email-verification"], policies["email-verification"], "unset TTLs keep their default")
+	assert.Equal(t, defaults["magic-link"], policies["magic-link"])
+}
+
+func TestGenerateCodeCooldown(t *testing.T) {
+	service, clock, userId := setupVerificationService(t, services.VerificationPolicy{TTL: time.Hour, MaxAttempts: 3, ResendCooldown: time.Minute})
+	ctx := context.Background()
+
+	first, err := service.GenerateCode(ctx, dto.VerificationDto{UserID: userId, Type: "email-verification"})
+	assert.NoError(t, err)
+
+	_, err = service.GenerateCode(ctx, dto.VerificationDto{UserID: userId, Type: "email-verification"})
+	var retryErr *domain.RetryError
+	assert.True(t, errors.As(err, &retryErr), "Expected a retry error during the cooldown")
+	assert.Greater(t, retryErr.RetryAfter, time.Duration(0))
+
+	clock.now = clock.now.Add(time.Minute + time.Second)
+	second, err := service.GenerateCode(ctx, dto.VerificationDto{UserID: userId, Type: "email-verification"})
+	assert.NoError(t, err)
+
+	if first != second {
+		_, err = service.VerifyCode(ctx, dto.VerificationDto{UserID: userId, Code: first, Type: "email-verification"})
+		assert.Error(t, err, "Expected the previous code to be replaced")
+	}
+}
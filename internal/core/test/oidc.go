@@ -0,0 +1,263 @@
+package test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stivo-m/vise-resume/internal/core/dto"
+	"github.com/stivo-m/vise-resume/internal/core/services"
+	"github.com/stivo-m/vise-resume/internal/core/utils"
+)
+
+// OIDCAccount is the account a user signs in with at the stand-in OpenID Connect provider
+type OIDCAccount struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OIDCProvider is a stand-in OpenID Connect provider for tests. It serves discovery, a JWK set,
+// and authorization and token endpoints that check the client and the PKCE code verifier like a
+// real provider, signing users in as Account without asking them anything.
+type OIDCProvider struct {
+	Server       *httptest.Server
+	ClientID     string
+	ClientSecret string
+
+	// Account is who signs in at the provider
+	Account OIDCAccount
+	// TamperClaims changes the claims of ID tokens before they are signed
+	TamperClaims func(claims jwt.MapClaims)
+	// ForgeSignature signs ID tokens with a key the provider does not publish
+	ForgeSignature bool
+
+	mu     sync.Mutex
+	key    services.SigningKey
+	forged services.SigningKey
+	codes  map[string]oidcAuthorization
+}
+
+type oidcAuthorization struct {
+	challenge   string
+	nonce       string
+	redirectURI string
+	account     OIDCAccount
+}
+
+func NewOIDCProvider() (*OIDCProvider, error) {
+	key, err := newRSASigningKey("stand-in")
+	if err != nil {
+		return nil, err
+	}
+
+	forged, err := newRSASigningKey("stand-in")
+	if err != nil {
+		return nil, err
+	}
+
+	provider := &OIDCProvider{
+		ClientID:     "vise-resume",
+		ClientSecret: utils.GenerateSecureToken(16),
+		Account: OIDCAccount{
+			Subject:       "248289761001",
+			Email:         "jane.doe@example.com",
+			EmailVerified: true,
+			Name:          "Jane Doe",
+		},
+		key:    key,
+		forged: forged,
+		codes:  map[string]oidcAuthorization{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", provider.handleDiscovery)
+	mux.HandleFunc("/jwks", provider.handleKeys)
+	mux.HandleFunc("/authorize", provider.handleAuthorize)
+	mux.HandleFunc("/token", provider.handleToken)
+	provider.Server = httptest.NewServer(mux)
+
+	return provider, nil
+}
+
+// Issuer is the address the provider is configured with
+func (p *OIDCProvider) Issuer() string {
+	return p.Server.URL
+}
+
+func (p *OIDCProvider) Close() {
+	p.Server.Close()
+}
+
+// Authorize visits an authorization URL the way a browser would and returns the address the
+// provider sends the user back to
+func (p *OIDCProvider) Authorize(authURL string) (string, error) {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	response, err := client.Get(authURL)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusFound {
+		return "", errors.New("the stand-in provider refused the authorization request: " + response.Status)
+	}
+
+	return response.Header.Get("Location"), nil
+}
+
+func (p *OIDCProvider) handleDiscovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.Issuer(),
+		"authorization_endpoint":                p.Issuer() + "/authorize",
+		"token_endpoint":                        p.Issuer() + "/token",
+		"jwks_uri":                              p.Issuer() + "/jwks",
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (p *OIDCProvider) handleKeys(w http.ResponseWriter, r *http.Request) {
+	jwk, err := p.key.JWK()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, dto.JWKSet{Keys: []dto.JWK{jwk}})
+}
+
+func (p *OIDCProvider) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != p.ClientID || query.Get("response_type") != "code" ||
+		query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.Host == "" {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+
+	code := utils.GenerateSecureToken(16)
+	p.mu.Lock()
+	p.codes[code] = oidcAuthorization{
+		challenge:   query.Get("code_challenge"),
+		nonce:       query.Get("nonce"),
+		redirectURI: query.Get("redirect_uri"),
+		account:     p.Account,
+	}
+	p.mu.Unlock()
+
+	callback := redirectURI.Query()
+	callback.Set("code", code)
+	callback.Set("state", query.Get("state"))
+	redirectURI.RawQuery = callback.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *OIDCProvider) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != p.ClientID || clientSecret != p.ClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	// Codes can only be redeemed once
+	code := r.PostForm.Get("code")
+	p.mu.Lock()
+	authorization, found := p.codes[code]
+	delete(p.codes, code)
+	p.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if r.PostForm.Get("grant_type") != "authorization_code" || !found ||
+		r.PostForm.Get("redirect_uri") != authorization.redirectURI ||
+		base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken, err := p.idToken(authorization)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": utils.GenerateSecureToken(16),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
+
+func (p *OIDCProvider) idToken(authorization oidcAuthorization) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            p.Issuer(),
+		"sub":            authorization.account.Subject,
+		"aud":            p.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+		"nonce":          authorization.nonce,
+		"email":          authorization.account.Email,
+		"email_verified": authorization.account.EmailVerified,
+		"name":           authorization.account.Name,
+	}
+	if p.TamperClaims != nil {
+		p.TamperClaims(claims)
+	}
+
+	key := p.key
+	if p.ForgeSignature {
+		key = p.forged
+	}
+
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
+}
+
+func newRSASigningKey(id string) (services.SigningKey, error) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return services.SigningKey{}, err
+	}
+
+	return services.NewSigningKey(id, private)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -4,7 +4,9 @@ import (
 	"time"
 
 	"github.com/brianvoe/gofakeit/v6"
+	"github.com/google/uuid"
 	"github.com/stivo-m/vise-resume/internal/adapters/database"
+	"github.com/stivo-m/vise-resume/internal/config"
 	"github.com/stivo-m/vise-resume/internal/core/domain"
 	"github.com/stivo-m/vise-resume/internal/core/services"
 )
@@ -23,7 +25,14 @@ func GenerateFakeUser() domain.User {
 
 func GetAuthenticatedTestUser(db *database.DB) (*domain.User, *domain.Token, error) {
 	passwordService := services.NewPasswordService()
-	tokenService := services.NewTokenService()
+	cfg, err := config.Load(config.Options{})
+	if err != nil {
+		return nil, nil, err
+	}
+	tokenService, err := services.NewTokenServiceFromConfig(cfg.Token)
+	if err != nil {
+		return nil, nil, err
+	}
 	userData := GenerateFakeUser()
 	password, _ := passwordService.HashPassword("password")
 	now := time.Now()
@@ -50,6 +59,8 @@ func GetAuthenticatedTestUser(db *database.DB) (*domain.User, *domain.Token, err
 	token := domain.Token{
 		UserId:      payload.ID,
 		AccessToken: tokenString,
+		Type:        "access",
+		FamilyId:    uuid.NewString(),
 	}
 	result = db.Db.Create(&token)
 	if result.Error != nil {
@@ -58,3 +69,32 @@ func GetAuthenticatedTestUser(db *database.DB) (*domain.User, *domain.Token, err
 
 	return &payload, &token, nil
 }
+
+func CreateTestResume(db *database.DB, userId string) (*domain.Resume, error) {
+	resume := domain.Resume{
+		UserId:  userId,
+		Summary: gofakeit.Sentence(10),
+		Skills:  []string{"Go", "SQL"},
+		Experiences: []domain.WorkExperience{
+			{
+				CompanyName: gofakeit.Company(),
+				Role:        gofakeit.JobTitle(),
+				StartDate:   time.Date(2019, 1, 2, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		Education: []domain.Education{
+			{
+				SchoolName: gofakeit.Company(),
+				Course:     "Computer Science",
+				StartDate:  time.Date(2014, 9, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	result := db.Db.Create(&resume)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &resume, nil
+}
@@ -10,6 +10,18 @@ type ContextKey string
 var USER_ID_KEY string = "user_id"
 var ACCESS_TOKEN_KEY string = "access_token"
 
+// TOKEN_SCOPES_KEY holds the scopes of the personal access token a request was
+// authenticated with; it is not set for requests made with a login
+var TOKEN_SCOPES_KEY string = "token_scopes"
+
+// CLIENT_KEY holds the client making a request, a dto.ClientDto, in the context handed to the
+// usecases recording it in the audit log
+var CLIENT_KEY ContextKey = "client"
+
+// PERSONAL_ACCESS_TOKEN_PREFIX starts every personal access token so they are
+// told apart from JWTs and are easy to recognise when they leak
+const PERSONAL_ACCESS_TOKEN_PREFIX = "vrp_"
+
 var TextLogger = slog.New(slog.NewTextHandler(os.Stdout, nil))
 
 // or
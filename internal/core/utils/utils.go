@@ -3,10 +3,12 @@ package utils
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
-	"os"
 	"reflect"
 	"strings"
 
@@ -30,6 +32,22 @@ func EncodeToString(max int) string {
 	return string(b)
 }
 
+// GenerateSecureToken returns a random URL safe token made of size random bytes
+func GenerateSecureToken(size int) string {
+	b := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// HashToken returns the hex encoded SHA-256 digest of a token so it can be stored and compared
+// without keeping the token itself
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
 func FormatApiResponse(message string, data interface{}) dto.ApiResponse[any] {
 	var errorMap dto.ApiResponse[any]
 	errorMap.Message = message
@@ -46,6 +64,10 @@ func GetValidationMessage(err validator.FieldError, field string) string {
 		return "The '" + field + "' field must be at least " + err.Param() + " characters long"
 	case "oneof":
 		return "The '" + field + "' field should be one of " + err.Param()
+	case "gtefield":
+		return "The '" + field + "' field must not be before the " + err.Param() + " field"
+	case "excluded_with":
+		return "The '" + field + "' field cannot be used together with " + err.Param()
 	default:
 		return "The '" + field + "' field is invalid"
 	}
@@ -116,9 +138,8 @@ func generateReadableName(routePath string) string {
 	return strings.Join(parts, " - ") // Join with a dash for readability
 }
 
-func GeneratePostmanCollection(app *fiber.App, port int) dto.PostmanCollection {
+func GeneratePostmanCollection(app *fiber.App, url string, port int) dto.PostmanCollection {
 	routes := app.GetRoutes()
-	url := os.Getenv("SERVER_URL")
 
 	// Create base info for Postman Collection
 	collection := dto.PostmanCollection{
@@ -1,3 +1,3 @@
 #!/bin/bash
 
-go run cmd/main.go migrations:run
\ No newline at end of file
+go run cmd/main.go migrations:up
\ No newline at end of file